@@ -90,6 +90,26 @@ func TestZeroAlloc(t *testing.T) {
 				return err
 			})
 		})
+		t.Run("Seek", func(t *testing.T) {
+			zeroAllocDecStr(t, `{"data": [{"id": 1}, {"attributes": {"name": "foo"}}]}`, func(d *Decoder) error {
+				if err := d.Seek("/data/1/attributes/name"); err != nil {
+					return err
+				}
+				return d.Skip()
+			})
+		})
+		t.Run("Extract", func(t *testing.T) {
+			p, err := CompilePointers("/0/person/id", "/1/company")
+			if err != nil {
+				t.Fatal(err)
+			}
+			skip := func(id int, d *Decoder) error {
+				return d.Skip()
+			}
+			zeroAllocDec(t, benchData, func(d *Decoder) error {
+				return d.Extract(p, skip)
+			})
+		})
 		t.Run("ArrBigFile", func(t *testing.T) {
 			zeroAllocDec(t, benchData, func(d *Decoder) error {
 				return d.Arr(nil)
@@ -115,5 +135,16 @@ func TestZeroAlloc(t *testing.T) {
 		t.Run("Callback", func(t *testing.T) {
 			zeroAllocEnc(t, encodeSmallCallback)
 		})
+		t.Run("RawIndented", func(t *testing.T) {
+			raw := []byte(`{"foo": [1, 2, {"bar": "baz"}]}`)
+			zeroAllocEnc(t, func(e *Encoder) {
+				e.SetIndent(IndentOptions{Indent: "  "})
+				e.ArrStart()
+				if err := e.RawIndented(raw); err != nil {
+					t.Fatal(err)
+				}
+				e.ArrEnd()
+			})
+		})
 	})
 }
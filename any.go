@@ -0,0 +1,483 @@
+package jx
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-faster/errors"
+)
+
+// AnyType is type of Any value.
+type AnyType byte
+
+// Possible types for Any.
+const (
+	AnyInvalid AnyType = iota
+	AnyStr
+	AnyNumber
+	AnyNull
+	AnyObj
+	AnyArr
+	AnyBool
+)
+
+func (t AnyType) String() string {
+	switch t {
+	case AnyInvalid:
+		return "invalid"
+	case AnyStr:
+		return "string"
+	case AnyNumber:
+		return "number"
+	case AnyNull:
+		return "null"
+	case AnyObj:
+		return "object"
+	case AnyArr:
+		return "array"
+	case AnyBool:
+		return "bool"
+	default:
+		return fmt.Sprintf("AnyType(%d)", int(t))
+	}
+}
+
+// Any represents any json value as sum type.
+//
+// Numbers are kept as Num, so they are not rounded. Object fields are kept
+// in Child in input order, including repeated keys.
+type Any struct {
+	Type AnyType // zero value if AnyInvalid, can be AnyNull
+
+	Str    string // AnyStr
+	Bool   bool   // AnyBool
+	Number Num    // AnyNumber
+
+	// Key in object. Valid only if KeyValid.
+	Key string
+	// KeyValid denotes whether Any is element of object.
+	// Needed for representing Key that is blank.
+	//
+	// Can be true only for Child of AnyObj.
+	KeyValid bool
+
+	Child []Any // AnyArr or AnyObj
+}
+
+// Equal reports whether v is semantically equal to b.
+//
+// Numbers are compared by value, so 1, 1.0 and 10e-1 are equal. Fields of
+// objects are compared regardless of order. Key of v is compared only if
+// v.KeyValid is true.
+func (v Any) Equal(b Any) bool {
+	if v.KeyValid && v.Key != b.Key {
+		return false
+	}
+	if v.Type != b.Type {
+		return false
+	}
+	switch v.Type {
+	case AnyNull, AnyInvalid:
+		return true
+	case AnyBool:
+		return v.Bool == b.Bool
+	case AnyStr:
+		return v.Str == b.Str
+	case AnyNumber:
+		return numEqual(v.Number, b.Number)
+	}
+	if len(v.Child) != len(b.Child) {
+		return false
+	}
+	if v.Type == AnyObj {
+		return v.objEqual(b)
+	}
+	for i := range v.Child {
+		if !v.Child[i].Equal(b.Child[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// objEqual reports whether objects with same count of fields are equal.
+func (v Any) objEqual(b Any) bool {
+	for i := range v.Child {
+		c := v.Child[i]
+		// Fast path for same order.
+		if c.Key == b.Child[i].Key && c.Equal(b.Child[i]) {
+			continue
+		}
+		// Repeated keys are matched in order.
+		var n int
+		for j := 0; j < i; j++ {
+			if v.Child[j].Key == c.Key {
+				n++
+			}
+		}
+		found := false
+		for j := range b.Child {
+			if b.Child[j].Key != c.Key {
+				continue
+			}
+			if n > 0 {
+				n--
+				continue
+			}
+			found = c.Equal(b.Child[j])
+			break
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Len returns count of elements of array or fields of object.
+func (v Any) Len() int {
+	return len(v.Child)
+}
+
+// Field returns pointer to value of the first field with given key, or nil
+// if v is not object or there is no such field.
+func (v *Any) Field(key string) *Any {
+	if v.Type != AnyObj {
+		return nil
+	}
+	for i := range v.Child {
+		if c := &v.Child[i]; c.Key == key {
+			return c
+		}
+	}
+	return nil
+}
+
+// Elem returns pointer to i-th element of array, or nil if v is not array or
+// i is out of range.
+func (v *Any) Elem(i int) *Any {
+	if v.Type != AnyArr || i < 0 || i >= len(v.Child) {
+		return nil
+	}
+	return &v.Child[i]
+}
+
+// Set sets value of field with given key, replacing the first existing
+// field or appending a new one.
+//
+// Returns false if v is not object.
+func (v *Any) Set(key string, val Any) bool {
+	if v.Type != AnyObj {
+		return false
+	}
+	val.Key = key
+	val.KeyValid = true
+	if c := v.Field(key); c != nil {
+		*c = val
+		return true
+	}
+	v.Child = append(v.Child, val)
+	return true
+}
+
+// Delete deletes all fields with given key, reporting whether any field was
+// deleted.
+func (v *Any) Delete(key string) bool {
+	if v.Type != AnyObj {
+		return false
+	}
+	n := 0
+	for _, c := range v.Child {
+		if c.Key != key {
+			v.Child[n] = c
+			n++
+		}
+	}
+	deleted := n < len(v.Child)
+	v.Child = v.Child[:n]
+	return deleted
+}
+
+// SetElem sets i-th element of array.
+//
+// Returns false if v is not array or i is out of range.
+func (v *Any) SetElem(i int, val Any) bool {
+	c := v.Elem(i)
+	if c == nil {
+		return false
+	}
+	val.Key = ""
+	val.KeyValid = false
+	*c = val
+	return true
+}
+
+// DeleteElem deletes i-th element of array, shifting following elements.
+//
+// Returns false if v is not array or i is out of range.
+func (v *Any) DeleteElem(i int) bool {
+	if v.Elem(i) == nil {
+		return false
+	}
+	v.Child = append(v.Child[:i], v.Child[i+1:]...)
+	return true
+}
+
+// Append appends element to array.
+//
+// Returns false if v is not array.
+func (v *Any) Append(val Any) bool {
+	if v.Type != AnyArr {
+		return false
+	}
+	val.Key = ""
+	val.KeyValid = false
+	v.Child = append(v.Child, val)
+	return true
+}
+
+// Any reads Any value.
+func (d *Decoder) Any() (Any, error) {
+	var v Any
+	if err := v.Read(d); err != nil {
+		return Any{}, err
+	}
+	return v, nil
+}
+
+// Any encodes Any value.
+func (e *Encoder) Any(a Any) {
+	a.Write(e)
+}
+
+// Read reads json value to v.
+func (v *Any) Read(d *Decoder) error {
+	switch d.Next() {
+	case Invalid:
+		return errors.New("invalid")
+	case Number:
+		n, err := d.NumAppend(nil)
+		if err != nil {
+			return errors.Wrap(err, "number")
+		}
+		v.Number = n
+		v.Type = AnyNumber
+	case String:
+		s, err := d.Str()
+		if err != nil {
+			return errors.Wrap(err, "str")
+		}
+		v.Str = s
+		v.Type = AnyStr
+	case Null:
+		if err := d.Null(); err != nil {
+			return errors.Wrap(err, "null")
+		}
+		v.Type = AnyNull
+	case Bool:
+		b, err := d.Bool()
+		if err != nil {
+			return errors.Wrap(err, "bool")
+		}
+		v.Bool = b
+		v.Type = AnyBool
+	case Object:
+		v.Type = AnyObj
+		if err := d.Obj(func(r *Decoder, s string) error {
+			var elem Any
+			if err := elem.Read(r); err != nil {
+				return errors.Wrap(err, "elem")
+			}
+			elem.Key = s
+			elem.KeyValid = true
+			v.Child = append(v.Child, elem)
+			return nil
+		}); err != nil {
+			return errors.Wrap(err, "obj")
+		}
+		return nil
+	case Array:
+		v.Type = AnyArr
+		if err := d.Arr(func(r *Decoder) error {
+			var elem Any
+			if err := elem.Read(r); err != nil {
+				return errors.Wrap(err, "elem")
+			}
+			v.Child = append(v.Child, elem)
+			return nil
+		}); err != nil {
+			return errors.Wrap(err, "array")
+		}
+		return nil
+	}
+	return nil
+}
+
+// Write json representation of Any to Encoder.
+func (v Any) Write(w *Encoder) {
+	if v.KeyValid {
+		w.FieldStart(v.Key)
+	}
+	switch v.Type {
+	case AnyStr:
+		w.Str(v.Str)
+	case AnyNumber:
+		w.Num(v.Number)
+	case AnyBool:
+		w.Bool(v.Bool)
+	case AnyNull:
+		w.Null()
+	case AnyArr:
+		w.ArrStart()
+		for _, c := range v.Child {
+			c.Write(w)
+		}
+		w.ArrEnd()
+	case AnyObj:
+		w.ObjStart()
+		for _, c := range v.Child {
+			c.Write(w)
+		}
+		w.ObjEnd()
+	}
+}
+
+// String returns human-readable representation of v, which is not json.
+func (v Any) String() string {
+	var b strings.Builder
+	if v.KeyValid {
+		if v.Key == "" {
+			b.WriteString("<blank>")
+		}
+		b.WriteString(v.Key)
+		b.WriteString(": ")
+	}
+	switch v.Type {
+	case AnyStr:
+		b.WriteString(`'` + v.Str + `'`)
+	case AnyNumber:
+		b.WriteString(v.Number.String())
+	case AnyBool:
+		b.WriteString(strconv.FormatBool(v.Bool))
+	case AnyNull:
+		b.WriteString("null")
+	case AnyArr:
+		b.WriteString("[")
+		for i, c := range v.Child {
+			if i != 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(c.String())
+		}
+		b.WriteString("]")
+	case AnyObj:
+		b.WriteString("{")
+		for i, c := range v.Child {
+			if i != 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(c.String())
+		}
+		b.WriteString("}")
+	default:
+		b.WriteString("<invalid>")
+	}
+	return b.String()
+}
+
+// Reset Any value to reuse.
+func (v *Any) Reset() {
+	v.Type = AnyInvalid
+	v.Child = v.Child[:0]
+	v.KeyValid = false
+
+	v.Str = ""
+	v.Key = ""
+}
+
+// Obj calls f for any child that is field if v is AnyObj.
+func (v Any) Obj(f func(k string, v Any)) {
+	if v.Type != AnyObj {
+		return
+	}
+	for _, c := range v.Child {
+		if !c.KeyValid {
+			continue
+		}
+		f(c.Key, c)
+	}
+}
+
+// numEqual reports whether numbers are equal by value.
+//
+// Falls back to comparison of bytes if any number is not valid json number.
+func numEqual(a, b Num) bool {
+	x, okA := parseDecimal(a)
+	y, okB := parseDecimal(b)
+	if !okA || !okB {
+		return a.Equal(b)
+	}
+	return x == y
+}
+
+// decimal is normalized json number 0.digits * 10^exp.
+type decimal struct {
+	neg    bool
+	digits string // without leading and trailing zeroes, empty for zero
+	exp    int
+}
+
+// parseDecimal parses json number n.
+func parseDecimal(n Num) (decimal, bool) {
+	var (
+		s = string(n)
+		r decimal
+	)
+	if strings.HasPrefix(s, "-") {
+		r.neg = true
+		s = s[1:]
+	}
+	mantissa := s
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		mantissa = s[:i]
+		e, err := strconv.Atoi(strings.TrimPrefix(s[i+1:], "+"))
+		if err != nil || e > 1<<30 || e < -1<<30 {
+			return decimal{}, false
+		}
+		r.exp = e
+	}
+	intPart, frac := mantissa, ""
+	if i := strings.IndexByte(mantissa, '.'); i >= 0 {
+		intPart, frac = mantissa[:i], mantissa[i+1:]
+	}
+	if intPart == "" || !isDigits(intPart) || !isDigits(frac) {
+		return decimal{}, false
+	}
+
+	// 0.digits * 10^exp, where digits are intPart+frac without zeroes.
+	digits := strings.TrimLeft(intPart, "0")
+	r.exp += len(digits)
+	if digits == "" {
+		trimmed := strings.TrimLeft(frac, "0")
+		r.exp -= len(frac) - len(trimmed)
+		digits = trimmed
+	} else {
+		digits += frac
+	}
+	r.digits = strings.TrimRight(digits, "0")
+	if r.digits == "" {
+		// Zero, including negative.
+		return decimal{}, true
+	}
+	return r, true
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -3,250 +3,12 @@ package jx
 import (
 	hexEnc "encoding/hex"
 	"encoding/json"
-	"strconv"
-	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
-
-	"github.com/go-faster/errors"
-)
-
-// NB: Any left intentionally unexported
-
-// AnyType is type of Any value.
-type AnyType byte
-
-// Possible types for Any.
-const (
-	AnyInvalid AnyType = iota
-	AnyStr
-	AnyNumber
-	AnyNull
-	AnyObj
-	AnyArr
-	AnyBool
 )
 
-// Any represents any json value as sum type.
-type Any struct {
-	Type AnyType // zero value if AnyInvalid, can be AnyNull
-
-	Str    string // AnyStr
-	Bool   bool   // AnyBool
-	Number Num    // AnyNumber
-
-	// Key in object. Valid only if KeyValid.
-	Key string
-	// KeyValid denotes whether Any is element of object.
-	// Needed for representing Key that is blank.
-	//
-	// Can be true only for Child of AnyObj.
-	KeyValid bool
-
-	Child []Any // AnyArr or AnyObj
-}
-
-// Equal reports whether v is equal to b.
-func (v Any) Equal(b Any) bool {
-	if v.KeyValid && v.Key != b.Key {
-		return false
-	}
-	if v.Type != b.Type {
-		return false
-	}
-	switch v.Type {
-	case AnyNull, AnyInvalid:
-		return true
-	case AnyBool:
-		return v.Bool == b.Bool
-	case AnyStr:
-		return v.Str == b.Str
-	case AnyNumber:
-		return v.Number.Equal(b.Number)
-	}
-	if len(v.Child) != len(b.Child) {
-		return false
-	}
-	for i := range v.Child {
-		if !v.Child[i].Equal(b.Child[i]) {
-			return false
-		}
-	}
-	return true
-}
-
-// Any reads Any value.
-func (d *Decoder) Any() (Any, error) {
-	var v Any
-	if err := v.Read(d); err != nil {
-		return Any{}, err
-	}
-	return v, nil
-}
-
-// Any encodes Any value.
-func (e *Encoder) Any(a Any) {
-	a.Write(e)
-}
-
-func (v *Any) Read(d *Decoder) error {
-	switch d.Next() {
-	case Invalid:
-		return errors.New("invalid")
-	case Number:
-		n, err := d.NumAppend(nil)
-		if err != nil {
-			return errors.Wrap(err, "number")
-		}
-		v.Number = n
-		v.Type = AnyNumber
-	case String:
-		s, err := d.Str()
-		if err != nil {
-			return errors.Wrap(err, "str")
-		}
-		v.Str = s
-		v.Type = AnyStr
-	case Null:
-		if err := d.Null(); err != nil {
-			return errors.Wrap(err, "null")
-		}
-		v.Type = AnyNull
-	case Bool:
-		b, err := d.Bool()
-		if err != nil {
-			return errors.Wrap(err, "bool")
-		}
-		v.Bool = b
-		v.Type = AnyBool
-	case Object:
-		v.Type = AnyObj
-		if err := d.Obj(func(r *Decoder, s string) error {
-			var elem Any
-			if err := elem.Read(r); err != nil {
-				return errors.Wrap(err, "elem")
-			}
-			elem.Key = s
-			elem.KeyValid = true
-			v.Child = append(v.Child, elem)
-			return nil
-		}); err != nil {
-			return errors.Wrap(err, "obj")
-		}
-		return nil
-	case Array:
-		v.Type = AnyArr
-		if err := d.Arr(func(r *Decoder) error {
-			var elem Any
-			if err := elem.Read(r); err != nil {
-				return errors.Wrap(err, "elem")
-			}
-			v.Child = append(v.Child, elem)
-			return nil
-		}); err != nil {
-			return errors.Wrap(err, "array")
-		}
-		return nil
-	}
-	return nil
-}
-
-// Write json representation of Any to Encoder.
-func (v Any) Write(w *Encoder) {
-	if v.KeyValid {
-		w.FieldStart(v.Key)
-	}
-	switch v.Type {
-	case AnyStr:
-		w.Str(v.Str)
-	case AnyNumber:
-		w.Num(v.Number)
-	case AnyBool:
-		w.Bool(v.Bool)
-	case AnyNull:
-		w.Null()
-	case AnyArr:
-		w.ArrStart()
-		for _, c := range v.Child {
-			c.Write(w)
-		}
-		w.ArrEnd()
-	case AnyObj:
-		w.ObjStart()
-		for _, c := range v.Child {
-			c.Write(w)
-		}
-		w.ObjEnd()
-	}
-}
-
-func (v Any) String() string {
-	var b strings.Builder
-	if v.KeyValid {
-		if v.Key == "" {
-			b.WriteString("<blank>")
-		}
-		b.WriteString(v.Key)
-		b.WriteString(": ")
-	}
-	switch v.Type {
-	case AnyStr:
-		b.WriteString(`'` + v.Str + `'`)
-	case AnyNumber:
-		b.WriteString(v.Number.String())
-	case AnyBool:
-		b.WriteString(strconv.FormatBool(v.Bool))
-	case AnyNull:
-		b.WriteString("null")
-	case AnyArr:
-		b.WriteString("[")
-		for i, c := range v.Child {
-			if i != 0 {
-				b.WriteString(", ")
-			}
-			b.WriteString(c.String())
-		}
-		b.WriteString("]")
-	case AnyObj:
-		b.WriteString("{")
-		for i, c := range v.Child {
-			if i != 0 {
-				b.WriteString(", ")
-			}
-			b.WriteString(c.String())
-		}
-		b.WriteString("}")
-	default:
-		b.WriteString("<invalid>")
-	}
-	return b.String()
-}
-
-// Reset Any value to reuse.
-func (v *Any) Reset() {
-	v.Type = AnyInvalid
-	v.Child = v.Child[:0]
-	v.KeyValid = false
-
-	v.Str = ""
-	v.Key = ""
-}
-
-// Obj calls f for any child that is field if v is AnyObj.
-func (v Any) Obj(f func(k string, v Any)) {
-	if v.Type != AnyObj {
-		return
-	}
-	for _, c := range v.Child {
-		if !c.KeyValid {
-			continue
-		}
-		f(c.Key, c)
-	}
-}
-
 func TestAny_Read(t *testing.T) {
 	t.Run("Obj", func(t *testing.T) {
 		var v Any
@@ -379,6 +141,105 @@ func TestAny_Equal(t *testing.T) {
 	})
 }
 
+func TestAny_EqualSemantic(t *testing.T) {
+	mustAny := func(t *testing.T, s string) Any {
+		t.Helper()
+		v, err := DecodeStr(s).Any()
+		require.NoError(t, err)
+		return v
+	}
+	for _, tt := range []struct {
+		a, b  string
+		equal bool
+	}{
+		{`1`, `1.0`, true},
+		{`1`, `10e-1`, true},
+		{`100`, `1E2`, true},
+		{`0`, `-0.0`, true},
+		{`0.0001`, `1e-4`, true},
+		{`123.45`, `12345e-2`, true},
+		{`1`, `2`, false},
+		{`1`, `-1`, false},
+		{`0.1`, `0.01`, false},
+		{`12345678901234567890`, `12345678901234567891`, false},
+		{`{"a":1,"b":[1,2]}`, `{"b":[1.0,2],"a":1}`, true},
+		{`{"a":1,"b":2}`, `{"a":1,"c":2}`, false},
+		{`{"a":1,"a":2}`, `{"a":1,"a":2}`, true},
+		{`{"a":1,"a":2}`, `{"a":2,"a":1}`, false},
+		{`{"a":1,"b":2}`, `{"a":1,"a":1}`, false},
+		{`[1,2]`, `[2,1]`, false},
+		{`"a"`, `"a"`, true},
+		{`"1"`, `1`, false},
+	} {
+		a, b := mustAny(t, tt.a), mustAny(t, tt.b)
+		require.Equal(t, tt.equal, a.Equal(b), "%s == %s", tt.a, tt.b)
+		require.Equal(t, tt.equal, b.Equal(a), "%s == %s", tt.b, tt.a)
+	}
+	t.Run("InvalidNumber", func(t *testing.T) {
+		a := Any{Type: AnyNumber, Number: Num(`"1"`)}
+		require.True(t, a.Equal(Any{Type: AnyNumber, Number: Num(`"1"`)}))
+		require.False(t, a.Equal(Any{Type: AnyNumber, Number: Num(`1`)}))
+	})
+}
+
+func TestAny_Mutate(t *testing.T) {
+	v, err := DecodeStr(`{"a":1,"b":[true,null],"a":2}`).Any()
+	require.NoError(t, err)
+	require.Equal(t, 3, v.Len())
+
+	a := v.Field("a")
+	require.NotNil(t, a)
+	require.Equal(t, "1", a.Number.String())
+	require.Nil(t, v.Field("c"))
+	require.Nil(t, v.Elem(0))
+
+	b := v.Field("b")
+	require.NotNil(t, b)
+	require.Equal(t, AnyBool, b.Elem(0).Type)
+	require.Nil(t, b.Elem(2))
+	require.Nil(t, b.Elem(-1))
+	require.Nil(t, b.Field("a"))
+
+	// Nested mutation through pointer.
+	require.True(t, b.SetElem(1, Any{Type: AnyStr, Str: "x", Key: "k", KeyValid: true}))
+	require.True(t, b.Append(Any{Type: AnyNumber, Number: Num("3")}))
+	require.True(t, b.DeleteElem(0))
+	require.False(t, b.DeleteElem(5))
+	require.False(t, b.SetElem(5, Any{}))
+	require.False(t, b.Set("a", Any{}))
+	require.False(t, b.Delete("a"))
+
+	require.True(t, v.Set("c", Any{Type: AnyNull}))
+	require.True(t, v.Set("a", Any{Type: AnyBool, Bool: true}))
+	require.False(t, v.Append(Any{}))
+
+	e := GetEncoder()
+	e.Any(v)
+	require.Equal(t, `{"a":true,"b":["x",3],"a":2,"c":null}`, e.String())
+
+	require.True(t, v.Delete("a"))
+	require.False(t, v.Delete("a"))
+
+	e.Reset()
+	e.Any(v)
+	require.Equal(t, `{"b":["x",3],"c":null}`, e.String())
+}
+
+func TestAnyType_String(t *testing.T) {
+	for typ, s := range map[AnyType]string{
+		AnyInvalid:  "invalid",
+		AnyStr:      "string",
+		AnyNumber:   "number",
+		AnyNull:     "null",
+		AnyObj:      "object",
+		AnyArr:      "array",
+		AnyBool:     "bool",
+		AnyType(42): "AnyType(42)",
+	} {
+		require.Equal(t, s, typ.String())
+	}
+}
+
 func BenchmarkAny(b *testing.B) {
 	buf := []byte(`[true, null, false, 100, "false"]`)
 	r := GetDecoder()
@@ -1,6 +1,7 @@
 package jx
 
 import (
+	"bytes"
 	"embed"
 	"path"
 	"testing"
@@ -95,6 +96,26 @@ func BenchmarkValid(b *testing.B) {
 	})
 }
 
+func BenchmarkValidReader(b *testing.B) {
+	d := GetDecoder()
+	runTestdata(b.Fatal, func(name string, data []byte) {
+		b.Run(name, func(b *testing.B) {
+			r := bytes.NewReader(data)
+			b.SetBytes(int64(len(data)))
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				r.Reset(data)
+				d.Reset(r)
+				if err := d.Validate(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	})
+}
+
 func encodeSmallObject(e *Encoder) {
 	e.ObjStart()
 	e.FieldStart("data_array")
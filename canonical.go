@@ -0,0 +1,300 @@
+package jx
+
+import (
+	"hash"
+	"sort"
+	"strconv"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/go-faster/errors"
+
+	"github.com/go-faster/jx/internal/byteseq"
+	"github.com/go-faster/jx/internal/scan"
+)
+
+// Canonical reads json value from d and writes its RFC 8785 (JCS) canonical
+// form to w.
+//
+// Keys are sorted by UTF-16 code units, numbers are written as by
+// ECMAScript, strings are minimally escaped and whitespace is removed.
+// Returns error for repeated keys, invalid UTF-8 and numbers out of float64
+// range, which are not allowed by I-JSON.
+//
+// Canonical values of fields of every object are kept in memory to be
+// sorted, arrays are copied by elements.
+func Canonical(d *Decoder, w *Writer) error {
+	return canonical(d, w)
+}
+
+// CanonicalHash writes RFC 8785 canonical form of json value from d to h,
+// returning h.Sum(nil).
+//
+// Output is written to h by chunks, so it is not kept in memory.
+func CanonicalHash(d *Decoder, h hash.Hash) ([]byte, error) {
+	w := Writer{
+		Buf:    make([]byte, 0, encoderBufSize),
+		stream: newStreamState(h),
+	}
+	if err := canonical(d, &w); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+func canonical(d *Decoder, w *Writer) error {
+	var c canonicalizer
+	return c.value(d, w)
+}
+
+// canonicalizer keeps fields of objects being written, by depth.
+type canonicalizer struct {
+	objs  []canonicalObj
+	depth int
+}
+
+// canonicalObj is fields of object being written.
+type canonicalObj struct {
+	buf    []byte // canonical values of fields
+	fields []canonicalField
+}
+
+type canonicalField struct {
+	key        string
+	start, end int // offsets of canonical value in buf
+}
+
+func (c *canonicalizer) value(d *Decoder, w *Writer) error {
+	var fail bool
+	switch d.Next() {
+	case String:
+		s, err := d.StrBytes()
+		if err != nil {
+			return err
+		}
+		if !utf8.Valid(s) {
+			return errors.New("invalid UTF-8 in string")
+		}
+		fail = canonicalStr(w, s)
+	case Number:
+		n, err := d.Num()
+		if err != nil {
+			return err
+		}
+		f, err := strconv.ParseFloat(string(n), 64)
+		if err != nil {
+			return errors.Wrapf(err, "number %s", n)
+		}
+		var buf [32]byte
+		fail = w.Raw(appendCanonicalNum(buf[:0], f))
+	case Null:
+		if err := d.Null(); err != nil {
+			return err
+		}
+		fail = w.Null()
+	case Bool:
+		v, err := d.Bool()
+		if err != nil {
+			return err
+		}
+		fail = w.Bool(v)
+	case Array:
+		if w.ArrStart() {
+			return w.writeErr()
+		}
+		first := true
+		if err := d.Arr(func(d *Decoder) error {
+			if !first && w.Comma() {
+				return w.writeErr()
+			}
+			first = false
+			return c.value(d, w)
+		}); err != nil {
+			return err
+		}
+		fail = w.ArrEnd()
+	case Object:
+		return c.obj(d, w)
+	default:
+		return d.Skip()
+	}
+	if fail {
+		return w.writeErr()
+	}
+	return nil
+}
+
+// obj writes object, sorting fields.
+//
+// Values of fields are written to buffer of current depth first, so every
+// value is decoded once.
+func (c *canonicalizer) obj(d *Decoder, w *Writer) error {
+	level := c.depth
+	if level == len(c.objs) {
+		c.objs = append(c.objs, canonicalObj{})
+	}
+	c.depth++
+	defer func() { c.depth-- }()
+
+	var (
+		fw     = Writer{Buf: c.objs[level].buf[:0]}
+		fields = c.objs[level].fields[:0]
+	)
+	err := d.ObjBytes(func(d *Decoder, key []byte) error {
+		if !utf8.Valid(key) {
+			return errors.New("invalid UTF-8 in key")
+		}
+		start := len(fw.Buf)
+		if err := c.value(d, &fw); err != nil {
+			return errors.Wrapf(err, "%q", key)
+		}
+		fields = append(fields, canonicalField{key: string(key), start: start, end: len(fw.Buf)})
+		return nil
+	})
+	// Keep memory for next object of this depth.
+	c.objs[level].buf, c.objs[level].fields = fw.Buf, fields
+	if err != nil {
+		return err
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		return utf16Less(fields[i].key, fields[j].key)
+	})
+	for i := 1; i < len(fields); i++ {
+		if fields[i].key == fields[i-1].key {
+			return errors.Errorf("duplicate key %q", fields[i].key)
+		}
+	}
+
+	if w.ObjStart() {
+		return w.writeErr()
+	}
+	for i, f := range fields {
+		if i > 0 && w.Comma() {
+			return w.writeErr()
+		}
+		if canonicalStr(w, f.key) || w.byte(':') || w.Raw(fw.Buf[f.start:f.end]) {
+			return w.writeErr()
+		}
+	}
+	if w.ObjEnd() {
+		return w.writeErr()
+	}
+	return nil
+}
+
+// utf16Less reports whether a is less than b, comparing them as UTF-16 code
+// units.
+func utf16Less(a, b string) bool {
+	for a != "" && b != "" {
+		ra, na := utf8.DecodeRuneInString(a)
+		rb, nb := utf8.DecodeRuneInString(b)
+		if ra != rb {
+			ha, la := utf16.EncodeRune(ra)
+			hb, lb := utf16.EncodeRune(rb)
+			if ra < 0x10000 {
+				ha = ra
+			}
+			if rb < 0x10000 {
+				hb = rb
+			}
+			if ha != hb {
+				return ha < hb
+			}
+			return la < lb
+		}
+		a, b = a[na:], b[nb:]
+	}
+	return len(a) < len(b)
+}
+
+// canonicalStr writes string with minimal escaping of RFC 8785.
+func canonicalStr[S byteseq.Byteseq](w *Writer, v S) (fail bool) {
+	fail = w.byte('"')
+	for len(v) > 0 && !fail {
+		i := scan.Str(v)
+		fail = writeStreamByteseq(w, v[:i])
+		if i == len(v) {
+			break
+		}
+		switch c := v[i]; c {
+		case '"', '\\':
+			fail = fail || w.twoBytes('\\', c)
+		case '\b':
+			fail = fail || w.twoBytes('\\', 'b')
+		case '\f':
+			fail = fail || w.twoBytes('\\', 'f')
+		case '\n':
+			fail = fail || w.twoBytes('\\', 'n')
+		case '\r':
+			fail = fail || w.twoBytes('\\', 'r')
+		case '\t':
+			fail = fail || w.twoBytes('\\', 't')
+		default:
+			fail = fail || w.rawStr(`\u00`) || w.twoBytes(hexChars[c>>4], hexChars[c&0xF])
+		}
+		v = v[i+1:]
+	}
+	return fail || w.byte('"')
+}
+
+// appendCanonicalNum appends f formatted as by ECMAScript Number.toString.
+func appendCanonicalNum(b []byte, f float64) []byte {
+	if f == 0 {
+		// Including negative zero.
+		return append(b, '0')
+	}
+	if f < 0 {
+		b = append(b, '-')
+		f = -f
+	}
+	// Shortest digits that round trip, as "d.ddde±dd".
+	var buf, digitsBuf [32]byte
+	s := strconv.AppendFloat(buf[:0], f, 'e', -1, 64)
+	var (
+		digits = digitsBuf[:0]
+		exp    int
+	)
+	for i, c := range s {
+		if c == 'e' {
+			exp, _ = strconv.Atoi(string(s[i+1:]))
+			break
+		}
+		if c != '.' {
+			digits = append(digits, c)
+		}
+	}
+	// Value is 0.digits * 10^n.
+	n, k := exp+1, len(digits)
+	switch {
+	case k <= n && n <= 21:
+		b = append(b, digits...)
+		for i := k; i < n; i++ {
+			b = append(b, '0')
+		}
+	case 0 < n && n <= 21:
+		b = append(b, digits[:n]...)
+		b = append(b, '.')
+		b = append(b, digits[n:]...)
+	case -6 < n && n <= 0:
+		b = append(b, '0', '.')
+		for i := n; i < 0; i++ {
+			b = append(b, '0')
+		}
+		b = append(b, digits...)
+	default:
+		b = append(b, digits[0])
+		if k > 1 {
+			b = append(b, '.')
+			b = append(b, digits[1:]...)
+		}
+		b = append(b, 'e')
+		if n-1 >= 0 {
+			b = append(b, '+')
+		}
+		b = strconv.AppendInt(b, int64(n-1), 10)
+	}
+	return b
+}
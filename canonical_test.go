@@ -0,0 +1,192 @@
+package jx
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonical(t *testing.T) {
+	for _, tt := range []struct {
+		Input  string
+		Expect string
+	}{
+		{
+			// RFC 8785, Section 3.2.2.
+			`{
+  "numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001],
+  "string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/",
+  "literals": [null, true, false]
+}`,
+			`{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],"string":"€$\u000f\nA'B\"\\\\\"/"}`,
+		},
+		{
+			// RFC 8785, Section 3.2.3.
+			`{
+  "\u20ac": "Euro Sign",
+  "\r": "Carriage Return",
+  "\ufb33": "Hebrew Letter Dalet With Dagesh",
+  "1": "One",
+  "\ud83d\ude00": "Emoji: Grinning Face",
+  "\u0080": "Control",
+  "\u00f6": "Latin Small Letter O With Diaeresis"
+}`,
+			"{\"\\r\":\"Carriage Return\",\"1\":\"One\",\"\u0080\":\"Control\",\"ö\":\"Latin Small Letter O With Diaeresis\",\"€\":\"Euro Sign\",\"😀\":\"Emoji: Grinning Face\",\"דּ\":\"Hebrew Letter Dalet With Dagesh\"}",
+		},
+		{`"\b\f\u0001\u001f<>&\u2028"`, "\"\\b\\f\\u0001\\u001f<>&\u2028\""},
+		{`{"b": {"z": [], "a": {}}, "a": [{"y": 1, "x": 2}]}`, `{"a":[{"x":2,"y":1}],"b":{"a":{},"z":[]}}`},
+		{`{"a": 1, "ab": 2, "": 3}`, `{"":3,"a":1,"ab":2}`},
+		{` -0.0 `, `0`},
+		{`[]`, `[]`},
+	} {
+		var w Writer
+		require.NoError(t, Canonical(DecodeStr(tt.Input), &w), tt.Input)
+		require.Equal(t, tt.Expect, w.String())
+	}
+	t.Run("Error", func(t *testing.T) {
+		for _, input := range []string{
+			`{"a": 1, "a": 2}`,
+			`{"a": {"b": 1, "b": 1}}`,
+			`1e400`,
+			`[1, tru]`,
+			`"\xff"`,
+			"{\"\xff\": 1}",
+			``,
+			`]`,
+		} {
+			var w Writer
+			require.Error(t, Canonical(DecodeStr(input), &w), input)
+		}
+	})
+	t.Run("DuplicateKey", func(t *testing.T) {
+		// Object is not written.
+		for input, expect := range map[string]string{
+			`{"b": 1, "a": {"x": 1}, "b": 2}`:         ``,
+			`[1, {"a": [], "a": 2}, 3]`:               `[1,`,
+			`[{"b": {}, "c": {"d": 1, "d": [2]}}, 1]`: `[`,
+		} {
+			var w Writer
+			require.ErrorContains(t, Canonical(DecodeStr(input), &w), "duplicate key", input)
+			require.Equal(t, expect, w.String(), input)
+		}
+	})
+}
+
+func TestCanonicalNum(t *testing.T) {
+	// Samples from RFC 8785, Appendix B.
+	for _, tt := range []struct {
+		Bits   uint64
+		Expect string
+	}{
+		{0x0000000000000000, "0"},
+		{0x8000000000000000, "0"},
+		{0x0000000000000001, "5e-324"},
+		{0x8000000000000001, "-5e-324"},
+		{0x7fefffffffffffff, "1.7976931348623157e+308"},
+		{0xffefffffffffffff, "-1.7976931348623157e+308"},
+		{0x4340000000000000, "9007199254740992"},
+		{0xc340000000000000, "-9007199254740992"},
+		{0x4430000000000000, "295147905179352830000"},
+		{0x44b52d02c7e14af5, "9.999999999999997e+22"},
+		{0x44b52d02c7e14af6, "1e+23"},
+		{0x44b52d02c7e14af7, "1.0000000000000001e+23"},
+		{0x444b1ae4d6e2ef4e, "999999999999999700000"},
+		{0x444b1ae4d6e2ef4f, "999999999999999900000"},
+		{0x444b1ae4d6e2ef50, "1e+21"},
+		{0x3eb0c6f7a0b5ed8c, "9.999999999999997e-7"},
+		{0x3eb0c6f7a0b5ed8d, "0.000001"},
+		{0x41b3de4355555553, "333333333.3333332"},
+		{0x41b3de4355555554, "333333333.33333325"},
+		{0x41b3de4355555555, "333333333.3333333"},
+		{0x41b3de4355555556, "333333333.3333334"},
+		{0x41b3de4355555557, "333333333.33333343"},
+		{0xbecbf647612f3696, "-0.0000033333333333333333"},
+		{0x43143ff3c1cb0959, "1424953923781206.2"},
+	} {
+		f := math.Float64frombits(tt.Bits)
+		require.Equal(t, tt.Expect, string(appendCanonicalNum(nil, f)), "%x", tt.Bits)
+
+		// Number must be read back from json.
+		var w Writer
+		input := strconv.FormatFloat(f, 'g', -1, 64)
+		require.NoError(t, Canonical(DecodeStr(input), &w), input)
+		require.Equal(t, tt.Expect, w.String())
+	}
+}
+
+func TestCanonicalHash(t *testing.T) {
+	input := []byte(`{"b": [1.0, "x"], "a": {"d": null, "c": true}}`)
+
+	var w Writer
+	require.NoError(t, Canonical(DecodeBytes(input), &w))
+	expect := sha256.Sum256(w.Buf)
+
+	t.Run("Buffer", func(t *testing.T) {
+		sum, err := CanonicalHash(DecodeBytes(input), sha256.New())
+		require.NoError(t, err)
+		require.Equal(t, expect[:], sum)
+	})
+	t.Run("Reader", func(t *testing.T) {
+		sum, err := CanonicalHash(Decode(bytes.NewReader(input), 8), sha256.New())
+		require.NoError(t, err)
+		require.Equal(t, expect[:], sum)
+	})
+	t.Run("Large", func(t *testing.T) {
+		data, err := testdata.ReadFile("testdata/twitter.json")
+		require.NoError(t, err)
+
+		var w Writer
+		require.NoError(t, Canonical(DecodeBytes(data), &w))
+		expect := sha256.Sum256(w.Buf)
+
+		sum, err := CanonicalHash(Decode(bytes.NewReader(data), 512), sha256.New())
+		require.NoError(t, err)
+		require.Equal(t, expect[:], sum)
+	})
+}
+
+func BenchmarkCanonical(b *testing.B) {
+	var deep Encoder
+	for i := 0; i < 64; i++ {
+		deep.ObjStart()
+		deep.FieldStart("b")
+		deep.Arr(func(e *Encoder) { e.Int(i) })
+		deep.FieldStart("a")
+	}
+	deep.Null()
+	for i := 0; i < 64; i++ {
+		deep.ObjEnd()
+	}
+	twitter, err := testdata.ReadFile("testdata/twitter.json")
+	if err != nil {
+		b.Fatal(err)
+	}
+	for _, bb := range []struct {
+		Name string
+		Data []byte
+	}{
+		{"Deep", deep.Bytes()},
+		{"Twitter", twitter},
+	} {
+		b.Run(bb.Name, func(b *testing.B) {
+			var (
+				w Writer
+				d Decoder
+			)
+			b.ReportAllocs()
+			b.SetBytes(int64(len(bb.Data)))
+
+			for i := 0; i < b.N; i++ {
+				w.Reset()
+				d.ResetBytes(bb.Data)
+				if err := Canonical(&d, &w); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -87,6 +87,32 @@ type Decoder struct {
 
 	streamOffset int // for reader, offset in stream to start of current buf contents
 	depth        int
+
+	keys    [][]byte     // for reader, decoded keys of objects being skipped, by depth
+	skipped []skippedKey // for reader, raw keys of objects being skipped, by depth
+	keySets []keySet     // keys of objects being decoded, by depth, see DuplicateKeys
+	last    lastKeys     // see DuplicateKeysLast
+	tok     tokenState
+	seek    []seekLevel // containers entered by Seek, see SeekEnd
+
+	pins      int // for reader, count of active pins, see pin
+	pinOffset int // for reader, offset in stream to start of pinned data
+
+	// lastErr and lastSyntaxErr cache SyntaxError lookup, see syntaxErr.
+	lastErr       error
+	lastSyntaxErr *SyntaxError
+
+	lines     int // for reader, count of lines before current buf contents
+	lineStart int // for reader, offset in stream to start of line before current buf contents
+
+	limits  DecoderLimits
+	ext     Extensions
+	utf8    UTF8Policy
+	dup     DuplicateKeys
+	strBuf  []byte // buffer for skipStrUTF8
+	cut     bool   // buf is cut to limits.MaxBytes
+	cutTail int    // tail before cut
+	slow    bool   // fast path of Skip failed, see skipFast
 }
 
 const defaultBuf = 512
@@ -125,6 +151,16 @@ func (d *Decoder) Reset(reader io.Reader) {
 	d.head = 0
 	d.tail = 0
 	d.depth = 0
+	d.streamOffset = 0
+	d.lines = 0
+	d.lineStart = 0
+	d.cut = false
+	d.pins = 0
+	d.skipped = d.skipped[:0]
+	d.last.reset()
+	d.lastErr, d.lastSyntaxErr = nil, nil
+	d.tok.reset()
+	d.seek = d.seek[:0]
 
 	// Reads from reader need buffer.
 	if cap(d.buf) == 0 {
@@ -143,6 +179,17 @@ func (d *Decoder) ResetBytes(input []byte) {
 	d.head = 0
 	d.tail = len(input)
 	d.depth = 0
+	d.streamOffset = 0
+	d.lines = 0
+	d.lineStart = 0
+	d.cut = false
+	d.pins = 0
+	d.skipped = d.skipped[:0]
+	d.last.reset()
+	d.lastErr, d.lastSyntaxErr = nil, nil
+	d.tok.reset()
+	d.seek = d.seek[:0]
 
 	d.buf = input
+	d.applyMaxBytes()
 }
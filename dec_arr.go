@@ -1,9 +1,5 @@
 package jx
 
-import (
-	"github.com/go-faster/errors"
-)
-
 // Elem skips to the start of next array element, returning true boolean
 // if element exists.
 //
@@ -29,14 +25,14 @@ func (d *Decoder) Elem() (ok bool, err error) {
 	case ',':
 		return true, nil
 	default:
-		return false, errors.Wrap(badToken(c, d.offset()), `"[", "," or "]" expected`)
+		return false, expected(d.badToken(c, d.offset()), `"[", "," or "]"`)
 	}
 }
 
 // Arr decodes array and invokes callback on each array element.
 func (d *Decoder) Arr(f func(d *Decoder) error) error {
 	if err := d.consume('['); err != nil {
-		return errors.Wrap(err, `"[" expected`)
+		return expected(err, `"["`)
 	}
 	if f == nil {
 		return d.skipArr()
@@ -46,36 +42,46 @@ func (d *Decoder) Arr(f func(d *Decoder) error) error {
 	}
 	c, err := d.more()
 	if err != nil {
-		return errors.Wrap(err, `value or "]" expected`)
+		return expected(err, `value or "]"`)
 	}
 	if c == ']' {
 		return d.decDepth()
 	}
 	d.unread()
+	if err := d.checkElements(1); err != nil {
+		return err
+	}
 	if err := f(d); err != nil {
-		return errors.Wrap(err, "callback")
+		return d.withIndex(err, 0, "callback")
 	}
 
 	c, err = d.more()
 	if err != nil {
-		return errors.Wrap(err, `"," or "]" expected`)
+		return expected(err, `"," or "]"`)
 	}
-	for c == ',' {
+	for n := 2; c == ','; n++ {
 		// Skip whitespace before reading element.
-		if _, err := d.next(); err != nil {
+		e, err := d.next()
+		if err != nil {
 			return err
 		}
+		if e == ']' && d.ext&ExtTrailingCommas != 0 {
+			return d.decDepth()
+		}
 		d.unread()
+		if err := d.checkElements(n); err != nil {
+			return err
+		}
 		if err := f(d); err != nil {
-			return errors.Wrap(err, "callback")
+			return d.withIndex(err, n-1, "callback")
 		}
 		if c, err = d.next(); err != nil {
 			return err
 		}
 	}
 	if c != ']' {
-		err := badToken(c, d.offset()-1)
-		return errors.Wrap(err, `"]" expected`)
+		err := d.badToken(c, d.offset()-1)
+		return expected(err, `"," or "]"`)
 	}
 	return d.decDepth()
 }
@@ -1,21 +1,18 @@
 package jx
 
-import (
-	"github.com/go-faster/errors"
-)
-
 // ArrIter is decoding array iterator.
 type ArrIter struct {
 	d      *Decoder
 	err    error
 	closed bool
 	comma  bool
+	n      int // count of elements
 }
 
 // ArrIter creates new array iterator.
 func (d *Decoder) ArrIter() (ArrIter, error) {
 	if err := d.consume('['); err != nil {
-		return ArrIter{}, errors.Wrap(err, `"[" expected`)
+		return ArrIter{}, expected(err, `"["`)
 	}
 	if err := d.incDepth(); err != nil {
 		return ArrIter{}, err
@@ -46,13 +43,30 @@ func (i *ArrIter) Next() bool {
 	}
 	if i.comma {
 		if c != ',' {
-			err := badToken(c, dec.offset()-1)
-			i.err = errors.Wrap(err, `"," expected`)
+			err := dec.badToken(c, dec.offset()-1)
+			i.err = expected(err, `","`)
 			return false
 		}
+		if dec.ext&ExtTrailingCommas != 0 {
+			if c, err = dec.more(); err != nil {
+				i.err = err
+				return false
+			}
+			if c == ']' {
+				i.closed = true
+				i.err = dec.decDepth()
+				return false
+			}
+			dec.unread()
+		}
 	} else {
 		dec.unread()
 	}
+	i.n++
+	if err := dec.checkElements(i.n); err != nil {
+		i.err = err
+		return false
+	}
 	i.comma = true
 	return true
 }
@@ -37,7 +37,7 @@ func TestDecoder_ArrIter(t *testing.T) {
 		d := DecodeStr(`[`)
 		// Emulate depth
 		d.depth = maxDepth
-		require.ErrorIs(t, testIter(d), errMaxDepth)
+		requireLimitErr(t, testIter(d), LimitDepth)
 	})
 	t.Run("Empty", func(t *testing.T) {
 		d := DecodeStr(``)
@@ -1,31 +1,86 @@
 package jx
 
-import (
-	"bytes"
-	"io"
-)
-
 // Capture calls f and then rolls back to state before call.
+//
+// If d reads from io.Reader, data read by f is kept in buffer and reused
+// by next reads.
 func (d *Decoder) Capture(f func(d *Decoder) error) error {
 	if f == nil {
 		return nil
 	}
 
-	if d.reader != nil {
-		// TODO(tdakkota): May it be more efficient?
-		var (
-			buf          bytes.Buffer
-			streamOffset = d.streamOffset
-		)
-		reader := io.TeeReader(d.reader, &buf)
-		defer func() {
-			d.reader = io.MultiReader(&buf, d.reader)
-			d.streamOffset = streamOffset
-		}()
-		d.reader = reader
-	}
-	head, tail, depth := d.head, d.tail, d.depth
+	m := d.Mark()
 	err := f(d)
-	d.head, d.tail, d.depth = head, tail, depth
+	d.Rewind(m)
 	return err
 }
+
+// Mark is checkpoint of Decoder, see Decoder.Mark.
+type Mark struct {
+	offset int
+	depth  int
+	keys   int // count of keys of current object, see DuplicateKeys
+
+	// State of Token.
+	want   tokenWant
+	frames int // start of token stack in tokenState.saved
+	n      int // count of token stack frames
+}
+
+// Mark returns checkpoint of current position, which can be used to
+// return to it with Rewind.
+//
+// If d reads from io.Reader, input after mark is kept in buffer until
+// mark is released by Rewind or Release, so every Mark call must be paired
+// with exactly one of them. Marks can be nested.
+//
+// State of Token and keys of current object read for DuplicateKeys are
+// saved too, so Mark can be used between Token calls and fields of object.
+//
+// Mark is invalidated by Reset and ResetBytes.
+func (d *Decoder) Mark() Mark {
+	return Mark{
+		offset: d.pin(),
+		depth:  d.depth,
+		keys:   d.keysLen(),
+		want:   d.tok.want,
+		frames: d.tok.save(),
+		n:      len(d.tok.stack),
+	}
+}
+
+// Rewind returns d to state of m and releases it.
+func (d *Decoder) Rewind(m Mark) {
+	d.unpin()
+	d.head, d.depth = m.offset-d.streamOffset, m.depth
+	d.tok.restore(m.frames, m.n, m.want)
+	if m.keys >= 0 && m.depth > 0 && m.depth <= len(d.keySets) {
+		d.keySets[m.depth-1].truncate(m.keys)
+	}
+}
+
+// Release releases m, keeping current position.
+func (d *Decoder) Release(m Mark) {
+	d.unpin()
+	d.tok.release(m.frames, m.n)
+}
+
+// pin prevents discarding of buffer contents starting from current
+// position until unpin, returning current offset.
+//
+// While pinned, reads append to buffer, growing it if needed.
+func (d *Decoder) pin() int {
+	offset := d.offset()
+	if d.pins == 0 || offset < d.pinOffset {
+		d.pinOffset = offset
+	}
+	d.pins++
+	return offset
+}
+
+// unpin releases pin.
+func (d *Decoder) unpin() {
+	if d.pins > 0 {
+		d.pins--
+	}
+}
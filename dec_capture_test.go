@@ -2,8 +2,13 @@ package jx
 
 import (
 	"bytes"
+	"fmt"
+	"io"
+	"strings"
 	"testing"
+	"testing/iotest"
 
+	"github.com/go-faster/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -161,3 +166,153 @@ func TestDoubleCapture(t *testing.T) {
 		a.NoError(check(d))
 	})(t)
 }
+
+func TestDecoder_CaptureReader(t *testing.T) {
+	const (
+		value = "[\n\"foo\",\n1,\n{\"bar\": true}\n]"
+		input = value + "\n}"
+	)
+	// Buffer is smaller than captured data.
+	d := Decode(iotest.OneByteReader(strings.NewReader(input)), 4)
+	a := require.New(t)
+
+	var captured Raw
+	a.NoError(d.Capture(func(d *Decoder) error {
+		raw, err := d.Raw()
+		captured = append(captured, raw...)
+		return err
+	}))
+	a.Equal(value, captured.String())
+
+	var n int
+	a.NoError(d.Arr(func(d *Decoder) error {
+		n++
+		if err := d.Capture(func(d *Decoder) error {
+			return d.Skip()
+		}); err != nil {
+			return err
+		}
+		return d.Skip()
+	}))
+	a.Equal(3, n)
+
+	// Position is tracked across captures.
+	se, ok := errors.Into[*SyntaxError](d.Skip())
+	a.True(ok)
+	a.Equal(SyntaxError{Token: '}', Offset: len(input) - 1, Line: 6, Column: 1}, *se)
+}
+
+func TestDecoder_CaptureAllocs(t *testing.T) {
+	input := []byte(`{"type": "foo", "foo": "string", "bar": [1, 2, 3]}`)
+	r := bytes.NewReader(input)
+	d := Decode(r, 8)
+	decode := func() {
+		r.Reset(input)
+		d.Reset(r)
+		if err := d.Capture(func(d *Decoder) error {
+			return d.Skip()
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if err := d.Skip(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Grow buffer.
+	decode()
+	require.Zero(t, testing.AllocsPerRun(100, decode))
+}
+
+func TestDecoder_Mark(t *testing.T) {
+	const input = `[1, "foo", {"bar": 2}, null]`
+	t.Run("Fallback", testBufferReader(input, func(t *testing.T, d *Decoder) {
+		a := require.New(t)
+		var (
+			ints []int
+			strs []string
+		)
+		a.NoError(d.Arr(func(d *Decoder) error {
+			// Try to decode as int, falling back to raw string.
+			m := d.Mark()
+			v, err := d.Int()
+			if err == nil {
+				d.Release(m)
+				ints = append(ints, v)
+				return nil
+			}
+			d.Rewind(m)
+			raw, err := d.Raw()
+			if err != nil {
+				return err
+			}
+			strs = append(strs, raw.String())
+			return nil
+		}))
+		a.Equal([]int{1}, ints)
+		a.Equal([]string{`"foo"`, `{"bar": 2}`, `null`}, strs)
+	}))
+	t.Run("Nested", testBufferReader(input, func(t *testing.T, d *Decoder) {
+		a := require.New(t)
+		outer := d.Mark()
+		a.NoError(d.Arr(func(d *Decoder) error {
+			m := d.Mark()
+			if err := d.Skip(); err != nil {
+				return err
+			}
+			d.Rewind(m)
+			return d.Skip()
+		}))
+		d.Rewind(outer)
+
+		inner := d.Mark()
+		a.Equal(Array, d.Next())
+		a.NoError(d.Skip())
+		d.Release(inner)
+
+		a.ErrorIs(d.Skip(), io.EOF)
+	}))
+	t.Run("Token", testBufferReader(`{"a": [1, {"b": 2}], "c": 3}`, func(t *testing.T, d *Decoder) {
+		a := require.New(t)
+		read := func(n int) (tokens []string) {
+			for i := 0; i < n; i++ {
+				tok, raw, err := d.Token()
+				a.NoError(err)
+				tokens = append(tokens, fmt.Sprintf("%s %s", tok, raw))
+			}
+			return tokens
+		}
+		read(3)
+		outer := d.Mark()
+		expect := read(6)
+		// Mark after container is closed.
+		inner := d.Mark()
+		a.Equal([]string{"Key \"c\"", "Number 3", "ObjEnd }"}, read(3))
+		d.Rewind(inner)
+		a.Equal([]string{"Key \"c\""}, read(1))
+		d.Rewind(outer)
+		a.Equal(expect, read(6))
+		a.Equal([]string{"Key \"c\"", "Number 3", "ObjEnd }"}, read(3))
+		a.Empty(d.tok.saved)
+
+		_, _, err := d.Token()
+		a.ErrorIs(err, io.EOF)
+	}))
+	t.Run("Depth", testBufferReader(`[[[1]]]`, func(t *testing.T, d *Decoder) {
+		a := require.New(t)
+		d.SetLimits(DecoderLimits{MaxDepth: 2})
+		m := d.Mark()
+		requireLimitErr(t, d.Skip(), LimitDepth)
+		d.Rewind(m)
+		a.Equal(0, d.depth)
+
+		a.NoError(d.Arr(func(d *Decoder) error {
+			m := d.Mark()
+			requireLimitErr(t, d.Skip(), LimitDepth)
+			d.Rewind(m)
+			a.Equal(1, d.depth)
+
+			d.SetLimits(DecoderLimits{})
+			return d.Skip()
+		}))
+	}))
+}
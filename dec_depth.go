@@ -3,14 +3,22 @@ package jx
 import "github.com/go-faster/errors"
 
 // limit maximum depth of nesting, as allowed by https://tools.ietf.org/html/rfc7159#section-9
+//
+// Can be overridden by DecoderLimits.MaxDepth.
 const maxDepth = 10000
 
-var errMaxDepth = errors.New("depth: maximum")
+// depthLimit returns maximum nesting depth.
+func (d *Decoder) depthLimit() int {
+	if max := d.limits.MaxDepth; max > 0 {
+		return max
+	}
+	return maxDepth
+}
 
 func (d *Decoder) incDepth() error {
 	d.depth++
-	if d.depth > maxDepth {
-		return errMaxDepth
+	if max := d.depthLimit(); d.depth > max {
+		return d.limitErr(LimitDepth, max, d.offset())
 	}
 	return nil
 }
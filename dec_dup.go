@@ -0,0 +1,395 @@
+package jx
+
+import (
+	"fmt"
+	"sort"
+)
+
+// DuplicateKeys configures handling of repeated keys in objects.
+type DuplicateKeys byte
+
+// Duplicate keys policies.
+const (
+	// DuplicateKeysAllow passes every field as is. This is default.
+	DuplicateKeysAllow DuplicateKeys = iota
+	// DuplicateKeysReject returns DuplicateKeyError on repeated key.
+	DuplicateKeysReject
+	// DuplicateKeysFirst passes only the first field with given key,
+	// values of repeated keys are skipped.
+	DuplicateKeysFirst
+	// DuplicateKeysLast passes only the last field with given key, values
+	// of previous ones are skipped.
+	//
+	// Outermost decoded object is scanned twice: to find last keys of it
+	// and of all nested objects, and to decode it.
+	DuplicateKeysLast
+)
+
+func (p DuplicateKeys) String() string {
+	switch p {
+	case DuplicateKeysAllow:
+		return "Allow"
+	case DuplicateKeysReject:
+		return "Reject"
+	case DuplicateKeysFirst:
+		return "First"
+	case DuplicateKeysLast:
+		return "Last"
+	default:
+		return fmt.Sprintf("DuplicateKeys(%d)", int(p))
+	}
+}
+
+// DuplicateKeyError reports repeated object key, see DuplicateKeysReject.
+type DuplicateKeyError struct {
+	// Key is repeated key.
+	Key string
+	// Err is position of repeated key.
+	Err *SyntaxError
+}
+
+func (e *DuplicateKeyError) Error() string {
+	msg := fmt.Sprintf("duplicate key %q at %d", e.Key, e.Err.Offset)
+	if e.Err.Pointer != "" {
+		msg += fmt.Sprintf(" in %q", e.Err.Pointer)
+	}
+	return msg
+}
+
+// Unwrap returns underlying SyntaxError.
+func (e *DuplicateKeyError) Unwrap() error {
+	return e.Err
+}
+
+// SetDuplicateKeys sets handling of repeated keys in objects.
+//
+// Policy is applied by Obj, ObjBytes and ObjIter. Skip and Validate
+// return error only for DuplicateKeysReject.
+//
+// Policy is kept on Reset and ResetBytes and cleared by PutDecoder.
+func (d *Decoder) SetDuplicateKeys(p DuplicateKeys) {
+	d.dup = p
+}
+
+// DuplicateKeys returns current duplicate keys policy.
+func (d *Decoder) DuplicateKeys() DuplicateKeys {
+	return d.dup
+}
+
+// keySetLinear is maximum count of keys in keySet searched linearly.
+const keySetLinear = 32
+
+// keySet is set of object keys with values, see DuplicateKeys.
+//
+// Small sets are searched linearly without allocations.
+type keySet struct {
+	buf  []byte // concatenated keys, in order of addition
+	ends []int  // end of each key in buf
+	vals []int  // value of each key, if m is empty
+	m    map[string]int
+}
+
+// len returns count of keys.
+func (s *keySet) len() int {
+	return len(s.ends)
+}
+
+// truncate removes keys added after first n ones.
+func (s *keySet) truncate(n int) {
+	if n >= len(s.ends) {
+		return
+	}
+	start := 0
+	if n > 0 {
+		start = s.ends[n-1]
+	}
+	if len(s.m) > 0 {
+		from := start
+		for _, end := range s.ends[n:] {
+			delete(s.m, string(s.buf[from:end]))
+			from = end
+		}
+	}
+	s.buf = s.buf[:start]
+	s.ends = s.ends[:n]
+	if n < len(s.vals) {
+		s.vals = s.vals[:n]
+	}
+}
+
+func (s *keySet) reset() {
+	s.buf = s.buf[:0]
+	s.ends = s.ends[:0]
+	s.vals = s.vals[:0]
+	for k := range s.m {
+		delete(s.m, k)
+	}
+}
+
+// get returns value of key.
+func (s *keySet) get(key []byte) (int, bool) {
+	if len(s.m) > 0 {
+		v, ok := s.m[string(key)]
+		return v, ok
+	}
+	start := 0
+	for i, end := range s.ends {
+		if string(s.buf[start:end]) == string(key) {
+			return s.vals[i], true
+		}
+		start = end
+	}
+	return 0, false
+}
+
+// put adds key with value v, returning false if key is already in set.
+//
+// Value of existing key is replaced only if overwrite is true.
+func (s *keySet) put(key []byte, v int, overwrite bool) bool {
+	if len(s.m) == 0 {
+		start := 0
+		for i, end := range s.ends {
+			if string(s.buf[start:end]) == string(key) {
+				if overwrite {
+					s.vals[i] = v
+				}
+				return false
+			}
+			start = end
+		}
+		if len(s.ends) < keySetLinear {
+			s.buf = append(s.buf, key...)
+			s.ends = append(s.ends, len(s.buf))
+			s.vals = append(s.vals, v)
+			return true
+		}
+
+		// Too many keys, switch to map.
+		if s.m == nil {
+			s.m = make(map[string]int, 2*keySetLinear)
+		}
+		start = 0
+		for i, end := range s.ends {
+			s.m[string(s.buf[start:end])] = s.vals[i]
+			start = end
+		}
+	}
+	_, ok := s.m[string(key)]
+	if !ok || overwrite {
+		s.m[string(key)] = v
+	}
+	if !ok {
+		// Keep order for truncate.
+		s.buf = append(s.buf, key...)
+		s.ends = append(s.ends, len(s.buf))
+	}
+	return !ok
+}
+
+// startKeys resets key set of object at current depth.
+func (d *Decoder) startKeys() {
+	for len(d.keySets) < d.depth {
+		d.keySets = append(d.keySets, keySet{})
+	}
+	d.keySets[d.depth-1].reset()
+}
+
+// keysLen returns count of keys of object at current depth, or -1 if there
+// is no such object.
+func (d *Decoder) keysLen() int {
+	if d.depth == 0 || d.depth > len(d.keySets) {
+		return -1
+	}
+	return d.keySets[d.depth-1].len()
+}
+
+// keyOffset returns offset of next key, skipping whitespace.
+func (d *Decoder) keyOffset() int {
+	if _, err := d.next(); err == nil {
+		d.unread()
+	}
+	return d.offset()
+}
+
+// dupKey applies DuplicateKeys policy to n-th key of object at current
+// depth, which starts at offset.
+//
+// Returns true, if value of key was skipped.
+func (d *Decoder) dupKey(key []byte, offset, n int) (bool, error) {
+	switch d.dup {
+	case DuplicateKeysAllow:
+		return false, nil
+	case DuplicateKeysLast:
+		i := d.last.depth[d.depth-1]
+		if i < 0 {
+			return false, nil
+		}
+		if last, _ := d.last.sets[i].keys.get(key); last == n {
+			return false, nil
+		}
+	default:
+		if d.keySets[d.depth-1].put(key, n, false) {
+			return false, nil
+		}
+		if d.dup == DuplicateKeysReject {
+			return false, d.dupKeyErr(key, offset)
+		}
+	}
+	if err := d.Skip(); err != nil {
+		return false, d.withKey(err, key, "skip")
+	}
+	return true, nil
+}
+
+// dupKeyErr returns DuplicateKeyError for key at offset.
+func (d *Decoder) dupKeyErr(key []byte, offset int) error {
+	c := byte('"')
+	if i := offset - d.streamOffset; i >= 0 && i < d.tail {
+		c = d.buf[i]
+	}
+	line, column := d.position(offset)
+	err := &DuplicateKeyError{
+		Key: string(key),
+		Err: &SyntaxError{
+			Token:  c,
+			Offset: offset,
+			Line:   line,
+			Column: column,
+		},
+	}
+	return d.withKey(err, key, "")
+}
+
+// lastKeys are last occurrences of keys in scanned object and its nested
+// objects, see DuplicateKeysLast.
+type lastKeys struct {
+	start, end int      // stream offsets of scanned object
+	sets       lastSets // objects with repeated keys, by offset
+	depth      []int    // index in sets of objects being decoded, -1 if none
+}
+
+func (l *lastKeys) reset() {
+	l.start, l.end = 0, 0
+	l.sets = l.sets[:0]
+}
+
+// save adds keys of object at offset.
+func (l *lastKeys) save(offset int, keys *keySet) {
+	if len(l.sets) < cap(l.sets) {
+		l.sets = l.sets[:len(l.sets)+1]
+	} else {
+		l.sets = append(l.sets, lastSet{})
+	}
+	s := &l.sets[len(l.sets)-1]
+	s.offset = offset
+	s.keys.copyFrom(keys)
+}
+
+// find returns index of object at offset in sets, or -1.
+func (l *lastKeys) find(offset int) int {
+	i := sort.Search(len(l.sets), func(i int) bool {
+		return l.sets[i].offset >= offset
+	})
+	if i < len(l.sets) && l.sets[i].offset == offset {
+		return i
+	}
+	return -1
+}
+
+// lastSet is key set of object with repeated keys.
+type lastSet struct {
+	offset int
+	keys   keySet
+}
+
+type lastSets []lastSet
+
+func (s lastSets) Len() int           { return len(s) }
+func (s lastSets) Less(i, j int) bool { return s[i].offset < s[j].offset }
+func (s lastSets) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+// copyFrom replaces keys of s with keys of src.
+func (s *keySet) copyFrom(src *keySet) {
+	s.reset()
+	if len(src.m) > 0 {
+		if s.m == nil {
+			s.m = make(map[string]int, len(src.m))
+		}
+		for k, v := range src.m {
+			s.m[k] = v
+		}
+		return
+	}
+	s.buf = append(s.buf, src.buf...)
+	s.ends = append(s.ends, src.ends...)
+	s.vals = append(s.vals, src.vals...)
+}
+
+// lastKeys finds last occurrences of keys of upcoming object, see
+// DuplicateKeysLast.
+//
+// Object and its nested objects are scanned once, so nested objects are
+// not scanned again when decoded.
+func (d *Decoder) lastKeys() error {
+	if d.Next() != Object {
+		// Reported by caller.
+		return nil
+	}
+	l := &d.last
+	offset := d.offset()
+	if offset < l.start || offset >= l.end {
+		m := d.Mark()
+		d.dup = DuplicateKeysAllow
+		l.sets = l.sets[:0]
+		err := d.scanLast()
+		l.start, l.end = offset, d.offset()
+		d.dup = DuplicateKeysLast
+		d.Rewind(m)
+		if err != nil {
+			l.reset()
+			return err
+		}
+		if len(l.sets) > 1 {
+			// Nested objects are saved first.
+			sort.Sort(&l.sets)
+		}
+	}
+	for len(l.depth) <= d.depth {
+		l.depth = append(l.depth, -1)
+	}
+	l.depth[d.depth] = l.find(offset)
+	return nil
+}
+
+// scanLast saves last occurrences of keys of objects in upcoming value,
+// if some key is repeated.
+func (d *Decoder) scanLast() error {
+	switch d.Next() {
+	case Object:
+		offset := d.offset()
+		n := 0
+		repeated := false
+		if err := d.ObjBytes(func(d *Decoder, key []byte) error {
+			if n == 0 {
+				d.startKeys()
+			}
+			n++
+			if !d.keySets[d.depth-1].put(key, n, true) {
+				repeated = true
+			}
+			return d.scanLast()
+		}); err != nil {
+			return err
+		}
+		if repeated {
+			d.last.save(offset, &d.keySets[d.depth])
+		}
+		return nil
+	case Array:
+		return d.Arr(func(d *Decoder) error {
+			return d.scanLast()
+		})
+	default:
+		return d.Skip()
+	}
+}
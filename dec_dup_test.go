@@ -0,0 +1,367 @@
+package jx
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-faster/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDuplicateKeys_String(t *testing.T) {
+	for p, s := range map[DuplicateKeys]string{
+		DuplicateKeysAllow:  "Allow",
+		DuplicateKeysReject: "Reject",
+		DuplicateKeysFirst:  "First",
+		DuplicateKeysLast:   "Last",
+		10:                  "DuplicateKeys(10)",
+	} {
+		require.Equal(t, s, p.String())
+	}
+}
+
+func TestDecoder_SetDuplicateKeys(t *testing.T) {
+	d := DecodeStr(`{}`)
+	require.Equal(t, DuplicateKeysAllow, d.DuplicateKeys())
+	d.SetDuplicateKeys(DuplicateKeysReject)
+	require.Equal(t, DuplicateKeysReject, d.DuplicateKeys())
+
+	d.ResetBytes([]byte(`{"a": 1, "a": 2}`))
+	require.Equal(t, DuplicateKeysReject, d.DuplicateKeys())
+	require.Error(t, d.Validate())
+
+	PutDecoder(d)
+	require.Equal(t, DuplicateKeysAllow, d.DuplicateKeys())
+}
+
+// decodeFields decodes object using every object decoding method, returning
+// key=value pairs.
+func decodeFields(t *testing.T, input string, p DuplicateKeys) map[string][]string {
+	result := map[string][]string{}
+	for name, decode := range map[string]func(d *Decoder, f func(d *Decoder, key []byte) error) error{
+		"ObjBytes": (*Decoder).ObjBytes,
+		"Obj": func(d *Decoder, f func(d *Decoder, key []byte) error) error {
+			return d.Obj(func(d *Decoder, key string) error {
+				return f(d, []byte(key))
+			})
+		},
+		"ObjIter": func(d *Decoder, f func(d *Decoder, key []byte) error) error {
+			iter, err := d.ObjIter()
+			if err != nil {
+				return err
+			}
+			for iter.Next() {
+				if err := f(d, iter.Key()); err != nil {
+					return err
+				}
+			}
+			return iter.Err()
+		},
+	} {
+		testBufferReader(input, func(t *testing.T, d *Decoder) {
+			d.SetDuplicateKeys(p)
+			var fields []string
+			err := decode(d, func(d *Decoder, key []byte) error {
+				raw, err := d.Raw()
+				fields = append(fields, fmt.Sprintf("%s=%s", key, raw))
+				return err
+			})
+			if err != nil {
+				fields = append(fields, "error")
+			}
+			result[name+"/"+t.Name()] = fields
+		})(t)
+	}
+	return result
+}
+
+func TestDecoder_DuplicateKeys(t *testing.T) {
+	const input = `{"a": 1, "b": {"a": 2}, "a": 3, "a": 4, "c": 5}`
+	for _, tt := range []struct {
+		Policy DuplicateKeys
+		Fields []string
+	}{
+		{DuplicateKeysAllow, []string{`a=1`, `b={"a": 2}`, `a=3`, `a=4`, `c=5`}},
+		{DuplicateKeysReject, []string{`a=1`, `b={"a": 2}`, `error`}},
+		{DuplicateKeysFirst, []string{`a=1`, `b={"a": 2}`, `c=5`}},
+		{DuplicateKeysLast, []string{`b={"a": 2}`, `a=4`, `c=5`}},
+	} {
+		t.Run(tt.Policy.String(), func(t *testing.T) {
+			for name, fields := range decodeFields(t, input, tt.Policy) {
+				require.Equal(t, tt.Fields, fields, name)
+			}
+		})
+	}
+}
+
+func TestDecoder_DuplicateKeysNested(t *testing.T) {
+	const input = `{"a": {"b": 1, "b": 2}, "a": {"b": 3, "c": 4, "c": 5}}`
+	testBufferReader(input, func(t *testing.T, d *Decoder) {
+		d.SetDuplicateKeys(DuplicateKeysLast)
+		var fields []string
+		require.NoError(t, d.ObjBytes(func(d *Decoder, key []byte) error {
+			return d.ObjBytes(func(d *Decoder, nested []byte) error {
+				v, err := d.Int()
+				fields = append(fields, fmt.Sprintf("%s.%s=%d", key, nested, v))
+				return err
+			})
+		}))
+		require.Equal(t, []string{"a.b=3", "a.c=5"}, fields)
+	})(t)
+	t.Run("First", testBufferReader(input, func(t *testing.T, d *Decoder) {
+		d.SetDuplicateKeys(DuplicateKeysFirst)
+		var fields []string
+		require.NoError(t, d.ObjBytes(func(d *Decoder, key []byte) error {
+			return d.ObjBytes(func(d *Decoder, nested []byte) error {
+				v, err := d.Int()
+				fields = append(fields, fmt.Sprintf("%s.%s=%d", key, nested, v))
+				return err
+			})
+		}))
+		require.Equal(t, []string{"a.b=1"}, fields)
+	}))
+}
+
+func TestDecoder_DuplicateKeysLastDeep(t *testing.T) {
+	const input = `{
+  "a": 1,
+  "x": [{"b": 1, "b": {"c": 1, "c": [{"d": 1, "d": 2}]}}, {"b": 3}],
+  "a": {"d": [{"e": 1, "e": 2}], "d": {"f": 1, "g": 2, "f": 3}},
+  "y": {"z": {"b": 1, "b": 2}}
+}`
+	testBufferReader(input+` {"a": 1, "a": 2}`, func(t *testing.T, d *Decoder) {
+		d.SetDuplicateKeys(DuplicateKeysLast)
+		var e Encoder
+		require.NoError(t, extCompact(&e, d))
+		require.Equal(t, `{"x":[{"b":{"c":[{"d":2}]}},{"b":3}],"a":{"d":{"g":2,"f":3}},"y":{"z":{"b":2}}}`, e.String())
+		// Outermost object is scanned once, only objects with repeated keys
+		// are saved.
+		require.Equal(t, 0, d.last.start)
+		require.Equal(t, len(input), d.last.end)
+		require.Len(t, d.last.sets, 8)
+
+		// Next value is scanned again.
+		e.Reset()
+		require.NoError(t, extCompact(&e, d))
+		require.Equal(t, `{"a":2}`, e.String())
+		require.Equal(t, len(input)+1, d.last.start)
+	})(t)
+	t.Run("Rewind", testBufferReader(input, func(t *testing.T, d *Decoder) {
+		d.SetDuplicateKeys(DuplicateKeysLast)
+		var fields []string
+		require.NoError(t, d.ObjBytes(func(d *Decoder, key []byte) error {
+			if string(key) != "a" {
+				return d.Skip()
+			}
+			m := d.Mark()
+			for i := 0; i < 2; i++ {
+				d.Rewind(m)
+				if err := d.ObjBytes(func(d *Decoder, key []byte) error {
+					fields = append(fields, string(key))
+					return d.Skip()
+				}); err != nil {
+					return err
+				}
+			}
+			return nil
+		}))
+		require.Equal(t, []string{"d", "d"}, fields)
+	}))
+}
+
+func BenchmarkDecoder_DuplicateKeysLastDeep(b *testing.B) {
+	const depth = 64
+	var e Encoder
+	for i := 0; i < depth; i++ {
+		e.ObjStart()
+		e.FieldStart("a")
+		e.Int(i)
+		e.FieldStart("b")
+		e.Str("foo")
+		e.FieldStart("n")
+	}
+	e.Null()
+	for i := 0; i < depth; i++ {
+		e.ObjEnd()
+	}
+	input := e.Bytes()
+
+	var decode func(d *Decoder) error
+	decode = func(d *Decoder) error {
+		if d.Next() != Object {
+			return d.Skip()
+		}
+		return d.ObjBytes(func(d *Decoder, key []byte) error {
+			return decode(d)
+		})
+	}
+	for _, p := range []DuplicateKeys{DuplicateKeysAllow, DuplicateKeysLast} {
+		b.Run(p.String(), func(b *testing.B) {
+			d := DecodeBytes(input)
+			d.SetDuplicateKeys(p)
+			b.ReportAllocs()
+			b.SetBytes(int64(len(input)))
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				d.ResetBytes(input)
+				if err := decode(d); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestDecoder_DuplicateKeysRewind(t *testing.T) {
+	var (
+		e    Encoder
+		keys []string
+	)
+	e.ObjStart()
+	for i := 0; i < 2*keySetLinear; i++ {
+		key := fmt.Sprintf("key%d", i)
+		keys = append(keys, key)
+		e.FieldStart(key)
+		e.Int(i)
+	}
+	e.ObjEnd()
+	for _, tt := range []struct {
+		Name  string
+		Input string
+		Keys  []string
+		Mark  int // index of key before mark
+	}{
+		{"Small", `{"a": 1, "b": 2, "c": 3}`, []string{"a", "b", "c"}, 0},
+		{"Large", e.String(), keys, keySetLinear + 1},
+	} {
+		tt := tt
+		t.Run(tt.Name, func(t *testing.T) {
+			for _, p := range []DuplicateKeys{DuplicateKeysReject, DuplicateKeysFirst} {
+				t.Run(p.String(), testBufferReader(tt.Input, func(t *testing.T, d *Decoder) {
+					d.SetDuplicateKeys(p)
+					iter, err := d.ObjIter()
+					require.NoError(t, err)
+					var keys []string
+					for i := 0; iter.Next(); i++ {
+						keys = append(keys, string(iter.Key()))
+						require.NoError(t, d.Skip())
+						if i != tt.Mark {
+							continue
+						}
+						// Read next keys and return to them.
+						m := d.Mark()
+						for j := 0; j < 2 && iter.Next(); j++ {
+							require.NoError(t, d.Skip())
+						}
+						require.NoError(t, iter.Err())
+						d.Rewind(m)
+					}
+					require.NoError(t, iter.Err())
+					require.Equal(t, tt.Keys, keys)
+				}))
+			}
+		})
+	}
+}
+
+func TestDecoder_DuplicateKeysError(t *testing.T) {
+	const input = "{\"foo\": [1, {\"a\": 1,\n  \"b\": 2, \"a\": 3}]}"
+	check := func(t *testing.T, err error) {
+		t.Helper()
+		var de *DuplicateKeyError
+		require.ErrorAs(t, err, &de)
+		require.Equal(t, "a", de.Key)
+
+		se, ok := errors.Into[*SyntaxError](err)
+		require.True(t, ok)
+		require.Equal(t, SyntaxError{
+			Token:   '"',
+			Offset:  strings.LastIndex(input, `"a"`),
+			Line:    2,
+			Column:  11,
+			Pointer: "/foo/1/a",
+		}, *se)
+		require.Equal(t, `duplicate key "a" at 31 in "/foo/1/a"`, de.Error())
+	}
+	for name, f := range map[string]func(d *Decoder) error{
+		"Validate": (*Decoder).Validate,
+		"Skip":     (*Decoder).Skip,
+		"ObjBytes": func(d *Decoder) error {
+			return d.ObjBytes(func(d *Decoder, key []byte) error {
+				return d.Arr(func(d *Decoder) error {
+					if d.Next() == Object {
+						return d.ObjBytes(func(d *Decoder, key []byte) error {
+							return d.Skip()
+						})
+					}
+					return d.Skip()
+				})
+			})
+		},
+	} {
+		f := f
+		t.Run(name, testBufferReader(input, func(t *testing.T, d *Decoder) {
+			d.SetDuplicateKeys(DuplicateKeysReject)
+			check(t, f(d))
+		}))
+	}
+	for _, p := range []DuplicateKeys{DuplicateKeysAllow, DuplicateKeysFirst, DuplicateKeysLast} {
+		t.Run(p.String(), testBufferReader(input, func(t *testing.T, d *Decoder) {
+			d.SetDuplicateKeys(p)
+			require.NoError(t, d.Validate())
+		}))
+	}
+}
+
+func TestDecoder_DuplicateKeysLarge(t *testing.T) {
+	var e Encoder
+	e.ObjStart()
+	for i := 0; i < 2*keySetLinear; i++ {
+		e.FieldStart(fmt.Sprintf("key%d", i))
+		e.Int(i)
+	}
+	e.FieldStart("key3")
+	e.Int(-1)
+	e.ObjEnd()
+	input := e.String()
+
+	testBufferReader(input, func(t *testing.T, d *Decoder) {
+		d.SetDuplicateKeys(DuplicateKeysReject)
+		var de *DuplicateKeyError
+		require.ErrorAs(t, d.Validate(), &de)
+		require.Equal(t, "key3", de.Key)
+	})(t)
+	t.Run("Last", testBufferReader(input, func(t *testing.T, d *Decoder) {
+		d.SetDuplicateKeys(DuplicateKeysLast)
+		values := map[string]int{}
+		require.NoError(t, d.Obj(func(d *Decoder, key string) error {
+			_, ok := values[key]
+			require.False(t, ok)
+			v, err := d.Int()
+			values[key] = v
+			return err
+		}))
+		require.Len(t, values, 2*keySetLinear)
+		require.Equal(t, -1, values["key3"])
+	}))
+}
+
+func TestDecoder_DuplicateKeysAllocs(t *testing.T) {
+	input := []byte(`{"a": 1, "b": {"c": [1, 2], "d": true}, "e": "f", "a": 2}`)
+	for _, p := range []DuplicateKeys{DuplicateKeysFirst, DuplicateKeysLast} {
+		d := DecodeBytes(input)
+		d.SetDuplicateKeys(p)
+		decode := func() {
+			d.ResetBytes(input)
+			if err := d.ObjBytes(func(d *Decoder, key []byte) error {
+				return d.Skip()
+			}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		decode()
+		require.Zero(t, testing.AllocsPerRun(100, decode), p)
+	}
+}
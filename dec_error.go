@@ -1,17 +1,190 @@
 package jx
 
-import "fmt"
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
 
-// badTokenErr means that Token was unexpected while decoding.
-type badTokenErr struct {
-	Token  byte
+	"github.com/go-faster/errors"
+)
+
+// SyntaxError means that Token was unexpected while decoding.
+type SyntaxError struct {
+	// Token is unexpected byte.
+	Token byte
+	// Offset is offset of Token in input.
 	Offset int
+	// Line is line number of Token, starting from 1.
+	//
+	// Zero if unknown.
+	Line int
+	// Column is column of Token in bytes, starting from 1.
+	//
+	// Zero if unknown.
+	Column int
+	// Pointer is RFC 6901 JSON Pointer to value being decoded, like
+	// "/items/3/price".
+	//
+	// Pointer is relative to the value decoded by outermost Obj, ObjBytes,
+	// Arr or Skip call and is empty for top-level value.
+	Pointer string
+	// Expected is description of expected token, like `","`, if known.
+	Expected string
+}
+
+func (e *SyntaxError) Error() string {
+	msg := fmt.Sprintf("unexpected byte %d %q at ", e.Token, e.Token)
+	if e.Line > 0 {
+		msg += fmt.Sprintf("line %d:%d (offset %d)", e.Line, e.Column, e.Offset)
+	} else {
+		msg += strconv.Itoa(e.Offset)
+	}
+	if e.Pointer != "" {
+		msg += fmt.Sprintf(" in %q", e.Pointer)
+	}
+	if e.Expected != "" {
+		msg += ", expected " + e.Expected
+	}
+	return msg
+}
+
+func (d *Decoder) badToken(c byte, offset int) error {
+	line, column := d.position(offset)
+	return &SyntaxError{
+		Token:  c,
+		Offset: offset,
+		Line:   line,
+		Column: column,
+	}
+}
+
+// position returns line and column of given offset in input.
+//
+// Returns zeroes if offset is not in current buffer or in the line before.
+func (d *Decoder) position(offset int) (line, column int) {
+	idx := offset - d.streamOffset
+	if idx < 0 {
+		if offset < d.lineStart {
+			return 0, 0
+		}
+		// Offset is in the last line of discarded input.
+		return d.lines + 1, offset - d.lineStart + 1
+	}
+	if idx > d.tail {
+		idx = d.tail
+	}
+	buf := d.buf[:idx]
+
+	lineStart := d.lineStart
+	if i := bytes.LastIndexByte(buf, '\n'); i >= 0 {
+		lineStart = d.streamOffset + i + 1
+	}
+	return d.lines + bytes.Count(buf, []byte{'\n'}) + 1, offset - lineStart + 1
+}
+
+// trackLines counts lines in first n bytes of buffer before they are
+// discarded.
+//
+// Column and lines of current buffer are computed by position, only when
+// error is built. Start of line is searched only if there is a line break.
+func (d *Decoder) trackLines(n int) {
+	buf := d.buf[:n]
+	lines := 0
+	if n < 64 {
+		// Short buffer is scanned faster than count is called.
+		for _, c := range buf {
+			if c == '\n' {
+				lines++
+			}
+		}
+	} else {
+		lines = bytes.Count(buf, []byte{'\n'})
+	}
+	if lines > 0 {
+		d.lines += lines
+		d.lineStart = d.streamOffset + bytes.LastIndexByte(buf, '\n') + 1
+	}
+}
+
+// expected adds description of expected token to SyntaxError in err chain,
+// otherwise wraps err with it.
+func expected(err error, what string) error {
+	if se, ok := errors.Into[*SyntaxError](err); ok && se.Expected == "" {
+		se.Expected = what
+		return err
+	}
+	return errors.Wrap(err, what+" expected")
+}
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// syntaxErr returns SyntaxError from err chain or nil.
+//
+// Errors of nested values are passed through every level of nesting, so
+// result of previous lookup is reused to not walk the whole chain on every
+// level.
+func (d *Decoder) syntaxErr(err error) *SyntaxError {
+	if d.lastErr != nil {
+		// Check few levels of wrapping, e.g. by Skip or callback.
+		for e, i := err, 0; e != nil && i < 3; e, i = errors.Unwrap(e), i+1 {
+			if e == d.lastErr {
+				return d.lastSyntaxErr
+			}
+		}
+	}
+	se, _ := errors.Into[*SyntaxError](err)
+	return se
+}
+
+// pathErr wraps err with msg, if msg is not blank, and remembers result for
+// syntaxErr.
+func (d *Decoder) pathErr(err error, se *SyntaxError, msg string) error {
+	if msg != "" {
+		err = errors.Wrap(err, msg)
+	}
+	d.lastErr, d.lastSyntaxErr = err, se
+	return err
+}
+
+// withKey prepends object key to Pointer of SyntaxError in err chain.
+func (d *Decoder) withKey(err error, key []byte, msg string) error {
+	se := d.syntaxErr(err)
+	if se != nil {
+		se.Pointer = "/" + pointerEscaper.Replace(string(key)) + se.Pointer
+	}
+	return d.pathErr(err, se, msg)
+}
+
+// withRawKey is withKey for raw key, as written in input.
+func (d *Decoder) withRawKey(err error, key []byte, msg string) error {
+	if bytes.IndexByte(key, '\\') >= 0 {
+		quoted := append(append([]byte{'"'}, key...), '"')
+		if k, kerr := DecodeBytes(quoted).StrBytes(); kerr == nil {
+			key = k
+		}
+	}
+	return d.withKey(err, key, msg)
 }
 
-func (e *badTokenErr) Error() string {
-	return fmt.Sprintf("unexpected byte %d %q at %d", e.Token, e.Token, e.Offset)
+// withPointer prepends escaped pointer to Pointer of SyntaxError in err
+// chain.
+func (d *Decoder) withPointer(err error, ptr string) error {
+	if ptr == "" {
+		return err
+	}
+	se := d.syntaxErr(err)
+	if se != nil {
+		se.Pointer = ptr + se.Pointer
+	}
+	return d.pathErr(err, se, "")
 }
 
-func badToken(c byte, offset int) error {
-	return &badTokenErr{Token: c, Offset: offset}
+// withIndex prepends array index to Pointer of SyntaxError in err chain.
+func (d *Decoder) withIndex(err error, idx int, msg string) error {
+	se := d.syntaxErr(err)
+	if se != nil {
+		se.Pointer = "/" + strconv.Itoa(idx) + se.Pointer
+	}
+	return d.pathErr(err, se, msg)
 }
@@ -1,16 +1,93 @@
 package jx
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/go-faster/errors"
 	"github.com/stretchr/testify/require"
 )
 
-func Test_badTokenErr_Error(t *testing.T) {
-	e := &badTokenErr{
+func TestSyntaxError_Error(t *testing.T) {
+	e := &SyntaxError{
 		Token:  'c',
 		Offset: 10,
 	}
 	s := error(e).Error()
 	require.Equal(t, "unexpected byte 99 'c' at 10", s)
+
+	e.Pointer = "/items/3"
+	require.Equal(t, `unexpected byte 99 'c' at 10 in "/items/3"`, e.Error())
+
+	e.Line, e.Column = 2, 5
+	e.Expected = `"," or "]"`
+	require.Equal(t, `unexpected byte 99 'c' at line 2:5 (offset 10) in "/items/3", expected "," or "]"`, e.Error())
+
+	t.Run("Decode", testBufferReader("{\n  \"a\": [1 2]\n}", func(t *testing.T, d *Decoder) {
+		require.EqualError(t, d.Skip(),
+			`object: array: unexpected byte 50 '2' at line 2:11 (offset 12) in "/a", expected "," or "]"`,
+		)
+	}))
+}
+
+func TestSyntaxError(t *testing.T) {
+	obj := func(d *Decoder) error {
+		return d.Obj(func(d *Decoder, key string) error {
+			return crawlValue(d)
+		})
+	}
+	objIter := func(d *Decoder) error {
+		iter, err := d.ObjIter()
+		if err != nil {
+			return err
+		}
+		for iter.Next() {
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		}
+		return iter.Err()
+	}
+	for _, tt := range []struct {
+		name     string
+		input    string
+		f        func(d *Decoder) error
+		token    byte
+		offset   int
+		line     int
+		column   int
+		pointer  string
+		expected string
+	}{
+		{"Obj", `{"a": 1 "b": 2}`, obj, '"', 8, 1, 9, "", `"," or "}"`},
+		{"ObjNested", "{\n  \"items\": [\n    1,\n    {\"price\": tru}\n  ]\n}", obj, '}', 39, 4, 18, "/items/1/price", ""},
+		{"ObjEscapedKey", `{"a/b~": [nul]}`, obj, ']', 13, 1, 14, "/a~1b~0/0", ""},
+		{"Arr", `[1, 2 3]`, crawlValue, '3', 6, 1, 7, "", `"," or "]"`},
+		{"ArrNested", "[[1],\n[2, {\"a\" 1}]]", crawlValue, '1', 15, 2, 10, "/1/1", `":"`},
+		{"Skip", "{\"a\": [1, {\"b\": [\"c\", x]}]}", (*Decoder).Skip, 'x', 22, 1, 23, "/a/1/b/1", ""},
+		{"SkipEscapedKey", `{"a\u002fb": [1 2]}`, (*Decoder).Skip, '2', 16, 1, 17, "/a~1b", `"," or "]"`},
+		{"SkipObj", "{\"a\": {\"b\": 1,,}}", (*Decoder).Skip, ',', 14, 1, 15, "/a", `'"'`},
+		{"ObjIter", `{"a": 1, "b" 2}`, objIter, '2', 13, 1, 14, "", `":"`},
+		{"Str", "\n\n \"\x01\"", decoderOnlyError((*Decoder).Str), 0x01, 4, 3, 3, "", ""},
+		{"Num", "\n 01", decoderOnlyError((*Decoder).Int), '1', 3, 2, 3, "", ""},
+		{"Long", "[" + strings.Repeat("1,\n", 300) + "x]", (*Decoder).Skip, 'x', 901, 301, 1, "/300", ""},
+	} {
+		tt := tt
+		t.Run(tt.name, testBufferReader(tt.input, func(t *testing.T, d *Decoder) {
+			err := tt.f(d)
+			se, ok := errors.Into[*SyntaxError](err)
+			require.Truef(t, ok, "expected *SyntaxError, got %v", err)
+
+			require.Equal(t, tt.token, se.Token)
+			require.Equal(t, tt.offset, se.Offset)
+			require.Equal(t, tt.input[tt.offset], se.Token)
+			require.Equal(t, tt.line, se.Line)
+			require.Equal(t, tt.column, se.Column)
+			require.Equal(t, tt.pointer, se.Pointer)
+			require.Equal(t, tt.expected, se.Expected)
+			if tt.pointer != "" {
+				require.True(t, strings.Contains(err.Error(), tt.pointer))
+			}
+		}))
+	}
 }
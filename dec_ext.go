@@ -0,0 +1,549 @@
+package jx
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/go-faster/errors"
+)
+
+// Extensions is set of non-standard syntax extensions accepted by Decoder.
+//
+// Zero value means strict RFC 7159 json.
+type Extensions uint
+
+// Syntax extensions.
+const (
+	// ExtComments allows line "// ..." and block "/* ... */" comments
+	// wherever whitespace is allowed.
+	ExtComments Extensions = 1 << iota
+	// ExtTrailingCommas allows comma after last element of object or array,
+	// like [1, 2,].
+	ExtTrailingCommas
+	// ExtUnquotedKeys allows identifiers as object keys, like {foo: 1}.
+	//
+	// Identifier consists of ASCII letters, digits, "_", "$" and non-ASCII
+	// bytes and does not start with digit. Escapes are not supported.
+	ExtUnquotedKeys
+	// ExtSingleQuotes allows single-quoted strings and keys, like 'foo'.
+	ExtSingleQuotes
+	// ExtEscapes allows JSON5 string escapes: \', \v, \0, \xFF, escaped line
+	// breaks and escaped characters that have no special meaning, like \a.
+	ExtEscapes
+	// ExtHexNumbers allows hexadecimal integers, like 0xFF or -0x1f.
+	ExtHexNumbers
+	// ExtNonFinite allows Infinity, -Infinity and NaN numbers.
+	ExtNonFinite
+	// ExtLooseNumbers allows leading plus sign and leading or trailing
+	// decimal point in numbers, like +1, .5 or 5.
+	ExtLooseNumbers
+)
+
+// Presets of syntax extensions.
+const (
+	// JSONC is json with comments and trailing commas.
+	JSONC = ExtComments | ExtTrailingCommas
+	// JSON5 is https://json5.org.
+	JSON5 = JSONC | ExtUnquotedKeys | ExtSingleQuotes | ExtEscapes |
+		ExtHexNumbers | ExtNonFinite | ExtLooseNumbers
+)
+
+// extNumbers are extensions that require numbers to be read by numberExt.
+//
+// Comments do not change number syntax, so for ExtComments numbers are read
+// by strict path, where '/' ends number, see commentStart.
+const extNumbers = ExtHexNumbers | ExtNonFinite | ExtLooseNumbers
+
+// commentStart reports whether c, which is invalid in number, starts comment
+// after number.
+func (d *Decoder) commentStart(c byte) bool {
+	return c == '/' && d.ext&ExtComments != 0
+}
+
+// SetExtensions sets syntax extensions accepted by d.
+//
+// Extensions are kept on Reset and ResetBytes and cleared by PutDecoder.
+// Raw, Num and Capture return input as is, so they can return values that
+// are not valid json.
+func (d *Decoder) SetExtensions(ext Extensions) {
+	d.ext = ext
+}
+
+// Extensions returns syntax extensions accepted by d.
+func (d *Decoder) Extensions() Extensions {
+	return d.ext
+}
+
+const (
+	identStart byte = 1
+	identPart  byte = 2
+)
+
+var (
+	identSet     [256]byte
+	numberExtSet [256]byte
+)
+
+func init() {
+	for c := 0; c < len(identSet); c++ {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c == '_', c == '$', c >= 0x80:
+			identSet[c] = identStart
+			numberExtSet[c] = 1
+		case c >= '0' && c <= '9':
+			identSet[c] = identPart
+			numberExtSet[c] = 1
+		}
+	}
+	numberExtSet['+'] = 1
+	numberExtSet['-'] = 1
+	numberExtSet['.'] = 1
+}
+
+// typeExt returns Type of value starting with c, that is allowed only by
+// extensions.
+func (d *Decoder) typeExt(c byte) Type {
+	switch {
+	case c == '\'' && d.ext&ExtSingleQuotes != 0:
+		return String
+	case (c == '+' || c == '.') && d.ext&ExtLooseNumbers != 0:
+		return Number
+	case (c == 'I' || c == 'N') && d.ext&ExtNonFinite != 0:
+		return Number
+	default:
+		return Invalid
+	}
+}
+
+// skipExt skips value starting with c, that is allowed only by extensions.
+func (d *Decoder) skipExt(c byte) error {
+	switch d.typeExt(c) {
+	case String:
+		if _, err := d.strQuoted(value{}, c, true); err != nil {
+			return errors.Wrap(err, "str")
+		}
+		return nil
+	case Number:
+		d.unread()
+		_, err := d.numberExt(nil)
+		return err
+	default:
+		return d.badToken(c, d.offset()-1)
+	}
+}
+
+// skipComment skips comment, assuming that "/" was consumed.
+//
+// Returns io.EOF if line comment is ended by end of input.
+func (d *Decoder) skipComment() error {
+	c, err := d.byte()
+	if err != nil {
+		return err
+	}
+	switch c {
+	case '/':
+		for {
+			if i := bytes.IndexByte(d.buf[d.head:d.tail], '\n'); i >= 0 {
+				d.head += i + 1
+				return nil
+			}
+			d.head = d.tail
+			if err := d.read(); err != nil {
+				return err
+			}
+		}
+	case '*':
+		var star bool
+		for {
+			for i, c := range d.buf[d.head:d.tail] {
+				if star && c == '/' {
+					d.head += i + 1
+					return nil
+				}
+				star = c == '*'
+			}
+			d.head = d.tail
+			if err := d.read(); err != nil {
+				if err == io.EOF {
+					err = io.ErrUnexpectedEOF
+				}
+				return err
+			}
+		}
+	default:
+		return expected(d.badToken(c, d.offset()-1), `"/" or "*"`)
+	}
+}
+
+// trailingComma reports whether err of reading element after comma is
+// caused by end of container, consuming it.
+//
+// Always false if ExtTrailingCommas is not set.
+func (d *Decoder) trailingComma(err error, end byte) bool {
+	if d.ext&ExtTrailingCommas == 0 {
+		return false
+	}
+	se, ok := errors.Into[*SyntaxError](err)
+	if !ok || se.Token != end {
+		return false
+	}
+	c, err := d.next()
+	return err == nil && c == end && se.Offset == d.offset()-1
+}
+
+// keyStartExt reports whether c starts object key that is allowed only by
+// extensions.
+func (d *Decoder) keyStartExt(c byte) bool {
+	switch {
+	case c == '\'':
+		return d.ext&ExtSingleQuotes != 0
+	case identSet[c] == identStart:
+		return d.ext&ExtUnquotedKeys != 0
+	default:
+		return false
+	}
+}
+
+// keyExt reports whether err of reading double-quoted key is caused by key
+// that is allowed only by extensions.
+func (d *Decoder) keyExt(err error) bool {
+	se, ok := err.(*SyntaxError)
+	return ok && d.keyStartExt(se.Token)
+}
+
+// key reads object key.
+func (d *Decoder) key(v value) (value, error) {
+	if d.ext&ExtUnquotedKeys != 0 {
+		c, err := d.more()
+		if err != nil {
+			return value{}, err
+		}
+		d.unread()
+		if identSet[c] == identStart {
+			return d.ident(v)
+		}
+	}
+	return d.str(v)
+}
+
+// ident reads identifier, see ExtUnquotedKeys.
+func (d *Decoder) ident(v value) (value, error) {
+	s, copied, err := d.scan(&identSet, v.buf, (*Decoder).checkStrLen)
+	if err != nil {
+		return value{}, err
+	}
+	if d.utf8 != UTF8PassThrough {
+		ident := s
+		if copied {
+			ident = s[len(v.buf):]
+		}
+		if i := invalidUTF8(ident); i >= 0 {
+			if d.utf8 == UTF8Reject {
+				return value{}, d.utf8Err(ident[i], d.offset()-len(ident)+i)
+			}
+			valid := appendValidUTF8(nil, ident)
+			return value{buf: append(v.buf, valid...)}, nil
+		}
+	}
+	switch {
+	case copied:
+		return value{buf: s}, nil
+	case v.raw:
+		return value{buf: s, raw: true}, nil
+	default:
+		return value{buf: append(v.buf, s...)}, nil
+	}
+}
+
+// scan reads bytes from set.
+//
+// Returns sub-slice of d.buf, if bytes are not split between reads, or
+// bytes appended to b otherwise.
+func (d *Decoder) scan(set *[256]byte, b []byte, check func(d *Decoder, start, end int) error) (_ []byte, copied bool, _ error) {
+	var (
+		start = d.offset()
+		head  = d.head
+	)
+	for {
+		i := d.head
+		for i < d.tail && set[d.buf[i]] != 0 {
+			i++
+		}
+		d.head = i
+		if err := check(d, start, d.offset()); err != nil {
+			return nil, false, err
+		}
+		if d.head != d.tail || d.reader == nil {
+			break
+		}
+
+		// Bytes may continue after read, save them.
+		b = append(b, d.buf[head:d.tail]...)
+		head = d.tail
+		copied = true
+		if err := d.read(); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, false, err
+		}
+		head = d.head
+	}
+	if copied {
+		return append(b, d.buf[head:d.head]...), true, nil
+	}
+	return d.buf[head:d.head], false, nil
+}
+
+// strExt reads string that is allowed only by extensions, where err is
+// error of reading double-quoted string.
+func (d *Decoder) strExt(v value, err error) (value, error) {
+	if se, ok := err.(*SyntaxError); !ok || se.Token != '\'' {
+		return value{}, err
+	}
+	if _, err := d.next(); err != nil {
+		return value{}, err
+	}
+	return d.strQuoted(v, '\'', false)
+}
+
+// strQuoted reads string quoted by q, assuming that first quote was
+// consumed.
+//
+// If skip is true, string is only validated.
+func (d *Decoder) strQuoted(v value, q byte, skip bool) (value, error) {
+	v.raw = false
+	start := d.offset()
+	for {
+		c, err := d.byte()
+		if err != nil {
+			return value{}, err
+		}
+		switch {
+		case c == q:
+			return v, d.checkStrLen(start, d.offset()-1)
+		case c == '\\':
+			c, err := d.byte()
+			if err != nil {
+				return value{}, err
+			}
+			e, err := d.escapedChar(value{buf: v.buf}, c)
+			if err != nil {
+				return value{}, errors.Wrap(err, "escape")
+			}
+			if !skip {
+				v.buf = e.buf
+			}
+		case c < ' ':
+			return value{}, d.badToken(c, d.offset()-1)
+		case c >= utf8.RuneSelf && d.utf8 != UTF8PassThrough:
+			e, err := d.strRune(value{buf: v.buf}, c)
+			if err != nil {
+				return value{}, err
+			}
+			if !skip {
+				v.buf = e.buf
+			}
+		default:
+			if !skip {
+				v.buf = append(v.buf, c)
+			}
+		}
+		if err := d.checkStrLen(start, d.offset()); err != nil {
+			return value{}, err
+		}
+	}
+}
+
+// escapedCharExt is escapedChar for escape sequence that is allowed only by
+// extensions.
+func (d *Decoder) escapedCharExt(v value, c byte) (value, error) {
+	switch {
+	case c == '\'' && d.ext&(ExtSingleQuotes|ExtEscapes) != 0:
+		v.buf = append(v.buf, c)
+		return v, nil
+	case d.ext&ExtEscapes == 0, c >= '1' && c <= '9':
+		err := d.badToken(c, d.offset()-1)
+		return v, errors.Wrap(err, "bad escape")
+	}
+	switch c {
+	case 'v':
+		v.buf = append(v.buf, '\v')
+	case '0':
+		v.buf = append(v.buf, 0)
+	case 'x':
+		var r rune
+		for range [2]struct{}{} {
+			h, err := d.byte()
+			if err != nil {
+				return value{}, err
+			}
+			val := hexSet[h]
+			if val == 0 {
+				return value{}, d.badToken(h, d.offset()-1)
+			}
+			r = r*16 + rune(val-1)
+		}
+		v = v.rune(r)
+	case '\r':
+		// Escaped line break, "\r\n" is single line break.
+		if c, err := d.peek(); err == nil && c == '\n' {
+			d.head++
+		}
+	case '\n':
+		// Escaped line break.
+	default:
+		v.buf = append(v.buf, c)
+	}
+	return v, nil
+}
+
+// numberExt reads number, allowing extensions.
+//
+// Returns sub-slice of d.buf, if number is not split between reads, or
+// number appended to b otherwise.
+func (d *Decoder) numberExt(b []byte) ([]byte, error) {
+	start := d.offset()
+	s, _, err := d.scan(&numberExtSet, b, (*Decoder).checkNumLen)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.checkNumberExt(s, start); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// checkNumberExt checks that s is valid number, allowing extensions.
+func (d *Decoder) checkNumberExt(s []byte, offset int) error {
+	i := 0
+	// end returns error for unexpected byte at i or end of number.
+	end := func() error {
+		if i < len(s) {
+			return d.badToken(s[i], offset+i)
+		}
+		return io.ErrUnexpectedEOF
+	}
+	digits := func() (n int) {
+		for ; i < len(s) && s[i] >= '0' && s[i] <= '9'; i++ {
+			n++
+		}
+		return n
+	}
+
+	if i < len(s) && (s[i] == '-' || s[i] == '+' && d.ext&ExtLooseNumbers != 0) {
+		i++
+	}
+	switch rest := string(s[i:]); {
+	case d.ext&ExtNonFinite != 0 && (rest == "Infinity" || rest == "NaN"):
+		return nil
+	case d.ext&ExtHexNumbers != 0 && len(rest) > 1 && rest[0] == '0' && (rest[1] == 'x' || rest[1] == 'X'):
+		i += 2
+		if i == len(s) {
+			return io.ErrUnexpectedEOF
+		}
+		for ; i < len(s); i++ {
+			if hexSet[s[i]] == 0 {
+				return end()
+			}
+		}
+		return nil
+	}
+
+	intStart := i
+	intDigits := digits()
+	if intDigits > 1 && s[intStart] == '0' {
+		return errors.Wrap(d.badToken(s[intStart+1], offset+intStart+1), "leading zero")
+	}
+	if i < len(s) && s[i] == '.' {
+		if intDigits == 0 && d.ext&ExtLooseNumbers == 0 {
+			return end()
+		}
+		i++
+		if digits() == 0 && (intDigits == 0 || d.ext&ExtLooseNumbers == 0) {
+			return end()
+		}
+	} else if intDigits == 0 {
+		return end()
+	}
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		i++
+		if i < len(s) && (s[i] == '-' || s[i] == '+') {
+			i++
+		}
+		if digits() == 0 {
+			return end()
+		}
+	}
+	if i < len(s) {
+		return end()
+	}
+	return nil
+}
+
+// floatExt reads float, allowing extensions.
+func (d *Decoder) floatExt(size int) (float64, error) {
+	if err := d.skipSpace(); err != nil {
+		return 0, err
+	}
+	s, err := d.numberExt(nil)
+	if err != nil {
+		return 0, errors.Wrap(err, "number")
+	}
+
+	neg := s[0] == '-'
+	if s[0] == '-' || s[0] == '+' {
+		s = s[1:]
+	}
+	var v float64
+	switch str := string(s); {
+	case str == "Infinity":
+		v = math.Inf(1)
+	case str == "NaN":
+		v = math.NaN()
+	case len(str) > 1 && (str[1] == 'x' || str[1] == 'X'):
+		u, err := strconv.ParseUint(str[2:], 16, 64)
+		if err != nil {
+			return 0, err
+		}
+		v = float64(u)
+	default:
+		if v, err = strconv.ParseFloat(str, size); err != nil {
+			return 0, err
+		}
+	}
+	if neg {
+		v = -v
+	}
+	return v, nil
+}
+
+// intExt reads integer of given size, allowing extensions.
+func (d *Decoder) intExt(size int) (int64, error) {
+	if err := d.skipSpace(); err != nil {
+		return 0, err
+	}
+	s, err := d.numberExt(nil)
+	if err != nil {
+		return 0, errors.Wrap(err, "number")
+	}
+	return strconv.ParseInt(string(s), 0, size)
+}
+
+// uintExt reads unsigned integer of given size, allowing extensions.
+func (d *Decoder) uintExt(size int) (uint64, error) {
+	if err := d.skipSpace(); err != nil {
+		return 0, err
+	}
+	s, err := d.numberExt(nil)
+	if err != nil {
+		return 0, errors.Wrap(err, "number")
+	}
+	if s[0] == '+' {
+		s = s[1:]
+	}
+	return strconv.ParseUint(string(s), 0, size)
+}
@@ -0,0 +1,440 @@
+package jx
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// extCompact decodes value from d and writes it to e as strict json.
+func extCompact(e *Encoder, d *Decoder) error {
+	switch d.Next() {
+	case Object:
+		e.ObjStart()
+		if err := d.ObjBytes(func(d *Decoder, key []byte) error {
+			e.FieldStart(string(key))
+			return extCompact(e, d)
+		}); err != nil {
+			return err
+		}
+		e.ObjEnd()
+	case Array:
+		e.ArrStart()
+		if err := d.Arr(func(d *Decoder) error {
+			return extCompact(e, d)
+		}); err != nil {
+			return err
+		}
+		e.ArrEnd()
+	case String:
+		s, err := d.Str()
+		if err != nil {
+			return err
+		}
+		e.Str(s)
+	case Number:
+		v, err := d.Float64()
+		if err != nil {
+			return err
+		}
+		e.Float64(v)
+	default:
+		raw, err := d.Raw()
+		if err != nil {
+			return err
+		}
+		e.Raw(raw)
+	}
+	return nil
+}
+
+func TestDecoder_SetExtensions(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		ext      Extensions
+		input    string
+		expected string
+	}{
+		{"LineComment", ExtComments, "// comment\n[1, // one\n2]// end", `[1,2]`},
+		{"BlockComment", ExtComments, `/* a */{/**/"a"/* b */:/* c */1/* d */,"b":[/* e */]}/***/`, `{"a":1,"b":[]}`},
+		{"CommentAfterNumber", ExtComments, `[1/* one */,2// two` + "\n]", `[1,2]`},
+		{"CommentSlashes", ExtComments, `"//" /* "/*" */`, `"//"`},
+		{"TrailingCommaArr", ExtTrailingCommas, `[1, 2, ]`, `[1,2]`},
+		{"TrailingCommaObj", ExtTrailingCommas, `{"a": [1,], "b": {"c": 1,} ,}`, `{"a":[1],"b":{"c":1}}`},
+		{"UnquotedKeys", ExtUnquotedKeys, `{foo: 1, $_Bar9 : 2, "baz": 3, ключ: 4}`, `{"foo":1,"$_Bar9":2,"baz":3,"ключ":4}`},
+		{"SingleQuotes", ExtSingleQuotes, `{'a': 'b"c', "d": '\'\n'}`, `{"a":"b\"c","d":"'\n"}`},
+		{"Escapes", ExtEscapes, `"\'\v\0\x41\a\` + "\n" + `b\` + "\r\n" + `c"`, `"'\u000b\u0000Aabc"`},
+		{"EscapesSingle", ExtSingleQuotes | ExtEscapes, `'\x7e\"'`, `"~\""`},
+		{"HexNumbers", ExtHexNumbers, `[0x1F, -0Xff, 0]`, `[31,-255,0]`},
+		{"LooseNumbers", ExtLooseNumbers, `[+1, .5, 5., -.5e1, +0.5]`, `[1,0.5,5,-5,0.5]`},
+		{"JSONC", JSONC, "{\n  // Comment.\n  \"a\": [1, 2,], /* Block. */\n}\n", `{"a":[1,2]}`},
+		{"JSON5", JSON5, `// JSON5
+{
+  unquoted: 'and you can quote me on that',
+  singleQuotes: 'I can use "double quotes" here',
+  lineBreaks: "Look, Mom! \
+No \\n's!",
+  hexadecimal: 0xdecaf,
+  leadingDecimalPoint: .8675309, andTrailing: 8675309.,
+  positiveSign: +1,
+  trailingComma: 'in objects', andIn: ['arrays',],
+  "backwardsCompatible": "with JSON",
+}`, `{"unquoted":"and you can quote me on that","singleQuotes":"I can use \"double quotes\" here","lineBreaks":"Look, Mom! No \\n's!","hexadecimal":912559,"leadingDecimalPoint":0.8675309,"andTrailing":8675309,"positiveSign":1,"trailingComma":"in objects","andIn":["arrays"],"backwardsCompatible":"with JSON"}`},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Run("Strict", func(t *testing.T) {
+				d := DecodeStr(tt.input)
+				require.Error(t, d.Validate())
+			})
+			t.Run("Decode", testBufferReader(tt.input, func(t *testing.T, d *Decoder) {
+				d.SetExtensions(tt.ext)
+				require.Equal(t, tt.ext, d.Extensions())
+				var e Encoder
+				require.NoError(t, extCompact(&e, d))
+				require.Equal(t, tt.expected, e.String())
+			}))
+			t.Run("Validate", testBufferReader(tt.input, func(t *testing.T, d *Decoder) {
+				d.SetExtensions(tt.ext)
+				require.NoError(t, d.Validate())
+			}))
+			t.Run("Token", testBufferReader(tt.input, func(t *testing.T, d *Decoder) {
+				d.SetExtensions(tt.ext)
+				for {
+					_, _, err := d.Token()
+					if err != nil {
+						require.ErrorIs(t, err, io.EOF)
+						break
+					}
+				}
+			}))
+		})
+	}
+}
+
+func TestDecoder_SetExtensionsInvalid(t *testing.T) {
+	for _, tt := range []struct {
+		ext   Extensions
+		input string
+	}{
+		{ExtComments, `[1] /`},
+		{ExtComments, `[1] /x`},
+		{ExtComments, `[1] /* `},
+		{ExtComments, `[1 // ]`},
+		{ExtComments, `{"a" /* : 1}`},
+		{ExtComments, `[1/]`},
+		{ExtTrailingCommas, `[,]`},
+		{ExtTrailingCommas, `{,}`},
+		{ExtTrailingCommas, `[1,,]`},
+		{ExtTrailingCommas, `{"a":1,,}`},
+		{ExtTrailingCommas, `[1,]]`},
+		{ExtUnquotedKeys, `{1a: 1}`},
+		{ExtUnquotedKeys, `{a b: 1}`},
+		{ExtUnquotedKeys, `{'a': 1}`},
+		{ExtUnquotedKeys, `[a]`},
+		{ExtSingleQuotes, `'a`},
+		{ExtSingleQuotes, `'a\'`},
+		{ExtSingleQuotes, `'\v'`},
+		{ExtSingleQuotes, "'\n'"},
+		{ExtSingleQuotes, `{a: 1}`},
+		{ExtEscapes, `"\1"`},
+		{ExtEscapes, `"\x4"`},
+		{ExtEscapes, `"\xZZ"`},
+		{ExtHexNumbers, `0x`},
+		{ExtHexNumbers, `0xZ`},
+		{ExtHexNumbers, `+0x1`},
+		{ExtHexNumbers, `0x1.5`},
+		{ExtHexNumbers, `Infinity`},
+		{ExtNonFinite, `Infinit`},
+		{ExtNonFinite, `+Infinity`},
+		{ExtNonFinite, `-NaNa`},
+		{ExtNonFinite, `0x1`},
+		{ExtLooseNumbers, `.`},
+		{ExtLooseNumbers, `+`},
+		{ExtLooseNumbers, `++1`},
+		{ExtLooseNumbers, `01`},
+		{ExtLooseNumbers, `1.e`},
+		{ExtLooseNumbers, `1e`},
+		{ExtLooseNumbers, `NaN`},
+		{JSONC, `+1`},
+		{JSONC, `.5`},
+		{JSONC, `5.`},
+		{JSONC, `{a: 1}`},
+		{JSONC, `'a'`},
+		{JSON5, `[1 2]`},
+		{JSON5, `{a}`},
+		{JSON5, `{a: 1 b: 2}`},
+	} {
+		tt := tt
+		t.Run(tt.input, testBufferReader(tt.input, func(t *testing.T, d *Decoder) {
+			d.SetExtensions(tt.ext)
+			require.Error(t, d.Validate())
+		}))
+	}
+}
+
+func TestDecoder_SetExtensionsNumbers(t *testing.T) {
+	t.Run("Float", func(t *testing.T) {
+		for input, expected := range map[string]float64{
+			`Infinity`:  math.Inf(1),
+			`-Infinity`: math.Inf(-1),
+			`+Infinity`: math.Inf(1),
+			`0x10`:      16,
+			`-0x10`:     -16,
+			`+.25`:      0.25,
+			`1.5e1`:     15,
+		} {
+			d := DecodeStr(input)
+			d.SetExtensions(JSON5)
+			v, err := d.Float64()
+			require.NoError(t, err, input)
+			require.Equal(t, expected, v, input)
+
+			d.ResetBytes([]byte(input))
+			v32, err := d.Float32()
+			require.NoError(t, err, input)
+			require.Equal(t, float32(expected), v32, input)
+		}
+
+		d := DecodeStr(`NaN`)
+		d.SetExtensions(JSON5)
+		v, err := d.Float64()
+		require.NoError(t, err)
+		require.True(t, math.IsNaN(v))
+	})
+	t.Run("Int", func(t *testing.T) {
+		d := DecodeStr(`[0x7f, -0x80, +12, 0x80, 1.5, Infinity]`)
+		d.SetExtensions(JSON5)
+		var (
+			values []int8
+			errs   int
+		)
+		require.NoError(t, d.Arr(func(d *Decoder) error {
+			v, err := d.Int8()
+			if err != nil {
+				errs++
+				return nil
+			}
+			values = append(values, v)
+			return nil
+		}))
+		require.Equal(t, []int8{0x7f, -0x80, 12}, values)
+		require.Equal(t, 3, errs)
+	})
+	t.Run("UInt", func(t *testing.T) {
+		d := DecodeStr(`[0xFFFF, +1] `)
+		d.SetExtensions(JSON5)
+		var values []uint16
+		require.NoError(t, d.Arr(func(d *Decoder) error {
+			v, err := d.UInt16()
+			values = append(values, v)
+			return err
+		}))
+		require.Equal(t, []uint16{0xFFFF, 1}, values)
+
+		d = DecodeStr(`-1`)
+		d.SetExtensions(JSON5)
+		_, err := d.UInt()
+		require.Error(t, err)
+	})
+	t.Run("Big", func(t *testing.T) {
+		d := DecodeStr(`0x1F`)
+		d.SetExtensions(JSON5)
+		i, err := d.BigInt()
+		require.NoError(t, err)
+		require.Equal(t, int64(31), i.Int64())
+
+		d.ResetBytes([]byte(`-Infinity`))
+		f, err := d.BigFloat()
+		require.NoError(t, err)
+		require.True(t, f.IsInf())
+		require.Equal(t, -1, f.Sign())
+	})
+	t.Run("Num", func(t *testing.T) {
+		d := DecodeStr(`[0x1F, +1/**/]`)
+		d.SetExtensions(JSON5)
+		var values []string
+		require.NoError(t, d.Arr(func(d *Decoder) error {
+			v, err := d.Num()
+			values = append(values, v.String())
+			return err
+		}))
+		require.Equal(t, []string{`0x1F`, `+1`}, values)
+	})
+	t.Run("Comments", func(t *testing.T) {
+		// Comments do not need extended number syntax.
+		require.Zero(t, ExtComments&extNumbers)
+
+		const input = `[0/**/, 12// a
+, 1.5/**/, -1e3/**/,0.5e-1//
+]`
+		read := map[string]func(d *Decoder) (string, error){
+			"Int": func(d *Decoder) (string, error) {
+				v, err := d.Int()
+				return fmt.Sprint(v), err
+			},
+			"Float64": func(d *Decoder) (string, error) {
+				v, err := d.Float64()
+				return fmt.Sprint(v), err
+			},
+			"BigFloat": func(d *Decoder) (string, error) {
+				v, err := d.BigFloat()
+				if err != nil {
+					return "", err
+				}
+				return v.String(), nil
+			},
+			"Num": func(d *Decoder) (string, error) {
+				v, err := d.Num()
+				return v.String(), err
+			},
+			"Skip": func(d *Decoder) (string, error) {
+				return "", d.Skip()
+			},
+		}
+		expected := map[string][]string{
+			"Int":      {"0", "12"},
+			"Float64":  {"0", "12", "1.5", "-1000", "0.05"},
+			"BigFloat": {"0", "12", "1.5", "-1000", "0.05"},
+			"Num":      {"0", "12", "1.5", "-1e3", "0.5e-1"},
+			"Skip":     {"", "", "", "", ""},
+		}
+		for name, f := range read {
+			f := f
+			t.Run(name, testBufferReader(input, func(t *testing.T, d *Decoder) {
+				d.SetExtensions(ExtComments)
+				var values []string
+				err := d.Arr(func(d *Decoder) error {
+					v, err := f(d)
+					if err != nil {
+						return err
+					}
+					values = append(values, v)
+					return nil
+				})
+				if name == "Int" {
+					require.Error(t, err)
+				} else {
+					require.NoError(t, err)
+				}
+				require.Equal(t, expected[name], values)
+			}))
+		}
+		t.Run("Validate", testBufferReader(input, func(t *testing.T, d *Decoder) {
+			d.SetExtensions(ExtComments)
+			require.NoError(t, d.Validate())
+		}))
+		for _, input := range []string{
+			`1/`,
+			`1./**/`,
+			`1e/**/`,
+			`01/**/`,
+		} {
+			t.Run(input, testBufferReader(input, func(t *testing.T, d *Decoder) {
+				d.SetExtensions(ExtComments)
+				require.Error(t, d.Validate())
+			}))
+		}
+		t.Run("Strict", func(t *testing.T) {
+			_, err := DecodeStr(`1/**/`).Int()
+			require.Error(t, err)
+			require.Error(t, DecodeStr(`[1/**/]`).Validate())
+		})
+	})
+}
+
+func TestDecoder_SetExtensionsIter(t *testing.T) {
+	const input = `{a: [1, 2,], 'b': 3, /* c */}`
+	testBufferReader(input, func(t *testing.T, d *Decoder) {
+		d.SetExtensions(JSON5)
+		obj, err := d.ObjIter()
+		require.NoError(t, err)
+		var keys []string
+		for obj.Next() {
+			keys = append(keys, string(obj.Key()))
+			if d.Next() != Array {
+				require.NoError(t, d.Skip())
+				continue
+			}
+			arr, err := d.ArrIter()
+			require.NoError(t, err)
+			for arr.Next() {
+				require.NoError(t, d.Skip())
+			}
+			require.NoError(t, arr.Err())
+		}
+		require.NoError(t, obj.Err())
+		require.Equal(t, []string{"a", "b"}, keys)
+	})(t)
+}
+
+func TestDecoder_SetExtensionsToken(t *testing.T) {
+	type token struct {
+		Tok Token
+		Raw string
+	}
+	const input = `{a: ['b', .5,], 'c': NaN, /* end */}`
+	testBufferReader(input, func(t *testing.T, d *Decoder) {
+		d.SetExtensions(JSON5)
+		var got []token
+		for {
+			tok, raw, err := d.Token()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			got = append(got, token{tok, string(raw)})
+		}
+		require.Equal(t, []token{
+			{TokenObjStart, `{`},
+			{TokenKey, `a`},
+			{TokenArrStart, `[`},
+			{TokenString, `'b'`},
+			{TokenNumber, `.5`},
+			{TokenArrEnd, `]`},
+			{TokenKey, `'c'`},
+			{TokenNumber, `NaN`},
+			{TokenObjEnd, `}`},
+		}, got)
+	})(t)
+}
+
+func TestDecoder_SetExtensionsLimits(t *testing.T) {
+	for _, input := range []string{
+		`[1, 2,]`,
+		`{a: 1, b: 2,}`,
+	} {
+		input := input
+		t.Run(input, testBufferReader(input, func(t *testing.T, d *Decoder) {
+			d.SetExtensions(JSON5)
+			d.SetLimits(DecoderLimits{MaxElements: 2})
+			require.NoError(t, crawlValue(d))
+		}))
+	}
+	t.Run("StrLen", testBufferReader(`{abcd: 'abcd'}`, func(t *testing.T, d *Decoder) {
+		d.SetExtensions(JSON5)
+		d.SetLimits(DecoderLimits{MaxStrLen: 3})
+		requireLimitErr(t, crawlValue(d), LimitStrLen)
+	}))
+	t.Run("NumLen", testBufferReader(`0x1234`, func(t *testing.T, d *Decoder) {
+		d.SetExtensions(JSON5)
+		d.SetLimits(DecoderLimits{MaxNumLen: 3})
+		requireLimitErr(t, d.Skip(), LimitNumLen)
+	}))
+}
+
+func TestDecoder_SetExtensionsReset(t *testing.T) {
+	d := GetDecoder()
+	d.SetExtensions(JSONC)
+	d.Reset(strings.NewReader(`[1,]`))
+	require.NoError(t, d.Validate())
+
+	PutDecoder(d)
+	d = GetDecoder()
+	defer PutDecoder(d)
+	require.Equal(t, Extensions(0), d.Extensions())
+}
@@ -0,0 +1,51 @@
+package jx
+
+// Extract reads json value, calling f for every value referenced by
+// pointers from p, in single pass.
+//
+// Argument id is index of pointer in CompilePointers. Decoder passed to f is
+// positioned at matched value, which f must consume, e.g. by Raw, Num or
+// Skip. Values not referenced by p are skipped, shared prefixes of pointers
+// are walked once.
+//
+// If matched value also contains values referenced by p, f is called by
+// Capture, so f can not consume it, and then contained values are matched.
+// Repeated keys match every time.
+func (d *Decoder) Extract(p *Pointers, f func(id int, d *Decoder) error) error {
+	return d.extract(&p.root, f)
+}
+
+func (d *Decoder) extract(n *pointerNode, f func(id int, d *Decoder) error) error {
+	if n.id >= 0 {
+		if n.fields == nil {
+			return f(n.id, d)
+		}
+		if err := d.Capture(func(d *Decoder) error {
+			return f(n.id, d)
+		}); err != nil {
+			return err
+		}
+	}
+	switch tt := d.Next(); {
+	case tt == Object && n.fields != nil:
+		return d.ObjBytes(func(d *Decoder, key []byte) error {
+			c, ok := n.fields[string(key)]
+			if !ok {
+				return d.Skip()
+			}
+			return d.extract(c, f)
+		})
+	case tt == Array && n.elems != nil:
+		var i int
+		return d.Arr(func(d *Decoder) error {
+			c, ok := n.elems[i]
+			i++
+			if !ok {
+				return d.Skip()
+			}
+			return d.extract(c, f)
+		})
+	default:
+		return d.Skip()
+	}
+}
@@ -0,0 +1,156 @@
+package jx
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-faster/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecoder_Extract(t *testing.T) {
+	const input = `{
+  "id": 1,
+  "data": [
+    {"name": "foo", "tags": ["a", "b"]},
+    {"name": "bar", "0": "zero"}
+  ],
+  "meta": {"size": 1.50, "a/b": true},
+  "id": 2
+}`
+	p, err := CompilePointers(
+		"/id",
+		"/data/0/name",
+		"/data/0/tags/1",
+		"/data/1",
+		"/data/1/0",
+		"/data/1/name",
+		"/meta/size",
+		"/meta/a~1b",
+		"/missing",
+		"/data/2/name",
+	)
+	require.NoError(t, err)
+
+	t.Run("Raw", testBufferReader(input+` {}`, func(t *testing.T, d *Decoder) {
+		var got []string
+		require.NoError(t, d.Extract(p, func(id int, d *Decoder) error {
+			raw, err := d.Raw()
+			if err != nil {
+				return err
+			}
+			got = append(got, fmt.Sprintf("%d: %s", id, raw))
+			return nil
+		}))
+		require.Equal(t, []string{
+			`0: 1`,
+			`1: "foo"`,
+			`2: "b"`,
+			`3: {"name": "bar", "0": "zero"}`,
+			`5: "bar"`,
+			`4: "zero"`,
+			`6: 1.50`,
+			`7: true`,
+			`0: 2`,
+		}, got)
+		// Value is consumed.
+		require.Equal(t, Object, d.Next())
+		require.Zero(t, d.depth)
+	}))
+	t.Run("Num", testBufferReader(input, func(t *testing.T, d *Decoder) {
+		var sum int
+		require.NoError(t, d.Extract(p, func(id int, d *Decoder) error {
+			if d.Next() != Number {
+				return d.Skip()
+			}
+			n, err := d.Num()
+			if err != nil {
+				return err
+			}
+			v, err := n.Float64()
+			sum += int(v * 10)
+			return err
+		}))
+		require.Equal(t, 45, sum)
+	}))
+	t.Run("Root", testBufferReader(`[1, 2]`, func(t *testing.T, d *Decoder) {
+		p, err := CompilePointers("", "/1")
+		require.NoError(t, err)
+		var got []string
+		require.NoError(t, d.Extract(p, func(id int, d *Decoder) error {
+			raw, err := d.Raw()
+			got = append(got, fmt.Sprintf("%d: %s", id, raw))
+			return err
+		}))
+		require.Equal(t, []string{`0: [1, 2]`, `1: 2`}, got)
+	}))
+	t.Run("Error", func(t *testing.T) {
+		p, err := CompilePointers("/a")
+		require.NoError(t, err)
+
+		errStop := errors.New("stop")
+		d := DecodeStr(`{"a": 1}`)
+		require.ErrorIs(t, d.Extract(p, func(id int, d *Decoder) error {
+			return errStop
+		}), errStop)
+
+		d = DecodeStr(`{"b": [tru], "a": 1}`)
+		var se *SyntaxError
+		require.ErrorAs(t, d.Extract(p, func(id int, d *Decoder) error {
+			return d.Skip()
+		}), &se)
+		require.Equal(t, "/b/0", se.Pointer)
+	})
+}
+
+func BenchmarkDecoder_Extract(b *testing.B) {
+	ptrs := []string{
+		"/statuses/0/id",
+		"/statuses/0/text",
+		"/statuses/0/user/id",
+		"/statuses/0/user/screen_name",
+		"/statuses/0/user/followers_count",
+		"/statuses/1/id",
+		"/statuses/1/user/screen_name",
+		"/search_metadata/count",
+	}
+	data, err := testdata.ReadFile("testdata/twitter.json")
+	require.NoError(b, err)
+
+	b.Run("Extract", func(b *testing.B) {
+		p, err := CompilePointers(ptrs...)
+		require.NoError(b, err)
+		d := DecodeBytes(data)
+		skip := func(id int, d *Decoder) error {
+			return d.Skip()
+		}
+
+		b.ReportAllocs()
+		b.SetBytes(int64(len(data)))
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			d.ResetBytes(data)
+			if err := d.Extract(p, skip); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("Seek", func(b *testing.B) {
+		d := DecodeBytes(data)
+
+		b.ReportAllocs()
+		b.SetBytes(int64(len(data)))
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			for _, ptr := range ptrs {
+				d.ResetBytes(data)
+				if err := d.Seek(ptr); err != nil {
+					b.Fatal(err)
+				}
+				if err := d.Skip(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+}
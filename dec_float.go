@@ -46,6 +46,10 @@ func init() {
 
 // Float32 reads float32 value.
 func (d *Decoder) Float32() (float32, error) {
+	if d.ext&extNumbers != 0 {
+		v, err := d.floatExt(32)
+		return float32(v), err
+	}
 	c, err := d.more()
 	if err != nil {
 		return 0, err
@@ -74,12 +78,12 @@ func (d *Decoder) positiveFloat32() (float32, error) {
 	ind := floatDigits[c]
 	switch ind {
 	case invalidCharForNumber, endOfNumber:
-		return 0, badToken(c, d.offset())
+		return 0, d.badToken(c, d.offset())
 	case dotInNumber, plusInNumber, expInNumber:
-		err := badToken(c, d.offset())
+		err := d.badToken(c, d.offset())
 		return 0, errors.Wrapf(err, "leading %q", c)
 	case minusInNumber: // minus handled by caller
-		err := badToken(c, d.offset())
+		err := d.badToken(c, d.offset())
 		return 0, errors.Wrap(err, "double minus")
 	case 0:
 		if i == d.tail {
@@ -87,7 +91,7 @@ func (d *Decoder) positiveFloat32() (float32, error) {
 		}
 		c = d.buf[i]
 		if floatDigits[c] >= 0 {
-			err := badToken(c, d.offset()+1)
+			err := d.badToken(c, d.offset()+1)
 			return 0, errors.Wrap(err, "leading zero")
 		}
 	}
@@ -99,7 +103,10 @@ NonDecimalLoop:
 		ind := floatDigits[c]
 		switch ind {
 		case invalidCharForNumber:
-			return 0, badToken(c, d.offset()+i)
+			if !d.commentStart(c) {
+				return 0, d.badToken(c, d.offset()+i)
+			}
+			fallthrough
 		case endOfNumber:
 			d.head = i
 			return float32(value), nil
@@ -122,6 +129,11 @@ NonDecimalLoop:
 			c = d.buf[i]
 			ind := floatDigits[c]
 			switch ind {
+			case invalidCharForNumber:
+				if !d.commentStart(c) {
+					return 0, d.badToken(c, d.offset()+i)
+				}
+				fallthrough
 			case endOfNumber:
 				if decimalPlaces > 0 && decimalPlaces < len(pow10) {
 					d.head = i
@@ -131,8 +143,6 @@ NonDecimalLoop:
 				return d.float32Slow()
 			case dotInNumber, expInNumber, plusInNumber, minusInNumber:
 				return d.float32Slow()
-			case invalidCharForNumber:
-				return 0, badToken(c, d.offset()+i)
 			}
 			decimalPlaces++
 			if value > uint64SafeToMultiple10 {
@@ -146,6 +156,9 @@ NonDecimalLoop:
 
 // Float64 read float64
 func (d *Decoder) Float64() (float64, error) {
+	if d.ext&extNumbers != 0 {
+		return d.floatExt(64)
+	}
 	c, err := d.more()
 	if err != nil {
 		return 0, err
@@ -174,12 +187,12 @@ func (d *Decoder) positiveFloat64() (float64, error) {
 	ind := floatDigits[c]
 	switch ind {
 	case invalidCharForNumber, endOfNumber:
-		return 0, badToken(c, d.offset())
+		return 0, d.badToken(c, d.offset())
 	case dotInNumber, plusInNumber, expInNumber:
-		err := badToken(c, d.offset())
+		err := d.badToken(c, d.offset())
 		return 0, errors.Wrapf(err, "leading %q", c)
 	case minusInNumber: // minus handled by caller
-		err := badToken(c, d.offset())
+		err := d.badToken(c, d.offset())
 		return 0, errors.Wrap(err, "double minus")
 	case 0:
 		if i == d.tail {
@@ -187,7 +200,7 @@ func (d *Decoder) positiveFloat64() (float64, error) {
 		}
 		c = d.buf[i]
 		if floatDigits[c] >= 0 {
-			err := badToken(c, d.offset()+1)
+			err := d.badToken(c, d.offset()+1)
 			return 0, errors.Wrap(err, "leading zero")
 		}
 	}
@@ -199,7 +212,10 @@ NonDecimal:
 		ind := floatDigits[c]
 		switch ind {
 		case invalidCharForNumber:
-			return 0, badToken(c, d.offset()+i)
+			if !d.commentStart(c) {
+				return 0, d.badToken(c, d.offset()+i)
+			}
+			fallthrough
 		case endOfNumber:
 			d.head = i
 			return float64(value), nil
@@ -222,6 +238,11 @@ NonDecimal:
 			c = d.buf[i]
 			ind := floatDigits[c]
 			switch ind {
+			case invalidCharForNumber:
+				if !d.commentStart(c) {
+					return 0, d.badToken(c, d.offset()+i)
+				}
+				fallthrough
 			case endOfNumber:
 				if decimalPlaces > 0 && decimalPlaces < len(pow10) {
 					d.head = i
@@ -231,8 +252,6 @@ NonDecimal:
 				return d.float64Slow()
 			case dotInNumber, expInNumber, plusInNumber, minusInNumber:
 				return d.float64Slow()
-			case invalidCharForNumber:
-				return 0, badToken(c, d.offset()+i)
 			}
 			decimalPlaces++
 			// Not checking for uint64SafeToMultiple10 here because
@@ -268,7 +287,7 @@ func (d *Decoder) floatSlow(size int) (float64, error) {
 		return 0, errors.Wrap(err, "number")
 	}
 
-	if err := validateFloat(str, offset); err != nil {
+	if err := d.validateFloat(str, offset); err != nil {
 		return 0, err
 	}
 
@@ -280,7 +299,7 @@ func (d *Decoder) floatSlow(size int) (float64, error) {
 	return val, nil
 }
 
-func validateFloat(str []byte, offset int) error {
+func (d *Decoder) validateFloat(str []byte, offset int) error {
 	// strconv.ParseFloat is not validating `1.` or `1.e1`
 	if len(str) == 0 {
 		// FIXME(tdakkota): use io.ErrUnexpectedEOF?
@@ -289,17 +308,17 @@ func validateFloat(str []byte, offset int) error {
 
 	switch c := str[0]; floatDigits[c] {
 	case dotInNumber, plusInNumber, expInNumber:
-		err := badToken(c, offset)
+		err := d.badToken(c, offset)
 		return errors.Wrapf(err, "leading %q", c)
 	case minusInNumber: // minus handled by caller
-		err := badToken(c, offset)
+		err := d.badToken(c, offset)
 		return errors.Wrap(err, "double minus")
 	case 0:
 		if len(str) >= 2 {
 			switch str[1] {
 			case 'e', 'E', '.':
 			default:
-				err := badToken(str[1], offset+1)
+				err := d.badToken(str[1], offset+1)
 				return errors.Wrap(err, "leading zero")
 			}
 		}
@@ -314,7 +333,7 @@ func validateFloat(str []byte, offset int) error {
 		switch c := str[dotPos+1]; c {
 		case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
 		default:
-			err := badToken(c, offset+dotPos+1)
+			err := d.badToken(c, offset+dotPos+1)
 			return errors.Wrap(err, "no digit after dot")
 		}
 	}
@@ -3,6 +3,7 @@ package jx
 import (
 	"io"
 	"math/big"
+	"strings"
 
 	"github.com/go-faster/errors"
 )
@@ -17,7 +18,12 @@ func (d *Decoder) BigFloat() (*big.Float, error) {
 	if len(str) > prec {
 		prec = len(str)
 	}
-	val, _, err := big.ParseFloat(string(str), 10, uint(prec), big.ToZero)
+	s, base := string(str), 10
+	if d.ext&extNumbers != 0 {
+		// Allow hexadecimal numbers and Infinity.
+		s, base = strings.TrimSuffix(s, "inity"), 0
+	}
+	val, _, err := big.ParseFloat(s, base, uint(prec), big.ToZero)
 	if err != nil {
 		return nil, errors.Wrap(err, "float")
 	}
@@ -30,9 +36,14 @@ func (d *Decoder) BigInt() (*big.Int, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "number")
 	}
+	base := 10
+	if d.ext&extNumbers != 0 {
+		// Allow hexadecimal numbers.
+		base = 0
+	}
 	v := big.NewInt(0)
 	var ok bool
-	if v, ok = v.SetString(string(str), 10); !ok {
+	if v, ok = v.SetString(string(str), base); !ok {
 		return nil, errors.New("invalid")
 	}
 	return v, nil
@@ -44,7 +55,10 @@ func (d *Decoder) number() ([]byte, error) {
 	for i, c := range buf {
 		switch floatDigits[c] {
 		case invalidCharForNumber:
-			return nil, badToken(c, d.offset()+i)
+			if !d.commentStart(c) {
+				return nil, d.badToken(c, d.offset()+i)
+			}
+			fallthrough
 		case endOfNumber:
 			// End of number.
 			d.head += i
@@ -59,6 +73,17 @@ func (d *Decoder) number() ([]byte, error) {
 }
 
 func (d *Decoder) numberAppend(b []byte) ([]byte, error) {
+	if d.ext&extNumbers != 0 {
+		s, err := d.numberExt(nil)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, s...), nil
+	}
+	var (
+		base  = len(b)
+		start = d.offset()
+	)
 	for {
 		r, err := d.number()
 		if err != nil {
@@ -66,6 +91,9 @@ func (d *Decoder) numberAppend(b []byte) ([]byte, error) {
 		}
 
 		b = append(b, r...)
+		if err := d.checkNumLen(start, start+len(b)-base); err != nil {
+			return nil, err
+		}
 		if d.head != d.tail {
 			return b, nil
 		}
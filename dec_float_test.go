@@ -159,29 +159,29 @@ func TestDecoderFloatUnexpectedChar(t *testing.T) {
 		{" -10", 0, ""},
 
 		// Digit after leading zero.
-		{"00", 0, "leading zero: unexpected byte 48 '0' at 1"},
-		{"01", 0, "leading zero: unexpected byte 49 '1' at 1"},
-		{"-00", 0, "leading zero: unexpected byte 48 '0' at 2"},
-		{"-01", 0, "leading zero: unexpected byte 49 '1' at 2"},
+		{"00", 0, "leading zero: unexpected byte 48 '0' at line 1:2 (offset 1)"},
+		{"01", 0, "leading zero: unexpected byte 49 '1' at line 1:2 (offset 1)"},
+		{"-00", 0, "leading zero: unexpected byte 48 '0' at line 1:3 (offset 2)"},
+		{"-01", 0, "leading zero: unexpected byte 49 '1' at line 1:3 (offset 2)"},
 
 		// Double minus.
-		{"--10", 0, "unexpected byte 45 '-' at 1"},
+		{"--10", 0, "unexpected byte 45 '-' at line 1:2 (offset 1)"},
 
 		// Leading dot.
-		{".0", 0, "unexpected byte 46 '.' at 0"},
+		{".0", 0, "unexpected byte 46 '.' at line 1:1 (offset 0)"},
 		// Leading exponent.
-		{"e0", 0, "unexpected byte 101 'e' at 0"},
-		{"E0", 0, "unexpected byte 69 'E' at 0"},
+		{"e0", 0, "unexpected byte 101 'e' at line 1:1 (offset 0)"},
+		{"E0", 0, "unexpected byte 69 'E' at line 1:1 (offset 0)"},
 
 		// Non-digit after minus.
-		{"-.0", 0, "unexpected byte 46 '.' at 1"},
-		{"-e0", 0, "unexpected byte 101 'e' at 1"},
-		{"-E0", 0, "unexpected byte 69 'E' at 1"},
+		{"-.0", 0, "unexpected byte 46 '.' at line 1:2 (offset 1)"},
+		{"-e0", 0, "unexpected byte 101 'e' at line 1:2 (offset 1)"},
+		{"-E0", 0, "unexpected byte 69 'E' at line 1:2 (offset 1)"},
 
 		// Unexpected character.
-		{"-a", 0, "unexpected byte 97 'a' at 1"},
-		{"0a", 0, "unexpected byte 97 'a' at 1"},
-		{"0.a", 0, "unexpected byte 97 'a' at 2"},
+		{"-a", 0, "unexpected byte 97 'a' at line 1:2 (offset 1)"},
+		{"0a", 0, "unexpected byte 97 'a' at line 1:2 (offset 1)"},
+		{"0.a", 0, "unexpected byte 97 'a' at line 1:3 (offset 2)"},
 	}
 
 	for i, tt := range tests {
@@ -200,7 +200,7 @@ func TestDecoderFloatUnexpectedChar(t *testing.T) {
 							err := intFn.fn(d)
 							if e := tt.errContains; e != "" {
 								a.ErrorContains(err, e)
-								v, ok := errors.Into[*badTokenErr](err)
+								v, ok := errors.Into[*SyntaxError](err)
 								if !ok {
 									return
 								}
@@ -21,6 +21,10 @@ const (
 
 // UInt8 reads uint8.
 func (d *Decoder) UInt8() (uint8, error) {
+	if d.ext&extNumbers != 0 {
+		v, err := d.uintExt(8)
+		return uint8(v), err
+	}
 	c, err := d.more()
 	if err != nil {
 		return 0, err
@@ -37,19 +41,21 @@ func (d *Decoder) readUInt8(c byte) (uint8, error) {
 		if err == nil {
 			switch floatDigits[c] {
 			case 0, 1, 2, 3, 4, 5, 6, 7, 8, 9:
-				err := badToken(c, d.offset())
+				err := d.badToken(c, d.offset())
 				return 0, errors.Wrap(err, "digit after leading zero")
 			case dotInNumber, expInNumber, plusInNumber, minusInNumber:
-				err := badToken(c, d.offset())
+				err := d.badToken(c, d.offset())
 				return 0, errors.Wrap(err, "unexpected floating point character")
 			case invalidCharForNumber:
-				return 0, badToken(c, d.offset())
+				if !d.commentStart(c) {
+					return 0, d.badToken(c, d.offset())
+				}
 			}
 		}
 		return 0, nil // single zero
 	default:
 		if ind < 0 {
-			return 0, badToken(c, d.offset()-1)
+			return 0, d.badToken(c, d.offset()-1)
 		}
 	}
 	value := uint8(ind)
@@ -59,54 +65,63 @@ func (d *Decoder) readUInt8(c byte) (uint8, error) {
 		ind2 := floatDigits[d.buf[i]]
 		switch ind2 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+0)
+			if !d.commentStart(d.buf[i]) {
+				return 0, d.badToken(d.buf[i], d.offset()+0)
+			}
+			fallthrough
+		case endOfNumber:
+			d.head = i
+			value *= 1
+			return value, nil
 		case dotInNumber,
 			expInNumber,
 			plusInNumber,
 			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+0)
+			err := d.badToken(d.buf[i], d.offset()+0)
 			return 0, errors.Wrap(err, "unexpected floating point character")
-		case endOfNumber:
-			d.head = i
-			value *= 1
-			return value, nil
 		}
 		i++
 		// Iteration 1.
 		ind3 := floatDigits[d.buf[i]]
 		switch ind3 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+1)
-		case dotInNumber,
-			expInNumber,
-			plusInNumber,
-			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+1)
-			return 0, errors.Wrap(err, "unexpected floating point character")
+			if !d.commentStart(d.buf[i]) {
+				return 0, d.badToken(d.buf[i], d.offset()+1)
+			}
+			fallthrough
 		case endOfNumber:
 			d.head = i
 			value *= 10
 			value += uint8(ind2) * 1
 			return value, nil
+		case dotInNumber,
+			expInNumber,
+			plusInNumber,
+			minusInNumber:
+			err := d.badToken(d.buf[i], d.offset()+1)
+			return 0, errors.Wrap(err, "unexpected floating point character")
 		}
 		i++
 		// Iteration 2.
 		ind4 := floatDigits[d.buf[i]]
 		switch ind4 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+2)
-		case dotInNumber,
-			expInNumber,
-			plusInNumber,
-			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+2)
-			return 0, errors.Wrap(err, "unexpected floating point character")
+			if !d.commentStart(d.buf[i]) {
+				return 0, d.badToken(d.buf[i], d.offset()+2)
+			}
+			fallthrough
 		case endOfNumber:
 			d.head = i
 			value *= 100
 			value += uint8(ind2) * 10
 			value += uint8(ind3) * 1
 			return value, nil
+		case dotInNumber,
+			expInNumber,
+			plusInNumber,
+			minusInNumber:
+			err := d.badToken(d.buf[i], d.offset()+2)
+			return 0, errors.Wrap(err, "unexpected floating point character")
 		}
 		d.head = i
 		value *= 100
@@ -119,16 +134,19 @@ func (d *Decoder) readUInt8(c byte) (uint8, error) {
 			ind = floatDigits[c]
 			switch ind {
 			case invalidCharForNumber:
-				return 0, badToken(c, d.offset()+i)
+				if !d.commentStart(c) {
+					return 0, d.badToken(c, d.offset()+i)
+				}
+				fallthrough
+			case endOfNumber:
+				d.head += i
+				return value, nil
 			case dotInNumber,
 				expInNumber,
 				plusInNumber,
 				minusInNumber:
-				err := badToken(c, d.offset()+i)
+				err := d.badToken(c, d.offset()+i)
 				return 0, errors.Wrap(err, "unexpected floating point character")
-			case endOfNumber:
-				d.head += i
-				return value, nil
 			}
 			if value > uint8SafeToMultiple10 {
 				value2 := (value << 3) + (value << 1) + uint8(ind)
@@ -153,6 +171,10 @@ func (d *Decoder) readUInt8(c byte) (uint8, error) {
 
 // Int8 reads int8.
 func (d *Decoder) Int8() (int8, error) {
+	if d.ext&extNumbers != 0 {
+		v, err := d.intExt(8)
+		return int8(v), err
+	}
 	c, err := d.more()
 	if err != nil {
 		return 0, err
@@ -183,6 +205,10 @@ func (d *Decoder) Int8() (int8, error) {
 
 // UInt16 reads uint16.
 func (d *Decoder) UInt16() (uint16, error) {
+	if d.ext&extNumbers != 0 {
+		v, err := d.uintExt(16)
+		return uint16(v), err
+	}
 	c, err := d.more()
 	if err != nil {
 		return 0, err
@@ -199,19 +225,21 @@ func (d *Decoder) readUInt16(c byte) (uint16, error) {
 		if err == nil {
 			switch floatDigits[c] {
 			case 0, 1, 2, 3, 4, 5, 6, 7, 8, 9:
-				err := badToken(c, d.offset())
+				err := d.badToken(c, d.offset())
 				return 0, errors.Wrap(err, "digit after leading zero")
 			case dotInNumber, expInNumber, plusInNumber, minusInNumber:
-				err := badToken(c, d.offset())
+				err := d.badToken(c, d.offset())
 				return 0, errors.Wrap(err, "unexpected floating point character")
 			case invalidCharForNumber:
-				return 0, badToken(c, d.offset())
+				if !d.commentStart(c) {
+					return 0, d.badToken(c, d.offset())
+				}
 			}
 		}
 		return 0, nil // single zero
 	default:
 		if ind < 0 {
-			return 0, badToken(c, d.offset()-1)
+			return 0, d.badToken(c, d.offset()-1)
 		}
 	}
 	value := uint16(ind)
@@ -221,67 +249,73 @@ func (d *Decoder) readUInt16(c byte) (uint16, error) {
 		ind2 := floatDigits[d.buf[i]]
 		switch ind2 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+0)
+			if !d.commentStart(d.buf[i]) {
+				return 0, d.badToken(d.buf[i], d.offset()+0)
+			}
+			fallthrough
+		case endOfNumber:
+			d.head = i
+			value *= 1
+			return value, nil
 		case dotInNumber,
 			expInNumber,
 			plusInNumber,
 			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+0)
+			err := d.badToken(d.buf[i], d.offset()+0)
 			return 0, errors.Wrap(err, "unexpected floating point character")
-		case endOfNumber:
-			d.head = i
-			value *= 1
-			return value, nil
 		}
 		i++
 		// Iteration 1.
 		ind3 := floatDigits[d.buf[i]]
 		switch ind3 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+1)
-		case dotInNumber,
-			expInNumber,
-			plusInNumber,
-			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+1)
-			return 0, errors.Wrap(err, "unexpected floating point character")
+			if !d.commentStart(d.buf[i]) {
+				return 0, d.badToken(d.buf[i], d.offset()+1)
+			}
+			fallthrough
 		case endOfNumber:
 			d.head = i
 			value *= 10
 			value += uint16(ind2) * 1
 			return value, nil
+		case dotInNumber,
+			expInNumber,
+			plusInNumber,
+			minusInNumber:
+			err := d.badToken(d.buf[i], d.offset()+1)
+			return 0, errors.Wrap(err, "unexpected floating point character")
 		}
 		i++
 		// Iteration 2.
 		ind4 := floatDigits[d.buf[i]]
 		switch ind4 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+2)
-		case dotInNumber,
-			expInNumber,
-			plusInNumber,
-			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+2)
-			return 0, errors.Wrap(err, "unexpected floating point character")
+			if !d.commentStart(d.buf[i]) {
+				return 0, d.badToken(d.buf[i], d.offset()+2)
+			}
+			fallthrough
 		case endOfNumber:
 			d.head = i
 			value *= 100
 			value += uint16(ind2) * 10
 			value += uint16(ind3) * 1
 			return value, nil
+		case dotInNumber,
+			expInNumber,
+			plusInNumber,
+			minusInNumber:
+			err := d.badToken(d.buf[i], d.offset()+2)
+			return 0, errors.Wrap(err, "unexpected floating point character")
 		}
 		i++
 		// Iteration 3.
 		ind5 := floatDigits[d.buf[i]]
 		switch ind5 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+3)
-		case dotInNumber,
-			expInNumber,
-			plusInNumber,
-			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+3)
-			return 0, errors.Wrap(err, "unexpected floating point character")
+			if !d.commentStart(d.buf[i]) {
+				return 0, d.badToken(d.buf[i], d.offset()+3)
+			}
+			fallthrough
 		case endOfNumber:
 			d.head = i
 			value *= 1000
@@ -289,19 +323,22 @@ func (d *Decoder) readUInt16(c byte) (uint16, error) {
 			value += uint16(ind3) * 10
 			value += uint16(ind4) * 1
 			return value, nil
+		case dotInNumber,
+			expInNumber,
+			plusInNumber,
+			minusInNumber:
+			err := d.badToken(d.buf[i], d.offset()+3)
+			return 0, errors.Wrap(err, "unexpected floating point character")
 		}
 		i++
 		// Iteration 4.
 		ind6 := floatDigits[d.buf[i]]
 		switch ind6 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+4)
-		case dotInNumber,
-			expInNumber,
-			plusInNumber,
-			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+4)
-			return 0, errors.Wrap(err, "unexpected floating point character")
+			if !d.commentStart(d.buf[i]) {
+				return 0, d.badToken(d.buf[i], d.offset()+4)
+			}
+			fallthrough
 		case endOfNumber:
 			d.head = i
 			value *= 10000
@@ -310,6 +347,12 @@ func (d *Decoder) readUInt16(c byte) (uint16, error) {
 			value += uint16(ind4) * 10
 			value += uint16(ind5) * 1
 			return value, nil
+		case dotInNumber,
+			expInNumber,
+			plusInNumber,
+			minusInNumber:
+			err := d.badToken(d.buf[i], d.offset()+4)
+			return 0, errors.Wrap(err, "unexpected floating point character")
 		}
 		d.head = i
 		value *= 10000
@@ -324,16 +367,19 @@ func (d *Decoder) readUInt16(c byte) (uint16, error) {
 			ind = floatDigits[c]
 			switch ind {
 			case invalidCharForNumber:
-				return 0, badToken(c, d.offset()+i)
+				if !d.commentStart(c) {
+					return 0, d.badToken(c, d.offset()+i)
+				}
+				fallthrough
+			case endOfNumber:
+				d.head += i
+				return value, nil
 			case dotInNumber,
 				expInNumber,
 				plusInNumber,
 				minusInNumber:
-				err := badToken(c, d.offset()+i)
+				err := d.badToken(c, d.offset()+i)
 				return 0, errors.Wrap(err, "unexpected floating point character")
-			case endOfNumber:
-				d.head += i
-				return value, nil
 			}
 			if value > uint16SafeToMultiple10 {
 				value2 := (value << 3) + (value << 1) + uint16(ind)
@@ -358,6 +404,10 @@ func (d *Decoder) readUInt16(c byte) (uint16, error) {
 
 // Int16 reads int16.
 func (d *Decoder) Int16() (int16, error) {
+	if d.ext&extNumbers != 0 {
+		v, err := d.intExt(16)
+		return int16(v), err
+	}
 	c, err := d.more()
 	if err != nil {
 		return 0, err
@@ -388,6 +438,10 @@ func (d *Decoder) Int16() (int16, error) {
 
 // UInt32 reads uint32.
 func (d *Decoder) UInt32() (uint32, error) {
+	if d.ext&extNumbers != 0 {
+		v, err := d.uintExt(32)
+		return uint32(v), err
+	}
 	c, err := d.more()
 	if err != nil {
 		return 0, err
@@ -404,19 +458,21 @@ func (d *Decoder) readUInt32(c byte) (uint32, error) {
 		if err == nil {
 			switch floatDigits[c] {
 			case 0, 1, 2, 3, 4, 5, 6, 7, 8, 9:
-				err := badToken(c, d.offset())
+				err := d.badToken(c, d.offset())
 				return 0, errors.Wrap(err, "digit after leading zero")
 			case dotInNumber, expInNumber, plusInNumber, minusInNumber:
-				err := badToken(c, d.offset())
+				err := d.badToken(c, d.offset())
 				return 0, errors.Wrap(err, "unexpected floating point character")
 			case invalidCharForNumber:
-				return 0, badToken(c, d.offset())
+				if !d.commentStart(c) {
+					return 0, d.badToken(c, d.offset())
+				}
 			}
 		}
 		return 0, nil // single zero
 	default:
 		if ind < 0 {
-			return 0, badToken(c, d.offset()-1)
+			return 0, d.badToken(c, d.offset()-1)
 		}
 	}
 	value := uint32(ind)
@@ -426,67 +482,73 @@ func (d *Decoder) readUInt32(c byte) (uint32, error) {
 		ind2 := floatDigits[d.buf[i]]
 		switch ind2 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+0)
+			if !d.commentStart(d.buf[i]) {
+				return 0, d.badToken(d.buf[i], d.offset()+0)
+			}
+			fallthrough
+		case endOfNumber:
+			d.head = i
+			value *= 1
+			return value, nil
 		case dotInNumber,
 			expInNumber,
 			plusInNumber,
 			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+0)
+			err := d.badToken(d.buf[i], d.offset()+0)
 			return 0, errors.Wrap(err, "unexpected floating point character")
-		case endOfNumber:
-			d.head = i
-			value *= 1
-			return value, nil
 		}
 		i++
 		// Iteration 1.
 		ind3 := floatDigits[d.buf[i]]
 		switch ind3 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+1)
-		case dotInNumber,
-			expInNumber,
-			plusInNumber,
-			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+1)
-			return 0, errors.Wrap(err, "unexpected floating point character")
+			if !d.commentStart(d.buf[i]) {
+				return 0, d.badToken(d.buf[i], d.offset()+1)
+			}
+			fallthrough
 		case endOfNumber:
 			d.head = i
 			value *= 10
 			value += uint32(ind2) * 1
 			return value, nil
+		case dotInNumber,
+			expInNumber,
+			plusInNumber,
+			minusInNumber:
+			err := d.badToken(d.buf[i], d.offset()+1)
+			return 0, errors.Wrap(err, "unexpected floating point character")
 		}
 		i++
 		// Iteration 2.
 		ind4 := floatDigits[d.buf[i]]
 		switch ind4 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+2)
-		case dotInNumber,
-			expInNumber,
-			plusInNumber,
-			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+2)
-			return 0, errors.Wrap(err, "unexpected floating point character")
+			if !d.commentStart(d.buf[i]) {
+				return 0, d.badToken(d.buf[i], d.offset()+2)
+			}
+			fallthrough
 		case endOfNumber:
 			d.head = i
 			value *= 100
 			value += uint32(ind2) * 10
 			value += uint32(ind3) * 1
 			return value, nil
+		case dotInNumber,
+			expInNumber,
+			plusInNumber,
+			minusInNumber:
+			err := d.badToken(d.buf[i], d.offset()+2)
+			return 0, errors.Wrap(err, "unexpected floating point character")
 		}
 		i++
 		// Iteration 3.
 		ind5 := floatDigits[d.buf[i]]
 		switch ind5 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+3)
-		case dotInNumber,
-			expInNumber,
-			plusInNumber,
-			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+3)
-			return 0, errors.Wrap(err, "unexpected floating point character")
+			if !d.commentStart(d.buf[i]) {
+				return 0, d.badToken(d.buf[i], d.offset()+3)
+			}
+			fallthrough
 		case endOfNumber:
 			d.head = i
 			value *= 1000
@@ -494,19 +556,22 @@ func (d *Decoder) readUInt32(c byte) (uint32, error) {
 			value += uint32(ind3) * 10
 			value += uint32(ind4) * 1
 			return value, nil
+		case dotInNumber,
+			expInNumber,
+			plusInNumber,
+			minusInNumber:
+			err := d.badToken(d.buf[i], d.offset()+3)
+			return 0, errors.Wrap(err, "unexpected floating point character")
 		}
 		i++
 		// Iteration 4.
 		ind6 := floatDigits[d.buf[i]]
 		switch ind6 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+4)
-		case dotInNumber,
-			expInNumber,
-			plusInNumber,
-			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+4)
-			return 0, errors.Wrap(err, "unexpected floating point character")
+			if !d.commentStart(d.buf[i]) {
+				return 0, d.badToken(d.buf[i], d.offset()+4)
+			}
+			fallthrough
 		case endOfNumber:
 			d.head = i
 			value *= 10000
@@ -515,19 +580,22 @@ func (d *Decoder) readUInt32(c byte) (uint32, error) {
 			value += uint32(ind4) * 10
 			value += uint32(ind5) * 1
 			return value, nil
+		case dotInNumber,
+			expInNumber,
+			plusInNumber,
+			minusInNumber:
+			err := d.badToken(d.buf[i], d.offset()+4)
+			return 0, errors.Wrap(err, "unexpected floating point character")
 		}
 		i++
 		// Iteration 5.
 		ind7 := floatDigits[d.buf[i]]
 		switch ind7 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+5)
-		case dotInNumber,
-			expInNumber,
-			plusInNumber,
-			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+5)
-			return 0, errors.Wrap(err, "unexpected floating point character")
+			if !d.commentStart(d.buf[i]) {
+				return 0, d.badToken(d.buf[i], d.offset()+5)
+			}
+			fallthrough
 		case endOfNumber:
 			d.head = i
 			value *= 100000
@@ -537,19 +605,22 @@ func (d *Decoder) readUInt32(c byte) (uint32, error) {
 			value += uint32(ind5) * 10
 			value += uint32(ind6) * 1
 			return value, nil
+		case dotInNumber,
+			expInNumber,
+			plusInNumber,
+			minusInNumber:
+			err := d.badToken(d.buf[i], d.offset()+5)
+			return 0, errors.Wrap(err, "unexpected floating point character")
 		}
 		i++
 		// Iteration 6.
 		ind8 := floatDigits[d.buf[i]]
 		switch ind8 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+6)
-		case dotInNumber,
-			expInNumber,
-			plusInNumber,
-			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+6)
-			return 0, errors.Wrap(err, "unexpected floating point character")
+			if !d.commentStart(d.buf[i]) {
+				return 0, d.badToken(d.buf[i], d.offset()+6)
+			}
+			fallthrough
 		case endOfNumber:
 			d.head = i
 			value *= 1000000
@@ -560,19 +631,22 @@ func (d *Decoder) readUInt32(c byte) (uint32, error) {
 			value += uint32(ind6) * 10
 			value += uint32(ind7) * 1
 			return value, nil
+		case dotInNumber,
+			expInNumber,
+			plusInNumber,
+			minusInNumber:
+			err := d.badToken(d.buf[i], d.offset()+6)
+			return 0, errors.Wrap(err, "unexpected floating point character")
 		}
 		i++
 		// Iteration 7.
 		ind9 := floatDigits[d.buf[i]]
 		switch ind9 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+7)
-		case dotInNumber,
-			expInNumber,
-			plusInNumber,
-			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+7)
-			return 0, errors.Wrap(err, "unexpected floating point character")
+			if !d.commentStart(d.buf[i]) {
+				return 0, d.badToken(d.buf[i], d.offset()+7)
+			}
+			fallthrough
 		case endOfNumber:
 			d.head = i
 			value *= 10000000
@@ -584,19 +658,22 @@ func (d *Decoder) readUInt32(c byte) (uint32, error) {
 			value += uint32(ind7) * 10
 			value += uint32(ind8) * 1
 			return value, nil
+		case dotInNumber,
+			expInNumber,
+			plusInNumber,
+			minusInNumber:
+			err := d.badToken(d.buf[i], d.offset()+7)
+			return 0, errors.Wrap(err, "unexpected floating point character")
 		}
 		i++
 		// Iteration 8.
 		ind10 := floatDigits[d.buf[i]]
 		switch ind10 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+8)
-		case dotInNumber,
-			expInNumber,
-			plusInNumber,
-			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+8)
-			return 0, errors.Wrap(err, "unexpected floating point character")
+			if !d.commentStart(d.buf[i]) {
+				return 0, d.badToken(d.buf[i], d.offset()+8)
+			}
+			fallthrough
 		case endOfNumber:
 			d.head = i
 			value *= 100000000
@@ -609,6 +686,12 @@ func (d *Decoder) readUInt32(c byte) (uint32, error) {
 			value += uint32(ind8) * 10
 			value += uint32(ind9) * 1
 			return value, nil
+		case dotInNumber,
+			expInNumber,
+			plusInNumber,
+			minusInNumber:
+			err := d.badToken(d.buf[i], d.offset()+8)
+			return 0, errors.Wrap(err, "unexpected floating point character")
 		}
 		d.head = i
 		value *= 100000000
@@ -627,16 +710,19 @@ func (d *Decoder) readUInt32(c byte) (uint32, error) {
 			ind = floatDigits[c]
 			switch ind {
 			case invalidCharForNumber:
-				return 0, badToken(c, d.offset()+i)
+				if !d.commentStart(c) {
+					return 0, d.badToken(c, d.offset()+i)
+				}
+				fallthrough
+			case endOfNumber:
+				d.head += i
+				return value, nil
 			case dotInNumber,
 				expInNumber,
 				plusInNumber,
 				minusInNumber:
-				err := badToken(c, d.offset()+i)
+				err := d.badToken(c, d.offset()+i)
 				return 0, errors.Wrap(err, "unexpected floating point character")
-			case endOfNumber:
-				d.head += i
-				return value, nil
 			}
 			if value > uint32SafeToMultiple10 {
 				value2 := (value << 3) + (value << 1) + uint32(ind)
@@ -661,6 +747,10 @@ func (d *Decoder) readUInt32(c byte) (uint32, error) {
 
 // Int32 reads int32.
 func (d *Decoder) Int32() (int32, error) {
+	if d.ext&extNumbers != 0 {
+		v, err := d.intExt(32)
+		return int32(v), err
+	}
 	c, err := d.more()
 	if err != nil {
 		return 0, err
@@ -691,6 +781,10 @@ func (d *Decoder) Int32() (int32, error) {
 
 // UInt64 reads uint64.
 func (d *Decoder) UInt64() (uint64, error) {
+	if d.ext&extNumbers != 0 {
+		v, err := d.uintExt(64)
+		return uint64(v), err
+	}
 	c, err := d.more()
 	if err != nil {
 		return 0, err
@@ -707,19 +801,21 @@ func (d *Decoder) readUInt64(c byte) (uint64, error) {
 		if err == nil {
 			switch floatDigits[c] {
 			case 0, 1, 2, 3, 4, 5, 6, 7, 8, 9:
-				err := badToken(c, d.offset())
+				err := d.badToken(c, d.offset())
 				return 0, errors.Wrap(err, "digit after leading zero")
 			case dotInNumber, expInNumber, plusInNumber, minusInNumber:
-				err := badToken(c, d.offset())
+				err := d.badToken(c, d.offset())
 				return 0, errors.Wrap(err, "unexpected floating point character")
 			case invalidCharForNumber:
-				return 0, badToken(c, d.offset())
+				if !d.commentStart(c) {
+					return 0, d.badToken(c, d.offset())
+				}
 			}
 		}
 		return 0, nil // single zero
 	default:
 		if ind < 0 {
-			return 0, badToken(c, d.offset()-1)
+			return 0, d.badToken(c, d.offset()-1)
 		}
 	}
 	value := uint64(ind)
@@ -729,67 +825,73 @@ func (d *Decoder) readUInt64(c byte) (uint64, error) {
 		ind2 := floatDigits[d.buf[i]]
 		switch ind2 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+0)
+			if !d.commentStart(d.buf[i]) {
+				return 0, d.badToken(d.buf[i], d.offset()+0)
+			}
+			fallthrough
+		case endOfNumber:
+			d.head = i
+			value *= 1
+			return value, nil
 		case dotInNumber,
 			expInNumber,
 			plusInNumber,
 			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+0)
+			err := d.badToken(d.buf[i], d.offset()+0)
 			return 0, errors.Wrap(err, "unexpected floating point character")
-		case endOfNumber:
-			d.head = i
-			value *= 1
-			return value, nil
 		}
 		i++
 		// Iteration 1.
 		ind3 := floatDigits[d.buf[i]]
 		switch ind3 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+1)
-		case dotInNumber,
-			expInNumber,
-			plusInNumber,
-			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+1)
-			return 0, errors.Wrap(err, "unexpected floating point character")
+			if !d.commentStart(d.buf[i]) {
+				return 0, d.badToken(d.buf[i], d.offset()+1)
+			}
+			fallthrough
 		case endOfNumber:
 			d.head = i
 			value *= 10
 			value += uint64(ind2) * 1
 			return value, nil
+		case dotInNumber,
+			expInNumber,
+			plusInNumber,
+			minusInNumber:
+			err := d.badToken(d.buf[i], d.offset()+1)
+			return 0, errors.Wrap(err, "unexpected floating point character")
 		}
 		i++
 		// Iteration 2.
 		ind4 := floatDigits[d.buf[i]]
 		switch ind4 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+2)
-		case dotInNumber,
-			expInNumber,
-			plusInNumber,
-			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+2)
-			return 0, errors.Wrap(err, "unexpected floating point character")
+			if !d.commentStart(d.buf[i]) {
+				return 0, d.badToken(d.buf[i], d.offset()+2)
+			}
+			fallthrough
 		case endOfNumber:
 			d.head = i
 			value *= 100
 			value += uint64(ind2) * 10
 			value += uint64(ind3) * 1
 			return value, nil
+		case dotInNumber,
+			expInNumber,
+			plusInNumber,
+			minusInNumber:
+			err := d.badToken(d.buf[i], d.offset()+2)
+			return 0, errors.Wrap(err, "unexpected floating point character")
 		}
 		i++
 		// Iteration 3.
 		ind5 := floatDigits[d.buf[i]]
 		switch ind5 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+3)
-		case dotInNumber,
-			expInNumber,
-			plusInNumber,
-			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+3)
-			return 0, errors.Wrap(err, "unexpected floating point character")
+			if !d.commentStart(d.buf[i]) {
+				return 0, d.badToken(d.buf[i], d.offset()+3)
+			}
+			fallthrough
 		case endOfNumber:
 			d.head = i
 			value *= 1000
@@ -797,19 +899,22 @@ func (d *Decoder) readUInt64(c byte) (uint64, error) {
 			value += uint64(ind3) * 10
 			value += uint64(ind4) * 1
 			return value, nil
+		case dotInNumber,
+			expInNumber,
+			plusInNumber,
+			minusInNumber:
+			err := d.badToken(d.buf[i], d.offset()+3)
+			return 0, errors.Wrap(err, "unexpected floating point character")
 		}
 		i++
 		// Iteration 4.
 		ind6 := floatDigits[d.buf[i]]
 		switch ind6 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+4)
-		case dotInNumber,
-			expInNumber,
-			plusInNumber,
-			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+4)
-			return 0, errors.Wrap(err, "unexpected floating point character")
+			if !d.commentStart(d.buf[i]) {
+				return 0, d.badToken(d.buf[i], d.offset()+4)
+			}
+			fallthrough
 		case endOfNumber:
 			d.head = i
 			value *= 10000
@@ -818,19 +923,22 @@ func (d *Decoder) readUInt64(c byte) (uint64, error) {
 			value += uint64(ind4) * 10
 			value += uint64(ind5) * 1
 			return value, nil
+		case dotInNumber,
+			expInNumber,
+			plusInNumber,
+			minusInNumber:
+			err := d.badToken(d.buf[i], d.offset()+4)
+			return 0, errors.Wrap(err, "unexpected floating point character")
 		}
 		i++
 		// Iteration 5.
 		ind7 := floatDigits[d.buf[i]]
 		switch ind7 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+5)
-		case dotInNumber,
-			expInNumber,
-			plusInNumber,
-			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+5)
-			return 0, errors.Wrap(err, "unexpected floating point character")
+			if !d.commentStart(d.buf[i]) {
+				return 0, d.badToken(d.buf[i], d.offset()+5)
+			}
+			fallthrough
 		case endOfNumber:
 			d.head = i
 			value *= 100000
@@ -840,19 +948,22 @@ func (d *Decoder) readUInt64(c byte) (uint64, error) {
 			value += uint64(ind5) * 10
 			value += uint64(ind6) * 1
 			return value, nil
+		case dotInNumber,
+			expInNumber,
+			plusInNumber,
+			minusInNumber:
+			err := d.badToken(d.buf[i], d.offset()+5)
+			return 0, errors.Wrap(err, "unexpected floating point character")
 		}
 		i++
 		// Iteration 6.
 		ind8 := floatDigits[d.buf[i]]
 		switch ind8 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+6)
-		case dotInNumber,
-			expInNumber,
-			plusInNumber,
-			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+6)
-			return 0, errors.Wrap(err, "unexpected floating point character")
+			if !d.commentStart(d.buf[i]) {
+				return 0, d.badToken(d.buf[i], d.offset()+6)
+			}
+			fallthrough
 		case endOfNumber:
 			d.head = i
 			value *= 1000000
@@ -863,19 +974,22 @@ func (d *Decoder) readUInt64(c byte) (uint64, error) {
 			value += uint64(ind6) * 10
 			value += uint64(ind7) * 1
 			return value, nil
+		case dotInNumber,
+			expInNumber,
+			plusInNumber,
+			minusInNumber:
+			err := d.badToken(d.buf[i], d.offset()+6)
+			return 0, errors.Wrap(err, "unexpected floating point character")
 		}
 		i++
 		// Iteration 7.
 		ind9 := floatDigits[d.buf[i]]
 		switch ind9 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+7)
-		case dotInNumber,
-			expInNumber,
-			plusInNumber,
-			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+7)
-			return 0, errors.Wrap(err, "unexpected floating point character")
+			if !d.commentStart(d.buf[i]) {
+				return 0, d.badToken(d.buf[i], d.offset()+7)
+			}
+			fallthrough
 		case endOfNumber:
 			d.head = i
 			value *= 10000000
@@ -887,19 +1001,22 @@ func (d *Decoder) readUInt64(c byte) (uint64, error) {
 			value += uint64(ind7) * 10
 			value += uint64(ind8) * 1
 			return value, nil
+		case dotInNumber,
+			expInNumber,
+			plusInNumber,
+			minusInNumber:
+			err := d.badToken(d.buf[i], d.offset()+7)
+			return 0, errors.Wrap(err, "unexpected floating point character")
 		}
 		i++
 		// Iteration 8.
 		ind10 := floatDigits[d.buf[i]]
 		switch ind10 {
 		case invalidCharForNumber:
-			return 0, badToken(d.buf[i], d.offset()+8)
-		case dotInNumber,
-			expInNumber,
-			plusInNumber,
-			minusInNumber:
-			err := badToken(d.buf[i], d.offset()+8)
-			return 0, errors.Wrap(err, "unexpected floating point character")
+			if !d.commentStart(d.buf[i]) {
+				return 0, d.badToken(d.buf[i], d.offset()+8)
+			}
+			fallthrough
 		case endOfNumber:
 			d.head = i
 			value *= 100000000
@@ -912,6 +1029,12 @@ func (d *Decoder) readUInt64(c byte) (uint64, error) {
 			value += uint64(ind8) * 10
 			value += uint64(ind9) * 1
 			return value, nil
+		case dotInNumber,
+			expInNumber,
+			plusInNumber,
+			minusInNumber:
+			err := d.badToken(d.buf[i], d.offset()+8)
+			return 0, errors.Wrap(err, "unexpected floating point character")
 		}
 		d.head = i
 		value *= 100000000
@@ -930,16 +1053,19 @@ func (d *Decoder) readUInt64(c byte) (uint64, error) {
 			ind = floatDigits[c]
 			switch ind {
 			case invalidCharForNumber:
-				return 0, badToken(c, d.offset()+i)
+				if !d.commentStart(c) {
+					return 0, d.badToken(c, d.offset()+i)
+				}
+				fallthrough
+			case endOfNumber:
+				d.head += i
+				return value, nil
 			case dotInNumber,
 				expInNumber,
 				plusInNumber,
 				minusInNumber:
-				err := badToken(c, d.offset()+i)
+				err := d.badToken(c, d.offset()+i)
 				return 0, errors.Wrap(err, "unexpected floating point character")
-			case endOfNumber:
-				d.head += i
-				return value, nil
 			}
 			if value > uint64SafeToMultiple10 {
 				value2 := (value << 3) + (value << 1) + uint64(ind)
@@ -964,6 +1090,10 @@ func (d *Decoder) readUInt64(c byte) (uint64, error) {
 
 // Int64 reads int64.
 func (d *Decoder) Int64() (int64, error) {
+	if d.ext&extNumbers != 0 {
+		v, err := d.intExt(64)
+		return int64(v), err
+	}
 	c, err := d.more()
 	if err != nil {
 		return 0, err
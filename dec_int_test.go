@@ -141,58 +141,58 @@ func TestDecoderIntUnexpectedChar(t *testing.T) {
 		{" -10", false, 0, ""},
 
 		// Space in the middle.
-		{"- 10", false, 0, "unexpected byte 32 ' ' at 1"},
+		{"- 10", false, 0, "unexpected byte 32 ' ' at line 1:2 (offset 1)"},
 
 		// Digit after leading zero.
-		{"00", true, 0, "digit after leading zero: unexpected byte 48 '0' at 1"},
-		{"01", true, 0, "digit after leading zero: unexpected byte 49 '1' at 1"},
+		{"00", true, 0, "digit after leading zero: unexpected byte 48 '0' at line 1:2 (offset 1)"},
+		{"01", true, 0, "digit after leading zero: unexpected byte 49 '1' at line 1:2 (offset 1)"},
 
 		// Unexpected character.
 		// 8 bits.
-		{"0a0", true, 0, "unexpected byte 97 'a' at 1"},
-		{"1a00000000000", true, 0, "unexpected byte 97 'a' at 1"},
-		{"10a0000000000", true, 0, "unexpected byte 97 'a' at 2"},
-		{"100a000000000", true, 0, "unexpected byte 97 'a' at 3"},
+		{"0a0", true, 0, "unexpected byte 97 'a' at line 1:2 (offset 1)"},
+		{"1a00000000000", true, 0, "unexpected byte 97 'a' at line 1:2 (offset 1)"},
+		{"10a0000000000", true, 0, "unexpected byte 97 'a' at line 1:3 (offset 2)"},
+		{"100a000000000", true, 0, "unexpected byte 97 'a' at line 1:4 (offset 3)"},
 		// 16 bits.
-		{"1000a00000000", true, 16, "unexpected byte 97 'a' at 4"},
-		{"10000a0000000", true, 16, "unexpected byte 97 'a' at 5"},
+		{"1000a00000000", true, 16, "unexpected byte 97 'a' at line 1:5 (offset 4)"},
+		{"10000a0000000", true, 16, "unexpected byte 97 'a' at line 1:6 (offset 5)"},
 		// 32 bits.
-		{"100000a000000", true, 32, "unexpected byte 97 'a' at 6"},
-		{"1000000a00000", true, 32, "unexpected byte 97 'a' at 7"},
-		{"10000000a0000", true, 32, "unexpected byte 97 'a' at 8"},
-		{"100000000a000", true, 32, "unexpected byte 97 'a' at 9"},
-		{"1000000000a00", true, 32, "unexpected byte 97 'a' at 10"},
+		{"100000a000000", true, 32, "unexpected byte 97 'a' at line 1:7 (offset 6)"},
+		{"1000000a00000", true, 32, "unexpected byte 97 'a' at line 1:8 (offset 7)"},
+		{"10000000a0000", true, 32, "unexpected byte 97 'a' at line 1:9 (offset 8)"},
+		{"100000000a000", true, 32, "unexpected byte 97 'a' at line 1:10 (offset 9)"},
+		{"1000000000a00", true, 32, "unexpected byte 97 'a' at line 1:11 (offset 10)"},
 		// 64 bits.
-		{"10000000000a0", true, 64, "unexpected byte 97 'a' at 11"},
+		{"10000000000a0", true, 64, "unexpected byte 97 'a' at line 1:12 (offset 11)"},
 
 		// Dot in integer.
 		// 8 bits.
-		{"0.0", true, 0, "unexpected floating point character: unexpected byte 46 '.' at 1"},
-		{"1.00000000000", true, 0, "unexpected floating point character: unexpected byte 46 '.' at 1"},
-		{"10.0000000000", true, 0, "unexpected floating point character: unexpected byte 46 '.' at 2"},
-		{"100.000000000", true, 0, "unexpected floating point character: unexpected byte 46 '.' at 3"},
+		{"0.0", true, 0, "unexpected floating point character: unexpected byte 46 '.' at line 1:2 (offset 1)"},
+		{"1.00000000000", true, 0, "unexpected floating point character: unexpected byte 46 '.' at line 1:2 (offset 1)"},
+		{"10.0000000000", true, 0, "unexpected floating point character: unexpected byte 46 '.' at line 1:3 (offset 2)"},
+		{"100.000000000", true, 0, "unexpected floating point character: unexpected byte 46 '.' at line 1:4 (offset 3)"},
 		// 16 bits.
-		{"1000.00000000", true, 16, "unexpected floating point character: unexpected byte 46 '.' at 4"},
-		{"10000.0000000", true, 16, "unexpected floating point character: unexpected byte 46 '.' at 5"},
+		{"1000.00000000", true, 16, "unexpected floating point character: unexpected byte 46 '.' at line 1:5 (offset 4)"},
+		{"10000.0000000", true, 16, "unexpected floating point character: unexpected byte 46 '.' at line 1:6 (offset 5)"},
 		// 32 bits.
-		{"100000.000000", true, 32, "unexpected floating point character: unexpected byte 46 '.' at 6"},
-		{"1000000.00000", true, 32, "unexpected floating point character: unexpected byte 46 '.' at 7"},
-		{"10000000.0000", true, 32, "unexpected floating point character: unexpected byte 46 '.' at 8"},
-		{"100000000.000", true, 32, "unexpected floating point character: unexpected byte 46 '.' at 9"},
-		{"1000000000.00", true, 32, "unexpected floating point character: unexpected byte 46 '.' at 10"},
+		{"100000.000000", true, 32, "unexpected floating point character: unexpected byte 46 '.' at line 1:7 (offset 6)"},
+		{"1000000.00000", true, 32, "unexpected floating point character: unexpected byte 46 '.' at line 1:8 (offset 7)"},
+		{"10000000.0000", true, 32, "unexpected floating point character: unexpected byte 46 '.' at line 1:9 (offset 8)"},
+		{"100000000.000", true, 32, "unexpected floating point character: unexpected byte 46 '.' at line 1:10 (offset 9)"},
+		{"1000000000.00", true, 32, "unexpected floating point character: unexpected byte 46 '.' at line 1:11 (offset 10)"},
 		// 64 bits.
-		{"10000000000.0", true, 64, "unexpected floating point character: unexpected byte 46 '.' at 11"},
+		{"10000000000.0", true, 64, "unexpected floating point character: unexpected byte 46 '.' at line 1:12 (offset 11)"},
 
 		// Exp in integer.
-		{"0e0", true, 0, "unexpected floating point character: unexpected byte 101 'e' at 1"},
-		{"0E0", true, 0, "unexpected floating point character: unexpected byte 69 'E' at 1"},
-		{"0e-0", true, 0, "unexpected floating point character: unexpected byte 101 'e' at 1"},
-		{"0e+0", true, 0, "unexpected floating point character: unexpected byte 101 'e' at 1"},
-
-		{"1e0", true, 0, "unexpected floating point character: unexpected byte 101 'e' at 1"},
-		{"1E0", true, 0, "unexpected floating point character: unexpected byte 69 'E' at 1"},
-		{"1e-0", true, 0, "unexpected floating point character: unexpected byte 101 'e' at 1"},
-		{"1e+0", true, 0, "unexpected floating point character: unexpected byte 101 'e' at 1"},
+		{"0e0", true, 0, "unexpected floating point character: unexpected byte 101 'e' at line 1:2 (offset 1)"},
+		{"0E0", true, 0, "unexpected floating point character: unexpected byte 69 'E' at line 1:2 (offset 1)"},
+		{"0e-0", true, 0, "unexpected floating point character: unexpected byte 101 'e' at line 1:2 (offset 1)"},
+		{"0e+0", true, 0, "unexpected floating point character: unexpected byte 101 'e' at line 1:2 (offset 1)"},
+
+		{"1e0", true, 0, "unexpected floating point character: unexpected byte 101 'e' at line 1:2 (offset 1)"},
+		{"1E0", true, 0, "unexpected floating point character: unexpected byte 69 'E' at line 1:2 (offset 1)"},
+		{"1e-0", true, 0, "unexpected floating point character: unexpected byte 101 'e' at line 1:2 (offset 1)"},
+		{"1e+0", true, 0, "unexpected floating point character: unexpected byte 101 'e' at line 1:2 (offset 1)"},
 	}
 
 	for i, tt := range tests {
@@ -211,7 +211,7 @@ func TestDecoderIntUnexpectedChar(t *testing.T) {
 							err := intFn.fn(d)
 							if e := tt.errString; e != "" {
 								a.EqualError(err, e)
-								v, ok := errors.Into[*badTokenErr](err)
+								v, ok := errors.Into[*SyntaxError](err)
 								if !ok {
 									return
 								}
@@ -0,0 +1,135 @@
+package jx
+
+import "fmt"
+
+// DecoderLimits configures resource limits of Decoder.
+//
+// Zero value of each field means default: maximum depth of 10000 for
+// MaxDepth and no limit for others.
+type DecoderLimits struct {
+	// MaxDepth is maximum nesting depth of arrays and objects.
+	MaxDepth int
+	// MaxStrLen is maximum length of string in bytes, as written in input,
+	// excluding quotes.
+	MaxStrLen int
+	// MaxNumLen is maximum length of number in bytes, as written in input.
+	MaxNumLen int
+	// MaxElements is maximum count of elements in single array or fields
+	// in single object.
+	MaxElements int
+	// MaxBytes is maximum count of input bytes that can be consumed.
+	MaxBytes int
+}
+
+// Limit is a kind of limit from DecoderLimits.
+type Limit int
+
+// Limit kinds.
+const (
+	LimitDepth Limit = iota
+	LimitStrLen
+	LimitNumLen
+	LimitElements
+	LimitBytes
+)
+
+func (l Limit) String() string {
+	switch l {
+	case LimitDepth:
+		return "MaxDepth"
+	case LimitStrLen:
+		return "MaxStrLen"
+	case LimitNumLen:
+		return "MaxNumLen"
+	case LimitElements:
+		return "MaxElements"
+	case LimitBytes:
+		return "MaxBytes"
+	default:
+		return fmt.Sprintf("Limit(%d)", int(l))
+	}
+}
+
+// LimitError reports that input exceeded one of DecoderLimits.
+type LimitError struct {
+	Limit  Limit // exceeded limit
+	Max    int   // value of exceeded limit
+	Offset int   // offset in input where limit was exceeded
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("limit %s (%d) exceeded at %d", e.Limit, e.Max, e.Offset)
+}
+
+// SetLimits sets resource limits for next reads.
+//
+// Limits are kept on Reset and ResetBytes and cleared by PutDecoder.
+func (d *Decoder) SetLimits(l DecoderLimits) {
+	d.limits = l
+	if d.cut {
+		// Restore end of buffer, new limit may be greater.
+		d.tail = d.cutTail
+		d.cut = false
+	}
+	d.applyMaxBytes()
+}
+
+// Limits returns current resource limits.
+func (d *Decoder) Limits() DecoderLimits {
+	return d.limits
+}
+
+func (d *Decoder) limitErr(l Limit, max, offset int) error {
+	return &LimitError{
+		Limit:  l,
+		Max:    max,
+		Offset: offset,
+	}
+}
+
+// applyMaxBytes cuts current buffer to MaxBytes, if needed.
+//
+// Next read after reaching the cut returns LimitError instead of
+// reading the rest of input.
+func (d *Decoder) applyMaxBytes() {
+	max := d.limits.MaxBytes
+	if max <= 0 || d.streamOffset+d.tail <= max {
+		return
+	}
+	tail := max - d.streamOffset
+	if tail < d.head {
+		tail = d.head
+	}
+	if !d.cut {
+		d.cutTail = d.tail
+	}
+	d.tail = tail
+	d.cut = true
+}
+
+// checkElements checks that n-th element of array or object does not
+// exceed MaxElements.
+func (d *Decoder) checkElements(n int) error {
+	if max := d.limits.MaxElements; max > 0 && n > max {
+		return d.limitErr(LimitElements, max, d.offset())
+	}
+	return nil
+}
+
+// checkStrLen checks that string started at start offset and ended at end
+// offset does not exceed MaxStrLen.
+func (d *Decoder) checkStrLen(start, end int) error {
+	if max := d.limits.MaxStrLen; max > 0 && end-start > max {
+		return d.limitErr(LimitStrLen, max, start+max)
+	}
+	return nil
+}
+
+// checkNumLen checks that number started at start offset and ended at end
+// offset does not exceed MaxNumLen.
+func (d *Decoder) checkNumLen(start, end int) error {
+	if max := d.limits.MaxNumLen; max > 0 && end-start > max {
+		return d.limitErr(LimitNumLen, max, start+max)
+	}
+	return nil
+}
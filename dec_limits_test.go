@@ -0,0 +1,161 @@
+package jx
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-faster/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func requireLimitErr(t *testing.T, err error, l Limit) {
+	t.Helper()
+	le, ok := errors.Into[*LimitError](err)
+	require.Truef(t, ok, "expected *LimitError, got %v", err)
+	require.Equal(t, l, le.Limit)
+}
+
+func TestLimit_String(t *testing.T) {
+	met := map[string]bool{}
+	for l := LimitDepth; l <= LimitBytes+1; l++ {
+		s := l.String()
+		require.NotEmpty(t, s)
+		require.False(t, met[s], s)
+		met[s] = true
+	}
+}
+
+func TestLimitError_Error(t *testing.T) {
+	e := &LimitError{Limit: LimitStrLen, Max: 10, Offset: 15}
+	require.Equal(t, "limit MaxStrLen (10) exceeded at 15", e.Error())
+}
+
+func TestDecoder_SetLimits(t *testing.T) {
+	longStr := `"` + strings.Repeat("a", 600) + `"`
+	longNum := strings.Repeat("1", 600)
+	for _, tt := range []struct {
+		name   string
+		limits DecoderLimits
+		input  string
+		f      func(d *Decoder) error
+		limit  Limit
+	}{
+		{"Depth", DecoderLimits{MaxDepth: 2}, `[[[1]]]`, (*Decoder).Skip, LimitDepth},
+		{"DepthArr", DecoderLimits{MaxDepth: 1}, `[[1]]`, crawlValue, LimitDepth},
+		{"DepthObj", DecoderLimits{MaxDepth: 1}, `{"a":{}}`, crawlValue, LimitDepth},
+		{"StrLen", DecoderLimits{MaxStrLen: 3}, `"abcd"`, decoderOnlyError((*Decoder).Str), LimitStrLen},
+		{"StrLenEscaped", DecoderLimits{MaxStrLen: 3}, `"\nabc"`, decoderOnlyError((*Decoder).Str), LimitStrLen},
+		{"StrLenLong", DecoderLimits{MaxStrLen: 100}, longStr, decoderOnlyError((*Decoder).Str), LimitStrLen},
+		{"StrLenSkip", DecoderLimits{MaxStrLen: 100}, longStr, (*Decoder).Skip, LimitStrLen},
+		{"StrLenKey", DecoderLimits{MaxStrLen: 2}, `{"abc":1}`, crawlValue, LimitStrLen},
+		{"NumLen", DecoderLimits{MaxNumLen: 3}, `1234`, decoderOnlyError((*Decoder).Num), LimitNumLen},
+		{"NumLenStr", DecoderLimits{MaxNumLen: 3}, `"1234"`, decoderOnlyError((*Decoder).Num), LimitNumLen},
+		{"NumLenSkip", DecoderLimits{MaxNumLen: 100}, longNum, (*Decoder).Skip, LimitNumLen},
+		{"NumLenFloat", DecoderLimits{MaxNumLen: 100}, longNum, decoderOnlyError((*Decoder).BigFloat), LimitNumLen},
+		{"ElementsArr", DecoderLimits{MaxElements: 2}, `[1,2,3]`, crawlValue, LimitElements},
+		{"ElementsObj", DecoderLimits{MaxElements: 1}, `{"a":1,"b":2}`, crawlValue, LimitElements},
+		{"ElementsSkip", DecoderLimits{MaxElements: 2}, `[1,2,3]`, (*Decoder).Skip, LimitElements},
+		{"ElementsSkipObj", DecoderLimits{MaxElements: 1}, `{"a":1,"b":2}`, (*Decoder).Skip, LimitElements},
+		{"ElementsArrIter", DecoderLimits{MaxElements: 2}, `[1,2,3]`, func(d *Decoder) error {
+			iter, err := d.ArrIter()
+			if err != nil {
+				return err
+			}
+			for iter.Next() {
+				if err := d.Skip(); err != nil {
+					return err
+				}
+			}
+			return iter.Err()
+		}, LimitElements},
+		{"ElementsObjIter", DecoderLimits{MaxElements: 1}, `{"a":1,"b":2}`, func(d *Decoder) error {
+			iter, err := d.ObjIter()
+			if err != nil {
+				return err
+			}
+			for iter.Next() {
+				if err := d.Skip(); err != nil {
+					return err
+				}
+			}
+			return iter.Err()
+		}, LimitElements},
+		{"Bytes", DecoderLimits{MaxBytes: 5}, `[1,2,3]`, (*Decoder).Skip, LimitBytes},
+		{"BytesLong", DecoderLimits{MaxBytes: 100}, `[` + longNum + `]`, (*Decoder).Validate, LimitBytes},
+		{"BytesTrailing", DecoderLimits{MaxBytes: 5}, `[1,2] 10`, (*Decoder).Validate, LimitBytes},
+		{"BytesRaw", DecoderLimits{MaxBytes: 5}, `"abcdef"`, decoderOnlyError((*Decoder).Raw), LimitBytes},
+	} {
+		tt := tt
+		t.Run(tt.name, testBufferReader(tt.input, func(t *testing.T, d *Decoder) {
+			d.SetLimits(tt.limits)
+			require.Equal(t, tt.limits, d.Limits())
+			requireLimitErr(t, tt.f(d), tt.limit)
+		}))
+	}
+}
+
+func TestDecoder_SetLimitsOK(t *testing.T) {
+	limits := DecoderLimits{
+		MaxDepth:    2,
+		MaxStrLen:   3,
+		MaxNumLen:   3,
+		MaxElements: 3,
+		MaxBytes:    32,
+	}
+	for _, input := range []string{
+		`[[1]]`,
+		`{"abc":[123,"a\n",-12]}`,
+		`"123"`,
+		`[1,2,3]`,
+		`{"a":1,"b":2,"c":3}`,
+		`  [1, 2, 3]  `,
+	} {
+		input := input
+		t.Run(input, testBufferReader(input, func(t *testing.T, d *Decoder) {
+			d.SetLimits(limits)
+			require.NoError(t, d.Validate())
+		}))
+	}
+}
+
+func TestDecoder_SetLimitsReset(t *testing.T) {
+	d := GetDecoder()
+	d.SetLimits(DecoderLimits{MaxBytes: 2})
+	d.ResetBytes([]byte(`[1]`))
+	requireLimitErr(t, d.Validate(), LimitBytes)
+
+	// Limits are kept on reset.
+	d.Reset(strings.NewReader(`[1]`))
+	requireLimitErr(t, d.Validate(), LimitBytes)
+
+	// And cleared on put.
+	PutDecoder(d)
+	d = GetDecoder()
+	defer PutDecoder(d)
+	require.Equal(t, DecoderLimits{}, d.Limits())
+}
+
+func TestDecoder_SetLimitsRaise(t *testing.T) {
+	const input = `[1,2,3,4,5,6]`
+	for _, l := range []DecoderLimits{
+		{},
+		{MaxBytes: len(input)},
+		{MaxBytes: 100},
+	} {
+		t.Run(fmt.Sprintf("%d", l.MaxBytes), testBufferReader(input, func(t *testing.T, d *Decoder) {
+			d.SetLimits(DecoderLimits{MaxBytes: 4})
+			d.SetLimits(l)
+			require.NoError(t, d.Validate())
+		}))
+	}
+	t.Run("Lower", testBufferReader(input, func(t *testing.T, d *Decoder) {
+		d.SetLimits(DecoderLimits{MaxBytes: 8})
+		d.SetLimits(DecoderLimits{MaxBytes: 4})
+		err := d.Validate()
+		requireLimitErr(t, err, LimitBytes)
+		var le *LimitError
+		require.ErrorAs(t, err, &le)
+		require.Equal(t, 4, le.Max)
+	}))
+}
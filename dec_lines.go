@@ -0,0 +1,177 @@
+package jx
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/go-faster/errors"
+)
+
+// Record is position of record in stream of json values.
+type Record struct {
+	// Index is number of record, starting from 0. Blank records are not
+	// counted.
+	Index int
+	// Offset is offset of record start in input.
+	Offset int
+}
+
+// RecordError is error of decoding single record.
+type RecordError struct {
+	Record Record
+	Err    error
+}
+
+func (e *RecordError) Error() string {
+	return fmt.Sprintf("record %d at %d: %s", e.Record.Index, e.Record.Offset, e.Err)
+}
+
+// Unwrap returns underlying error.
+func (e *RecordError) Unwrap() error {
+	return e.Err
+}
+
+// Lines reads newline-delimited json (NDJSON, JSON Lines), calling f for
+// every non-blank line.
+//
+// The f must decode exactly one value using provided Decoder, which is
+// valid only until f returns. If f is nil, values are only validated.
+//
+// If record is malformed or f returns error, onErr is called and decoding
+// continues from the next line if onErr returns nil. If onErr is nil,
+// the first such error is returned. Read errors and LimitError for
+// MaxBytes are always returned as is.
+func (d *Decoder) Lines(f func(d *Decoder, r Record) error, onErr func(err *RecordError) error) error {
+	return d.records('\n', f, onErr)
+}
+
+// records reads records separated by sep, see Lines.
+func (d *Decoder) records(sep byte, f func(d *Decoder, r Record) error, onErr func(err *RecordError) error) error {
+	rd := &Decoder{
+		limits: d.limits,
+		ext:    d.ext,
+		dup:    d.dup,
+		utf8:   d.utf8,
+	}
+	// Input size is limited by d.
+	rd.limits.MaxBytes = 0
+
+	var (
+		buf   []byte
+		idx   int
+		lines int
+		start int
+	)
+	if line, column := d.position(d.offset()); line > 0 {
+		lines, start = line-1, d.offset()-column+1
+	}
+	for {
+		rec, offset, err := d.readRecord(buf[:0], sep)
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if d.reader != nil {
+			// Reuse buffer for next record.
+			buf = rec
+		}
+
+		rd.ResetBytes(rec)
+		rd.streamOffset = offset
+		rd.lines, rd.lineStart = lines, start
+		if _, err = rd.next(); err != io.EOF {
+			r := Record{Index: idx, Offset: offset}
+			idx++
+			if err == nil {
+				rd.unread()
+				if sep == seqSeparator && rd.seqTruncated(rec) {
+					err = errors.Wrap(io.ErrUnexpectedEOF, "truncated value")
+				} else {
+					err = rd.recordValue(f, r)
+				}
+			}
+			if err != nil {
+				rerr := &RecordError{Record: r, Err: err}
+				if onErr == nil {
+					return rerr
+				}
+				if err := onErr(rerr); err != nil {
+					return err
+				}
+			}
+		}
+
+		if i := bytes.LastIndexByte(rec, '\n'); i >= 0 {
+			lines += bytes.Count(rec, []byte{'\n'})
+			start = offset + i + 1
+		}
+		if sep == '\n' {
+			lines++
+			start = offset + len(rec) + 1
+		}
+	}
+}
+
+// recordValue decodes single value of record using f.
+func (d *Decoder) recordValue(f func(d *Decoder, r Record) error, r Record) error {
+	if f == nil {
+		if err := d.Skip(); err != nil {
+			return err
+		}
+	} else if err := f(d, r); err != nil {
+		return err
+	}
+	c, err := d.next()
+	switch err {
+	case io.EOF:
+		return nil
+	case nil:
+		return errors.Wrap(d.badToken(c, d.offset()-1), "trailing data")
+	default:
+		return err
+	}
+}
+
+// readRecord reads input until sep or end of input, returning record
+// without sep and its offset.
+//
+// If d is buffered, returns sub-slice of d.buf. Otherwise, appends record to
+// b.
+func (d *Decoder) readRecord(b []byte, sep byte) (rec []byte, offset int, err error) {
+	offset = d.offset()
+	start := d.head
+	for {
+		if i := bytes.IndexByte(d.buf[d.head:d.tail], sep); i >= 0 {
+			end := d.head + i
+			d.head = end + 1
+			if d.reader == nil {
+				return d.buf[start:end], offset, nil
+			}
+			return append(b, d.buf[start:end]...), offset, nil
+		}
+
+		end := d.tail
+		if d.reader != nil {
+			b = append(b, d.buf[start:end]...)
+		}
+		d.head = end
+		if err := d.read(); err != nil {
+			if err != io.EOF {
+				return nil, offset, err
+			}
+			// Last record is not terminated by sep.
+			switch {
+			case d.reader == nil && start < end:
+				return d.buf[start:end], offset, nil
+			case d.reader != nil && len(b) > 0:
+				return b, offset, nil
+			default:
+				return nil, offset, io.EOF
+			}
+		}
+		start = d.head
+	}
+}
@@ -0,0 +1,180 @@
+package jx
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-faster/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordError_Error(t *testing.T) {
+	err := &RecordError{
+		Record: Record{Index: 2, Offset: 10},
+		Err:    errors.New("test"),
+	}
+	require.Equal(t, "record 2 at 10: test", err.Error())
+	require.Equal(t, err.Err, errors.Unwrap(err))
+}
+
+func TestDecoder_Lines(t *testing.T) {
+	const input = "{\"a\": 1}\n\n  \r\n[1, 2]\r\n\"foo\"\n  3  "
+	testBufferReader(input, func(t *testing.T, d *Decoder) {
+		var (
+			records []Record
+			values  []string
+		)
+		require.NoError(t, d.Lines(func(d *Decoder, r Record) error {
+			raw, err := d.Raw()
+			if err != nil {
+				return err
+			}
+			records = append(records, r)
+			values = append(values, strings.TrimSpace(raw.String()))
+			return nil
+		}, nil))
+		require.Equal(t, []Record{
+			{Index: 0, Offset: 0},
+			{Index: 1, Offset: 14},
+			{Index: 2, Offset: 22},
+			{Index: 3, Offset: 28},
+		}, records)
+		require.Equal(t, []string{`{"a": 1}`, `[1, 2]`, `"foo"`, `3`}, values)
+	})(t)
+}
+
+func TestDecoder_LinesErrors(t *testing.T) {
+	const input = "{\"a\": 1}\n{\"a\":\n[1, 2]\n1 2\n\"x\"\n[1,]\n"
+	testBufferReader(input, func(t *testing.T, d *Decoder) {
+		var (
+			values []string
+			errs   []*RecordError
+		)
+		require.NoError(t, d.Lines(func(d *Decoder, r Record) error {
+			raw, err := d.Raw()
+			if err != nil {
+				return err
+			}
+			values = append(values, raw.String())
+			return nil
+		}, func(err *RecordError) error {
+			errs = append(errs, err)
+			return nil
+		}))
+		require.Equal(t, []string{`{"a": 1}`, `[1, 2]`, `1`, `"x"`}, values)
+		require.Len(t, errs, 3)
+
+		require.Equal(t, Record{Index: 1, Offset: 9}, errs[0].Record)
+
+		// Trailing data.
+		require.Equal(t, Record{Index: 3, Offset: 22}, errs[1].Record)
+		se, ok := errors.Into[*SyntaxError](errs[1])
+		require.True(t, ok)
+		require.Equal(t, SyntaxError{Token: '2', Offset: 24, Line: 4, Column: 3}, *se)
+
+		require.Equal(t, Record{Index: 5, Offset: 30}, errs[2].Record)
+		se, ok = errors.Into[*SyntaxError](errs[2])
+		require.True(t, ok)
+		require.Equal(t, 6, se.Line)
+		require.Equal(t, 4, se.Column)
+	})(t)
+
+	t.Run("Abort", testBufferReader(input, func(t *testing.T, d *Decoder) {
+		err := d.Lines(nil, nil)
+		var rerr *RecordError
+		require.ErrorAs(t, err, &rerr)
+		require.Equal(t, 1, rerr.Record.Index)
+
+		abort := errors.New("abort")
+		require.ErrorIs(t, DecodeStr(input).Lines(nil, func(err *RecordError) error {
+			return abort
+		}), abort)
+	}))
+	t.Run("Callback", testBufferReader(input, func(t *testing.T, d *Decoder) {
+		var indexes []int
+		require.NoError(t, d.Lines(func(d *Decoder, r Record) error {
+			if r.Index == 0 {
+				return errors.New("skip")
+			}
+			return d.Skip()
+		}, func(err *RecordError) error {
+			indexes = append(indexes, err.Record.Index)
+			return nil
+		}))
+		require.Equal(t, []int{0, 1, 3, 5}, indexes)
+	}))
+}
+
+func TestDecoder_LinesLimits(t *testing.T) {
+	const input = "[[1]]\n[1]\n"
+	testBufferReader(input, func(t *testing.T, d *Decoder) {
+		d.SetLimits(DecoderLimits{MaxDepth: 1})
+		var n int
+		require.NoError(t, d.Lines(nil, func(err *RecordError) error {
+			requireLimitErr(t, err, LimitDepth)
+			n++
+			return nil
+		}))
+		require.Equal(t, 1, n)
+	})(t)
+	t.Run("MaxBytes", testBufferReader(input, func(t *testing.T, d *Decoder) {
+		d.SetLimits(DecoderLimits{MaxBytes: 8})
+		err := d.Lines(nil, func(err *RecordError) error {
+			t.Fatalf("unexpected record error: %v", err)
+			return nil
+		})
+		requireLimitErr(t, err, LimitBytes)
+	}))
+}
+
+func TestDecoder_LinesExtensions(t *testing.T) {
+	const input = "// Comment.\n{a: 1,} // One.\n[2,]\n"
+	testBufferReader(input, func(t *testing.T, d *Decoder) {
+		d.SetExtensions(JSON5)
+		var n int
+		require.NoError(t, d.Lines(func(d *Decoder, r Record) error {
+			n++
+			return d.Skip()
+		}, nil))
+		require.Equal(t, 2, n)
+	})(t)
+}
+
+func TestDecoder_LinesDuplicateKeys(t *testing.T) {
+	const input = "{\"a\": 1}\n{\"a\": 1, \"a\": 2}\n"
+	t.Run("Reject", testBufferReader(input, func(t *testing.T, d *Decoder) {
+		d.SetDuplicateKeys(DuplicateKeysReject)
+		var indexes []int
+		require.NoError(t, d.Lines(nil, func(err *RecordError) error {
+			var de *DuplicateKeyError
+			require.ErrorAs(t, err, &de)
+			require.Equal(t, "a", de.Key)
+			indexes = append(indexes, err.Record.Index)
+			return nil
+		}))
+		require.Equal(t, []int{1}, indexes)
+	}))
+}
+
+func TestDecoder_LinesUTF8(t *testing.T) {
+	const input = "\"a\"\n\"\xff\"\n"
+	t.Run("Reject", testBufferReader(input, func(t *testing.T, d *Decoder) {
+		d.SetUTF8Policy(UTF8Reject)
+		var indexes []int
+		require.NoError(t, d.Lines(nil, func(err *RecordError) error {
+			indexes = append(indexes, err.Record.Index)
+			return nil
+		}))
+		require.Equal(t, []int{1}, indexes)
+	}))
+	t.Run("Replace", testBufferReader(input, func(t *testing.T, d *Decoder) {
+		d.SetUTF8Policy(UTF8Replace)
+		var values []string
+		require.NoError(t, d.Lines(func(d *Decoder, r Record) error {
+			s, err := d.Str()
+			values = append(values, s)
+			return err
+		}, nil))
+		require.Equal(t, []string{"a", "\uFFFD"}, values)
+	}))
+}
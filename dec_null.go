@@ -17,7 +17,7 @@ func (d *Decoder) Null() error {
 
 	if string(buf[:]) != "null" {
 		const encodedNull = 'n' | 'u'<<8 | 'l'<<16 | 'l'<<24
-		return findInvalidToken4(buf, encodedNull, offset)
+		return d.findInvalidToken4(buf, encodedNull, offset)
 	}
 	return nil
 }
@@ -30,24 +30,27 @@ func (d *Decoder) num(v Num, forceAppend bool) (Num, error) {
 
 		// Validate number.
 		{
-			d := Decoder{}
-			d.ResetBytes(str.buf)
+			nd := Decoder{}
+			nd.ResetBytes(str.buf)
 
-			c, err := d.next()
+			c, err := nd.next()
 			if err != nil {
 				return Num{}, err
 			}
 			switch c {
 			case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9', '-':
-				d.unread()
+				nd.unread()
 
-				if err := d.skipNumber(); err != nil {
+				if err := nd.skipNumber(); err != nil {
 					return Num{}, errors.Wrap(err, "skip number")
 				}
 			default:
-				return nil, badToken(c, offset)
+				return nil, d.badToken(c, offset)
 			}
 		}
+		if err := d.checkNumLen(offset+1, offset+1+len(str.buf)); err != nil {
+			return nil, err
+		}
 
 		// If string is escaped or decoder is streaming, copy it.
 		if !str.raw || forceAppend {
@@ -8,8 +8,13 @@ import (
 //
 // The key value is valid only until f is not returned.
 func (d *Decoder) ObjBytes(f func(d *Decoder, key []byte) error) error {
+	if f != nil && d.dup == DuplicateKeysLast {
+		if err := d.lastKeys(); err != nil {
+			return err
+		}
+	}
 	if err := d.consume('{'); err != nil {
-		return errors.Wrap(err, `"{" expected`)
+		return expected(err, `"{"`)
 	}
 	if f == nil {
 		return d.skipObj()
@@ -19,7 +24,7 @@ func (d *Decoder) ObjBytes(f func(d *Decoder, key []byte) error) error {
 	}
 	c, err := d.more()
 	if err != nil {
-		return errors.Wrap(err, `'"' or "}" expected`)
+		return expected(err, `'"' or "}"`)
 	}
 	if c == '}' {
 		return d.decDepth()
@@ -31,50 +36,83 @@ func (d *Decoder) ObjBytes(f func(d *Decoder, key []byte) error) error {
 	//
 	// See https://github.com/go-faster/jx/pull/62.
 	isBuffer := d.reader == nil
+	if d.dup == DuplicateKeysReject || d.dup == DuplicateKeysFirst {
+		d.startKeys()
+	}
 
-	k, err := d.str(value{raw: isBuffer})
+	if err := d.checkElements(1); err != nil {
+		return err
+	}
+	offset := d.offset()
+	k, err := d.key(value{raw: isBuffer})
 	if err != nil {
 		return errors.Wrap(err, "field name")
 	}
 	if err := d.consume(':'); err != nil {
-		return errors.Wrap(err, `":" expected`)
+		return expected(err, `":"`)
 	}
 	// Skip whitespace.
 	if _, err = d.more(); err != nil {
 		return err
 	}
 	d.unread()
-	if err := f(d, k.buf); err != nil {
-		return errors.Wrap(err, "callback")
+	skip := false
+	if d.dup != DuplicateKeysAllow {
+		if skip, err = d.dupKey(k.buf, offset, 1); err != nil {
+			return err
+		}
+	}
+	if !skip {
+		if err := f(d, k.buf); err != nil {
+			return d.withKey(err, k.buf, "callback")
+		}
 	}
 
 	c, err = d.more()
 	if err != nil {
-		return errors.Wrap(err, `"," or "}" expected`)
+		return expected(err, `"," or "}"`)
 	}
-	for c == ',' {
-		k, err := d.str(value{raw: isBuffer})
+	for n := 2; c == ','; n++ {
+		offset := d.offset()
+		if d.dup != DuplicateKeysAllow {
+			offset = d.keyOffset()
+		}
+		k, err := d.key(value{raw: isBuffer})
 		if err != nil {
+			if d.trailingComma(err, '}') {
+				return d.decDepth()
+			}
 			return errors.Wrap(err, "field name")
 		}
+		if err := d.checkElements(n); err != nil {
+			return err
+		}
 		if err := d.consume(':'); err != nil {
-			return errors.Wrap(err, `":" expected`)
+			return expected(err, `":"`)
 		}
 		// Check that value exists.
 		if _, err = d.more(); err != nil {
 			return err
 		}
 		d.unread()
-		if err := f(d, k.buf); err != nil {
-			return errors.Wrap(err, "callback")
+		skip := false
+		if d.dup != DuplicateKeysAllow {
+			if skip, err = d.dupKey(k.buf, offset, n); err != nil {
+				return err
+			}
+		}
+		if !skip {
+			if err := f(d, k.buf); err != nil {
+				return d.withKey(err, k.buf, "callback")
+			}
 		}
 		if c, err = d.more(); err != nil {
 			return err
 		}
 	}
 	if c != '}' {
-		err := badToken(c, d.offset()-1)
-		return errors.Wrap(err, `"}" expected`)
+		err := d.badToken(c, d.offset()-1)
+		return expected(err, `"," or "}"`)
 	}
 	return d.decDepth()
 }
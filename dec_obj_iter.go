@@ -10,16 +10,25 @@ type ObjIter struct {
 	isBuffer bool
 	closed   bool
 	comma    bool
+	n        int // count of elements
 }
 
 // ObjIter creates new object iterator.
 func (d *Decoder) ObjIter() (ObjIter, error) {
+	if d.dup == DuplicateKeysLast {
+		if err := d.lastKeys(); err != nil {
+			return ObjIter{}, err
+		}
+	}
 	if err := d.consume('{'); err != nil {
-		return ObjIter{}, errors.Wrap(err, `"{" expected`)
+		return ObjIter{}, expected(err, `"{"`)
 	}
 	if err := d.incDepth(); err != nil {
 		return ObjIter{}, err
 	}
+	if d.dup == DuplicateKeysReject || d.dup == DuplicateKeysFirst {
+		d.startKeys()
+	}
 	if _, err := d.more(); err != nil {
 		return ObjIter{}, err
 	}
@@ -41,6 +50,7 @@ func (i *ObjIter) Next() bool {
 	}
 
 	dec := i.d
+next:
 	c, err := dec.more()
 	if err != nil {
 		i.err = err
@@ -53,27 +63,40 @@ func (i *ObjIter) Next() bool {
 	}
 	if i.comma {
 		if c != ',' {
-			err := badToken(c, dec.offset()-1)
-			i.err = errors.Wrap(err, `"," expected`)
+			err := dec.badToken(c, dec.offset()-1)
+			i.err = expected(err, `","`)
 			return false
 		}
 	} else {
 		dec.unread()
 	}
-
-	k, err := dec.str(value{raw: i.isBuffer})
+	offset := dec.offset()
+	if dec.dup != DuplicateKeysAllow {
+		offset = dec.keyOffset()
+	}
+	k, err := dec.key(value{raw: i.isBuffer})
 	if err != nil {
+		if i.comma && dec.trailingComma(err, '}') {
+			i.closed = true
+			i.err = dec.decDepth()
+			return false
+		}
 		i.err = errors.Wrap(err, "field name")
 		return false
 	}
+	i.n++
+	if err := dec.checkElements(i.n); err != nil {
+		i.err = err
+		return false
+	}
 	if err := dec.consume(':'); err != nil {
-		i.err = errors.Wrap(err, `":" expected`)
+		i.err = expected(err, `":"`)
 		return false
 	}
 	// Skip whitespace.
 	if _, err = dec.more(); err != nil {
-		err := badToken(c, dec.offset()-1)
-		i.err = errors.Wrap(err, `"," or "}" expected`)
+		err := dec.badToken(c, dec.offset()-1)
+		i.err = expected(err, `"," or "}"`)
 		return false
 	}
 	dec.unread()
@@ -81,6 +104,16 @@ func (i *ObjIter) Next() bool {
 	i.comma = true
 	i.key = k.buf
 
+	if dec.dup != DuplicateKeysAllow {
+		skip, err := dec.dupKey(k.buf, offset, i.n)
+		if err != nil {
+			i.err = err
+			return false
+		}
+		if skip {
+			goto next
+		}
+	}
 	return true
 }
 
@@ -40,9 +40,9 @@ func TestDecoder_ObjBytes(t *testing.T) {
 			input = append(input, `{"1":`...)
 		}
 		d := DecodeBytes(input)
-		require.ErrorIs(t, d.ObjBytes(func(d *Decoder, key []byte) error {
+		requireLimitErr(t, d.ObjBytes(func(d *Decoder, key []byte) error {
 			return crawlValue(d)
-		}), errMaxDepth)
+		}), LimitDepth)
 	})
 	t.Run("Invalid", func(t *testing.T) {
 		for _, s := range testObjs {
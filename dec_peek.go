@@ -0,0 +1,130 @@
+package jx
+
+import "github.com/go-faster/errors"
+
+// peekSpan is offsets of value in input.
+type peekSpan struct {
+	start, end int
+}
+
+// Peek finds values of keys in upcoming object, leaving d positioned at
+// object start.
+//
+// Returns raw values in order of keys, value is nil if key is not found. If
+// key is duplicated, the first value is used. Scan stops when all keys are
+// found, other fields are skipped.
+//
+// Values are valid only until next read.
+func (d *Decoder) Peek(keys ...string) ([]Raw, error) {
+	spans := make([]peekSpan, len(keys))
+	raws := make([]Raw, len(keys))
+	m := d.Mark()
+	defer d.Rewind(m)
+
+	if err := d.lookahead(keys, spans); err != nil {
+		return nil, err
+	}
+	// Buffer is pinned, so it contains all found values.
+	for i, s := range spans {
+		if s.end > 0 {
+			raws[i] = d.buf[s.start-d.streamOffset : s.end-d.streamOffset]
+		}
+	}
+	return raws, nil
+}
+
+// PeekType returns type of key value in upcoming object, leaving d
+// positioned at object start.
+//
+// Returns Invalid if key is not found.
+func (d *Decoder) PeekType(key string) (Type, error) {
+	var spans [1]peekSpan
+	m := d.Mark()
+	defer d.Rewind(m)
+
+	if err := d.lookahead([]string{key}, spans[:]); err != nil {
+		return Invalid, err
+	}
+	if spans[0].end == 0 {
+		return Invalid, nil
+	}
+	c := d.buf[spans[0].start-d.streamOffset]
+	if t := types[c]; t != Invalid || d.ext == 0 {
+		return t, nil
+	}
+	return d.typeExt(c), nil
+}
+
+// lookahead consumes object until all keys are found, saving offsets of
+// their values to spans.
+//
+// Like skipObj, but depth is not decremented, caller must rewind.
+func (d *Decoder) lookahead(keys []string, spans []peekSpan) error {
+	if err := d.consume('{'); err != nil {
+		return expected(err, `"{"`)
+	}
+	if err := d.incDepth(); err != nil {
+		return errors.Wrap(err, "inc")
+	}
+	c, err := d.more()
+	if err != nil {
+		return expected(err, `'"' or "}"`)
+	}
+	switch {
+	case c == '}':
+		return nil
+	case c == '"' || d.keyStartExt(c):
+		d.unread()
+	default:
+		return expected(d.badToken(c, d.offset()-1), `'"' or "}"`)
+	}
+
+	left := len(keys)
+	for n := 1; ; n++ {
+		key, err := d.skipKeyCopy()
+		if err != nil {
+			if n > 1 && d.trailingComma(err, '}') {
+				return nil
+			}
+			return err
+		}
+		if err := d.checkElements(n); err != nil {
+			return err
+		}
+		if err := d.consume(':'); err != nil {
+			return expected(err, `":"`)
+		}
+		// Skip whitespace.
+		if _, err := d.more(); err != nil {
+			return err
+		}
+		d.unread()
+
+		start := d.offset()
+		if err := d.Skip(); err != nil {
+			return d.withKey(err, key, "")
+		}
+		for i, k := range keys {
+			if spans[i].end == 0 && string(key) == k {
+				spans[i] = peekSpan{start: start, end: d.offset()}
+				left--
+			}
+		}
+		if left == 0 {
+			return nil
+		}
+
+		c, err := d.more()
+		if err != nil {
+			return expected(err, `"," or "}"`)
+		}
+		switch c {
+		case ',':
+			continue
+		case '}':
+			return nil
+		default:
+			return expected(d.badToken(c, d.offset()-1), `"," or "}"`)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package jx
+
+import (
+	"testing"
+
+	"github.com/go-faster/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecoder_Peek(t *testing.T) {
+	const input = `{"foo": {"a": [1, 2]}, "type": "bar", "id": 10, "type": "baz"}`
+	testBufferReader(input, func(t *testing.T, d *Decoder) {
+		a := require.New(t)
+		raws, err := d.Peek("type", "missing", "id")
+		a.NoError(err)
+		a.Len(raws, 3)
+		a.Equal(`"bar"`, raws[0].String())
+		a.Nil(raws[1])
+		a.Equal(`10`, raws[2].String())
+
+		typ, err := d.PeekType("id")
+		a.NoError(err)
+		a.Equal(Number, typ)
+		typ, err = d.PeekType("missing")
+		a.NoError(err)
+		a.Equal(Invalid, typ)
+
+		// Decoder is positioned at object start.
+		raw, err := d.Raw()
+		a.NoError(err)
+		a.Equal(input, raw.String())
+	})(t)
+	t.Run("Nested", testBufferReader(`[{"type": "foo"}, {"type": 1}]`, func(t *testing.T, d *Decoder) {
+		a := require.New(t)
+		var types []Type
+		a.NoError(d.Arr(func(d *Decoder) error {
+			typ, err := d.PeekType("type")
+			if err != nil {
+				return err
+			}
+			types = append(types, typ)
+			return d.Obj(func(d *Decoder, key string) error {
+				return d.Skip()
+			})
+		}))
+		a.Equal([]Type{String, Number}, types)
+	}))
+	t.Run("Extensions", testBufferReader(`{type: Infinity, // Comment.
+}`, func(t *testing.T, d *Decoder) {
+		a := require.New(t)
+		d.SetExtensions(JSON5)
+		typ, err := d.PeekType("type")
+		a.NoError(err)
+		a.Equal(Number, typ)
+	}))
+	t.Run("Escaped", testBufferReader(`{"ty\u0070e": true}`, func(t *testing.T, d *Decoder) {
+		typ, err := d.PeekType("type")
+		require.NoError(t, err)
+		require.Equal(t, Bool, typ)
+	}))
+	t.Run("Error", testBufferReader(`{"foo": [1, }`, func(t *testing.T, d *Decoder) {
+		_, err := d.PeekType("type")
+		se, ok := errors.Into[*SyntaxError](err)
+		require.True(t, ok)
+		require.Equal(t, "/foo/1", se.Pointer)
+	}))
+	t.Run("Invalid", func(t *testing.T) {
+		for _, input := range []string{
+			`[]`,
+			`{"foo": }`,
+			`{"foo": 1`,
+		} {
+			d := DecodeStr(input)
+			_, err := d.Peek("type")
+			require.Error(t, err, input)
+			_, err = d.PeekType("type")
+			require.Error(t, err, input)
+		}
+	})
+}
+
+func BenchmarkDecoder_PeekType(b *testing.B) {
+	input := []byte(`{"foo": {"a": [1, 2]}, "bar": "baz", "type": "bar"}`)
+	d := DecodeBytes(input)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(input)))
+
+	for i := 0; i < b.N; i++ {
+		d.ResetBytes(input)
+		if _, err := d.PeekType("type"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
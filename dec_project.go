@@ -0,0 +1,368 @@
+package jx
+
+import "github.com/go-faster/errors"
+
+// ProjectionRules are rules of Projection.
+//
+// Rules are RFC 6901 JSON Pointers, where token "*" matches any field or
+// element.
+type ProjectionRules struct {
+	// Include lists values to keep, with all nested values. If empty, all
+	// values are kept.
+	Include []string
+	// Exclude lists values to remove.
+	Exclude []string
+	// Mask lists values to replace with MaskValue.
+	Mask []string
+	// MaskValue replaces masked values, null if empty.
+	MaskValue Raw
+}
+
+// Projection is compiled ProjectionRules, see Decoder.Project.
+type Projection struct {
+	root projNode
+	mask Raw
+}
+
+// projAction is set of rules matching value.
+type projAction byte
+
+const (
+	projInclude projAction = 1 << iota
+	projExclude
+	projMask
+	projNested // some nested value is included
+)
+
+// projNode is node of prefix tree of rules.
+type projNode struct {
+	action projAction
+	fields map[string]*projNode
+	elems  map[int]*projNode
+	any    *projNode // "*" token
+}
+
+// CompileProjection compiles projection rules.
+func CompileProjection(r ProjectionRules) (*Projection, error) {
+	p := &Projection{mask: r.MaskValue}
+	if len(p.mask) == 0 {
+		p.mask = Raw("null")
+	} else if err := DecodeBytes(p.mask).Validate(); err != nil {
+		return nil, errors.Wrap(err, "mask value")
+	}
+	for _, rule := range []struct {
+		ptrs   []string
+		action projAction
+	}{
+		{r.Include, projInclude},
+		{r.Exclude, projExclude},
+		{r.Mask, projMask},
+	} {
+		for _, ptr := range rule.ptrs {
+			if ptr == "" && rule.action == projExclude {
+				return nil, errors.New("root value can not be excluded")
+			}
+			n := &p.root
+			for rest := ptr; rest != ""; {
+				tok, next, err := splitPointer(rest)
+				if err != nil {
+					return nil, err
+				}
+				n = n.child(tok)
+				rest = next
+			}
+			n.action |= rule.action
+		}
+	}
+	if len(r.Include) == 0 {
+		p.root.action |= projInclude
+	}
+	p.root.compile()
+	return p, nil
+}
+
+// child returns child node for reference token, adding it if needed.
+func (n *projNode) child(tok string) *projNode {
+	if tok == "*" {
+		if n.any == nil {
+			n.any = &projNode{}
+		}
+		return n.any
+	}
+	if c, ok := n.fields[tok]; ok {
+		return c
+	}
+	c := &projNode{}
+	if n.fields == nil {
+		n.fields = map[string]*projNode{}
+	}
+	n.fields[tok] = c
+	if idx := pointerIndex(tok); idx >= 0 {
+		if n.elems == nil {
+			n.elems = map[int]*projNode{}
+		}
+		n.elems[idx] = c
+	}
+	return c
+}
+
+// merge adds rules of src to n.
+func (n *projNode) merge(src *projNode) {
+	n.action |= src.action
+	for tok, c := range src.fields {
+		n.child(tok).merge(c)
+	}
+	if src.any != nil {
+		n.child("*").merge(src.any)
+	}
+}
+
+// compile adds rules of "*" to other children, so every value is matched
+// by single node, and sets projNested.
+func (n *projNode) compile() {
+	if n.any != nil {
+		for _, c := range n.fields {
+			c.merge(n.any)
+		}
+		n.any.compile()
+		if n.any.action&(projInclude|projNested) != 0 {
+			n.action |= projNested
+		}
+	}
+	for _, c := range n.fields {
+		c.compile()
+		if c.action&(projInclude|projNested) != 0 {
+			n.action |= projNested
+		}
+	}
+}
+
+// leaf reports whether n has no children.
+func (n *projNode) leaf() bool {
+	return n.fields == nil && n.any == nil
+}
+
+func (n *projNode) field(key []byte) *projNode {
+	if c, ok := n.fields[string(key)]; ok {
+		return c
+	}
+	return n.any
+}
+
+func (n *projNode) elem(i int) *projNode {
+	if c, ok := n.elems[i]; ok {
+		return c
+	}
+	return n.any
+}
+
+// Project reads json value and writes it to e, keeping only values
+// selected by p.
+//
+// Values selected as a whole are copied as Raw. For io.Reader input,
+// arrays and objects are copied by elements instead, so memory usage is
+// bounded by the largest scalar value, not by the whole value. Keys of
+// filtered objects are re-encoded.
+//
+// Nested object or array that is not included itself is written only if
+// some value is selected from it, e.g. Include "/a/b" writes {} for
+// {"a":{"c":1}}. Root value is always written, unless p selects nothing
+// from scalar value.
+func (d *Decoder) Project(p *Projection, e *Encoder) error {
+	if !p.root.selected(d, false) {
+		return d.Skip()
+	}
+	pr := projector{p: p, e: e, depth: d.depth}
+	return d.project(&pr, &p.root, false, nil, false)
+}
+
+// ProjectWriter is Project that writes to w.
+func (d *Decoder) ProjectWriter(p *Projection, w *Writer) error {
+	e := Encoder{w: *w}
+	err := d.Project(p, &e)
+	*w = e.w
+	return err
+}
+
+// selected reports whether upcoming value matched by n is written.
+//
+// Node n can be nil, then value is written only if parent is included.
+func (n *projNode) selected(d *Decoder, inc bool) bool {
+	switch {
+	case n == nil:
+		return inc
+	case n.action&projExclude != 0:
+		return false
+	case inc || n.action&projInclude != 0:
+		return true
+	case n.action&projNested != 0:
+		// Only nested values are written.
+		tt := d.Next()
+		return tt == Object || tt == Array
+	default:
+		return false
+	}
+}
+
+// projector is state of Decoder.Project.
+type projector struct {
+	p     *Projection
+	e     *Encoder
+	depth int // depth of root value
+	// open are containers that are not written until some nested value is
+	// selected.
+	open []projOpen
+	keys []byte // field names of open
+}
+
+// projOpen is container that is not written yet.
+type projOpen struct {
+	obj   bool
+	field bool // container is value of field
+	key   int  // start of field name in keys, ends at start of next one
+}
+
+// begin writes open containers and field name of upcoming value.
+func (pr *projector) begin(key []byte, field bool) (fail bool) {
+	e := pr.e
+	for i, o := range pr.open {
+		if o.field {
+			end := len(pr.keys)
+			if i+1 < len(pr.open) {
+				end = pr.open[i+1].key
+			}
+			if e.fieldStartBytes(pr.keys[o.key:end]) {
+				return true
+			}
+		}
+		if o.obj && e.ObjStart() || !o.obj && e.ArrStart() {
+			return true
+		}
+	}
+	pr.open = pr.open[:0]
+	pr.keys = pr.keys[:0]
+	return field && e.fieldStartBytes(key)
+}
+
+// project writes upcoming value matched by n, which is selected.
+//
+// If field is true, value is written with key as field name.
+func (d *Decoder) project(pr *projector, n *projNode, inc bool, key []byte, field bool) error {
+	e := pr.e
+	if n == nil {
+		if pr.begin(key, field) {
+			return e.writeErr()
+		}
+		return d.projectCopy(e)
+	}
+	if n.action&projMask != 0 {
+		if pr.begin(key, field) {
+			return e.writeErr()
+		}
+		if err := d.Skip(); err != nil {
+			return err
+		}
+		if e.Raw(pr.p.mask) {
+			return e.writeErr()
+		}
+		return nil
+	}
+	inc = inc || n.action&projInclude != 0
+	tt := d.Next()
+	if inc && n.leaf() || tt != Object && tt != Array {
+		if pr.begin(key, field) {
+			return e.writeErr()
+		}
+		return d.projectCopy(e)
+	}
+
+	// Nested value that is not included is written on first selected
+	// value in it.
+	lazy := !inc && d.depth > pr.depth
+	if lazy {
+		pr.open = append(pr.open, projOpen{obj: tt == Object, field: field, key: len(pr.keys)})
+		pr.keys = append(pr.keys, key...)
+	} else if pr.begin(key, field) || tt == Object && e.ObjStart() || tt == Array && e.ArrStart() {
+		return e.writeErr()
+	}
+	level := len(pr.open)
+
+	var err error
+	if tt == Object {
+		err = d.ObjBytes(func(d *Decoder, key []byte) error {
+			c := n.field(key)
+			if !c.selected(d, inc) {
+				return d.Skip()
+			}
+			return d.project(pr, c, inc, key, true)
+		})
+	} else {
+		var i int
+		err = d.Arr(func(d *Decoder) error {
+			c := n.elem(i)
+			i++
+			if !c.selected(d, inc) {
+				return d.Skip()
+			}
+			return d.project(pr, c, inc, nil, false)
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	if lazy && len(pr.open) == level {
+		// Nothing is selected, container is not written.
+		pr.keys = pr.keys[:pr.open[level-1].key]
+		pr.open = pr.open[:level-1]
+		return nil
+	}
+	if tt == Object && e.ObjEnd() || tt == Array && e.ArrEnd() {
+		return e.writeErr()
+	}
+	return nil
+}
+
+// projectCopy copies upcoming value to e.
+func (d *Decoder) projectCopy(e *Encoder) error {
+	switch tt := d.Next(); {
+	case tt == Object && d.reader != nil:
+		if e.ObjStart() {
+			return e.writeErr()
+		}
+		if err := d.ObjBytes(func(d *Decoder, key []byte) error {
+			if e.fieldStartBytes(key) {
+				return e.writeErr()
+			}
+			return d.projectCopy(e)
+		}); err != nil {
+			return err
+		}
+		if e.ObjEnd() {
+			return e.writeErr()
+		}
+		return nil
+	case tt == Array && d.reader != nil:
+		if e.ArrStart() {
+			return e.writeErr()
+		}
+		if err := d.Arr(func(d *Decoder) error {
+			return d.projectCopy(e)
+		}); err != nil {
+			return err
+		}
+		if e.ArrEnd() {
+			return e.writeErr()
+		}
+		return nil
+	default:
+		raw, err := d.Raw()
+		if err != nil {
+			return err
+		}
+		if e.Raw(raw) {
+			return e.writeErr()
+		}
+		return nil
+	}
+}
@@ -0,0 +1,205 @@
+package jx
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecoder_Project(t *testing.T) {
+	const input = `{
+  "id": 1,
+  "users": [
+    {"name": "foo", "email": "foo@example.com", "tags": ["a", "b"]},
+    {"name": "bar", "email": "bar@example.com", "ssn": "123"}
+  ],
+  "meta": {"total": 2, "next": null}
+}`
+	for _, tt := range []struct {
+		Name   string
+		Rules  ProjectionRules
+		Expect string
+	}{
+		{
+			Name:   "All",
+			Expect: `{"id":1,"users":[{"name":"foo","email":"foo@example.com","tags":["a","b"]},{"name":"bar","email":"bar@example.com","ssn":"123"}],"meta":{"total":2,"next":null}}`,
+		},
+		{
+			Name: "Exclude",
+			Rules: ProjectionRules{
+				Exclude: []string{"/users/*/email", "/users/1/ssn", "/meta/next"},
+			},
+			Expect: `{"id":1,"users":[{"name":"foo","tags":["a","b"]},{"name":"bar"}],"meta":{"total":2}}`,
+		},
+		{
+			Name: "Include",
+			Rules: ProjectionRules{
+				Include: []string{"/id", "/users/*/name", "/users/0/tags/1", "/missing/x"},
+			},
+			Expect: `{"id":1,"users":[{"name":"foo","tags":["b"]},{"name":"bar"}]}`,
+		},
+		{
+			Name: "IncludeExclude",
+			Rules: ProjectionRules{
+				Include: []string{"/users"},
+				Exclude: []string{"/users/0", "/users/*/tags"},
+			},
+			Expect: `{"users":[{"name":"bar","email":"bar@example.com","ssn":"123"}]}`,
+		},
+		{
+			Name: "Mask",
+			Rules: ProjectionRules{
+				Mask: []string{"/users/*/email", "/users/*/ssn", "/meta/total"},
+			},
+			Expect: `{"id":1,"users":[{"name":"foo","email":null,"tags":["a","b"]},{"name":"bar","email":null,"ssn":null}],"meta":{"total":null,"next":null}}`,
+		},
+		{
+			Name: "MaskValue",
+			Rules: ProjectionRules{
+				Include:   []string{"/users/*/email", "/id"},
+				Mask:      []string{"/users/*/email", "/meta"},
+				MaskValue: Raw(`"***"`),
+			},
+			Expect: `{"id":1,"users":[{"email":"***"},{"email":"***"}]}`,
+		},
+		{
+			Name: "ScalarNotObject",
+			Rules: ProjectionRules{
+				Include: []string{"/id/x", "/users/0/name/y"},
+			},
+			Expect: `{}`,
+		},
+		{
+			Name: "IncludeMissing",
+			Rules: ProjectionRules{
+				Include: []string{"/users/*/tags/5", "/meta/next/x", "/meta/total"},
+			},
+			Expect: `{"meta":{"total":2}}`,
+		},
+		{
+			Name: "IncludeExcluded",
+			Rules: ProjectionRules{
+				Include: []string{"/users/0/tags", "/meta/next"},
+				Exclude: []string{"/users/0/tags/*", "/meta"},
+			},
+			Expect: `{"users":[{"tags":[]}]}`,
+		},
+	} {
+		t.Run(tt.Name, testBufferReader(input+` {}`, func(t *testing.T, d *Decoder) {
+			p, err := CompileProjection(tt.Rules)
+			require.NoError(t, err)
+
+			var e Encoder
+			require.NoError(t, d.Project(p, &e))
+			// Raw values keep formatting of buffered input.
+			require.Equal(t, tt.Expect, strings.NewReplacer(" ", "", "\n", "").Replace(e.String()))
+			// Value is consumed.
+			require.Equal(t, Object, d.Next())
+		}))
+	}
+	t.Run("NothingSelected", func(t *testing.T) {
+		p, err := CompileProjection(ProjectionRules{Include: []string{"/a/b"}})
+		require.NoError(t, err)
+		for input, expect := range map[string]string{
+			`{"a":{"c":1},"x":{"y":2}}`:     `{}`,
+			`{"a":[{"c":1}]}`:               `{}`,
+			`[{"a":{"c":1}},{"a":{"b":1}}]`: `[]`,
+			`{"x":1,"a":{"c":{},"b":[1]}}`:  `{"a":{"b":[1]}}`,
+		} {
+			t.Run(input, testBufferReader(input, func(t *testing.T, d *Decoder) {
+				var e Encoder
+				require.NoError(t, d.Project(p, &e))
+				require.Equal(t, expect, e.String())
+			}))
+		}
+		p, err = CompileProjection(ProjectionRules{Include: []string{"/*/a/b"}})
+		require.NoError(t, err)
+		const input = `[{"a":{"c":1}},{"x":2,"a":{"b":1}},{"a":[]}]`
+		t.Run(input, testBufferReader(input, func(t *testing.T, d *Decoder) {
+			var e Encoder
+			require.NoError(t, d.Project(p, &e))
+			require.Equal(t, `[{"a":{"b":1}}]`, e.String())
+		}))
+	})
+	t.Run("Scalar", func(t *testing.T) {
+		p, err := CompileProjection(ProjectionRules{Include: []string{"/a"}})
+		require.NoError(t, err)
+		d := DecodeStr(`1 2`)
+		var e Encoder
+		require.NoError(t, d.Project(p, &e))
+		require.Empty(t, e.Bytes())
+		v, err := d.Int()
+		require.NoError(t, err)
+		require.Equal(t, 2, v)
+	})
+	t.Run("Error", func(t *testing.T) {
+		p, err := CompileProjection(ProjectionRules{Exclude: []string{"/a"}})
+		require.NoError(t, err)
+		var e Encoder
+		d := DecodeStr(`{"a": [tru], "b": 1}`)
+		var se *SyntaxError
+		require.ErrorAs(t, d.Project(p, &e), &se)
+		require.Equal(t, "/a/0", se.Pointer)
+	})
+	t.Run("Compile", func(t *testing.T) {
+		for _, r := range []ProjectionRules{
+			{Exclude: []string{""}},
+			{Include: []string{"a"}},
+			{Mask: []string{"/~2"}},
+			{MaskValue: Raw(`{`)},
+		} {
+			_, err := CompileProjection(r)
+			require.Error(t, err)
+		}
+	})
+}
+
+func TestDecoder_ProjectStreaming(t *testing.T) {
+	p, err := CompileProjection(ProjectionRules{
+		Exclude: []string{"/*/secret"},
+	})
+	require.NoError(t, err)
+
+	var (
+		input  bytes.Buffer
+		expect bytes.Buffer
+	)
+	input.WriteString("[")
+	expect.WriteString("[")
+	long := strings.Repeat("x", 1024)
+	for i := 0; i < 1000; i++ {
+		if i > 0 {
+			input.WriteString(",")
+			expect.WriteString(",")
+		}
+		input.WriteString(`{"secret":"s","data":["` + long + `",{"n":1}]}`)
+		expect.WriteString(`{"data":["` + long + `",{"n":1}]}`)
+	}
+	input.WriteString("]")
+	expect.WriteString("]")
+
+	d := Decode(iotest.HalfReader(bytes.NewReader(input.Bytes())), 512)
+	var out bytes.Buffer
+	e := NewStreamingEncoder(&out, 512)
+	require.NoError(t, d.Project(p, e))
+	require.NoError(t, e.Close())
+	require.Equal(t, expect.String(), out.String())
+	// Memory is bounded by the largest string, not by input.
+	require.Less(t, cap(d.buf), 4*len(long))
+
+	t.Run("Writer", func(t *testing.T) {
+		var w Writer
+		d := DecodeBytes(input.Bytes())
+		require.NoError(t, d.ProjectWriter(p, &w))
+		require.Equal(t, expect.String(), w.String())
+	})
+	t.Run("WriteError", func(t *testing.T) {
+		d := DecodeBytes(input.Bytes())
+		e := NewStreamingEncoder(&errWriter{err: io.ErrClosedPipe}, 512)
+		require.ErrorIs(t, d.Project(p, e), io.ErrClosedPipe)
+	})
+}
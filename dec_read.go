@@ -11,26 +11,43 @@ func (d *Decoder) Next() Type {
 	if err == nil {
 		d.unread()
 	}
-	return types[v]
+	if t := types[v]; t != Invalid || d.ext == 0 {
+		return t
+	}
+	return d.typeExt(v)
 }
 
 var spaceSet = [256]byte{
 	' ': 1, '\n': 1, '\t': 1, '\r': 1,
+	'/': 2, // comment, if ExtComments is set
 }
 
 func (d *Decoder) consume(c byte) (err error) {
+scan:
 	for {
 		buf := d.buf[d.head:d.tail]
 		for i, got := range buf {
 			switch spaceSet[got] {
+			case 1:
+				continue
+			case 2:
+				if d.ext&ExtComments != 0 {
+					d.head += i + 1
+					if err := d.skipComment(); err != nil {
+						if err == io.EOF {
+							err = io.ErrUnexpectedEOF
+						}
+						return err
+					}
+					continue scan
+				}
+				fallthrough
 			default:
 				if c != got {
-					return badToken(got, d.offset()+i)
+					return d.badToken(got, d.offset()+i)
 				}
 				d.head += i + 1
 				return nil
-			case 1:
-				continue
 			}
 		}
 		if err = d.read(); err != nil {
@@ -53,15 +70,25 @@ func (d *Decoder) more() (byte, error) {
 
 // next reads next non-whitespace token or error.
 func (d *Decoder) next() (byte, error) {
+scan:
 	for {
 		buf := d.buf[d.head:d.tail]
 		for i, c := range buf {
 			switch spaceSet[c] {
+			case 1:
+				continue
+			case 2:
+				if d.ext&ExtComments != 0 {
+					d.head += i + 1
+					if err := d.skipComment(); err != nil {
+						return 0, err
+					}
+					continue scan
+				}
+				fallthrough
 			default:
 				d.head += i + 1
 				return c, nil
-			case 1:
-				continue
 			}
 		}
 		if err := d.read(); err != nil {
@@ -97,10 +124,21 @@ func (d *Decoder) byte() (byte, error) {
 }
 
 func (d *Decoder) read() error {
+	if d.cut {
+		return d.cutErr()
+	}
 	if d.reader == nil {
 		d.head = d.tail
 		return io.EOF
 	}
+	if d.pins > 0 {
+		return d.readPinned(1)
+	}
+	d.saveSkipped()
+
+	// Buffer would be overwritten, so count lines first.
+	lines, lineStart := d.lines, d.lineStart
+	d.trackLines(d.tail)
 
 	n, err := d.reader.Read(d.buf)
 	switch err {
@@ -111,26 +149,39 @@ func (d *Decoder) read() error {
 		}
 		fallthrough
 	default:
+		d.lines, d.lineStart = lines, lineStart
 		return err
 	}
 
 	d.streamOffset += d.tail
 	d.head = 0
 	d.tail = n
+	d.applyMaxBytes()
 	return nil
 }
 
 func (d *Decoder) readAtLeast(min int) error {
+	if d.cut {
+		return d.cutErr()
+	}
 	if d.reader == nil {
 		d.head = d.tail
 		return io.ErrUnexpectedEOF
 	}
+	if d.pins > 0 {
+		return d.readPinned(min)
+	}
+	d.saveSkipped()
 
 	if need := min - len(d.buf); need > 0 {
 		d.buf = append(d.buf, make([]byte, need)...)
 	}
+	lines, lineStart := d.lines, d.lineStart
+	d.trackLines(d.tail)
+
 	n, err := io.ReadAtLeast(d.reader, d.buf, min)
 	if err != nil {
+		d.lines, d.lineStart = lines, lineStart
 		if err == io.EOF && n == 0 {
 			return io.ErrUnexpectedEOF
 		}
@@ -140,9 +191,53 @@ func (d *Decoder) readAtLeast(min int) error {
 	d.streamOffset += d.tail
 	d.head = 0
 	d.tail = n
+	d.applyMaxBytes()
+	return nil
+}
+
+// readPinned reads at least min bytes, keeping buffer contents after
+// pinned offset, see pin.
+//
+// Like read, sets head to start of new data.
+func (d *Decoder) readPinned(min int) error {
+	// Discard buffer before pinned offset.
+	d.saveSkipped()
+	if n := d.pinOffset - d.streamOffset; n > 0 {
+		d.trackLines(n)
+		d.streamOffset += n
+		d.head -= n
+		d.tail = copy(d.buf, d.buf[n:d.tail])
+	}
+	if free := len(d.buf) - d.tail; free < min || free < len(d.buf)/4 {
+		size := 2 * len(d.buf)
+		if size < d.tail+min {
+			size = d.tail + min
+		}
+		buf := make([]byte, size)
+		copy(buf, d.buf[:d.tail])
+		d.buf = buf
+	}
+
+	n, err := io.ReadAtLeast(d.reader, d.buf[d.tail:], min)
+	if err != nil {
+		if err == io.EOF && min > 1 {
+			return io.ErrUnexpectedEOF
+		}
+		return err
+	}
+
+	d.head = d.tail
+	d.tail += n
+	d.applyMaxBytes()
 	return nil
 }
 
+// cutErr returns error for read beyond limits.MaxBytes.
+func (d *Decoder) cutErr() error {
+	d.head = d.tail
+	return d.limitErr(LimitBytes, d.limits.MaxBytes, d.offset())
+}
+
 func (d *Decoder) unread() { d.head-- }
 
 func (d *Decoder) readExact4(b *[4]byte) error {
@@ -159,8 +254,8 @@ func (d *Decoder) readExact4(b *[4]byte) error {
 	return nil
 }
 
-func findInvalidToken4(buf [4]byte, mask uint32, offset int) error {
+func (d *Decoder) findInvalidToken4(buf [4]byte, mask uint32, offset int) error {
 	c := uint32(buf[0]) | uint32(buf[1])<<8 | uint32(buf[2])<<16 | uint32(buf[3])<<24
 	idx := bits.TrailingZeros32(c^mask) / 8
-	return badToken(buf[idx], offset+idx)
+	return d.badToken(buf[idx], offset+idx)
 }
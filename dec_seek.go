@@ -0,0 +1,305 @@
+package jx
+
+import (
+	"bytes"
+
+	"github.com/go-faster/errors"
+)
+
+// ErrNotFound is returned by Seek if value referenced by pointer is not
+// found.
+var ErrNotFound = errors.New("value not found")
+
+// Seek walks to value referenced by RFC 6901 JSON Pointer, like
+// "/data/0/name", leaving d positioned at that value.
+//
+// Empty pointer references the whole upcoming value. Preceding siblings of
+// values on the path are skipped, rest of enclosing arrays and objects is
+// not consumed. If key is repeated, the first field is used.
+//
+// Decoder stays inside of enclosing arrays and objects, so they are
+// counted by MaxDepth limit when reading the value. Use SeekEnd to skip
+// rest of them after the value is read.
+//
+// Returns error wrapping ErrNotFound if there is no such value. Then d is
+// left after the value it was searched in.
+func (d *Decoder) Seek(ptr string) error {
+	// Drop containers left since previous Seek, e.g. by Rewind.
+	for n := len(d.seek); n > 0 && d.seek[n-1].depth > d.depth; n-- {
+		d.seek = d.seek[:n-1]
+	}
+	for rest := ptr; rest != ""; {
+		tok, next, err := splitPointer(rest)
+		if err != nil {
+			return err
+		}
+		c, err := d.more()
+		if err != nil {
+			return err
+		}
+		d.unread()
+
+		var (
+			at    = ptr[:len(ptr)-len(rest)]
+			found bool
+		)
+		switch c {
+		case '{':
+			found, err = d.seekField(tok, at)
+		case '[':
+			found, err = d.seekElem(tok, at)
+		default:
+			err = d.Skip()
+		}
+		if err != nil {
+			return d.withPointer(err, at)
+		}
+		if !found {
+			return errors.Wrapf(ErrNotFound, "%q", ptr[:len(ptr)-len(next)])
+		}
+		rest = next
+	}
+	return nil
+}
+
+// SeekEnd skips rest of arrays and objects entered by Seek, leaving d
+// after the value that contains them, so the next value of stream can be
+// read or sought.
+//
+// Value found by Seek must be read or skipped before.
+func (d *Decoder) SeekEnd() error {
+	for i := len(d.seek) - 1; i >= 0; i-- {
+		l := d.seek[i]
+		d.seek = d.seek[:i]
+		if l.depth > d.depth {
+			// Already left, e.g. by Rewind.
+			continue
+		}
+
+		var err error
+		if l.obj {
+			err = d.seekObjEnd(l.n)
+		} else {
+			err = d.seekArrEnd(l.n)
+		}
+		if err != nil {
+			return d.withPointer(err, l.ptr)
+		}
+	}
+	return nil
+}
+
+// seekLevel is array or object entered by Seek, see SeekEnd.
+type seekLevel struct {
+	ptr   string // pointer to container
+	obj   bool
+	n     int // count of elements up to the found one
+	depth int
+}
+
+// seekEnter keeps container at ptr, which is entered up to n-th element.
+func (d *Decoder) seekEnter(ptr string, obj bool, n int) {
+	d.seek = append(d.seek, seekLevel{ptr: ptr, obj: obj, n: n, depth: d.depth})
+}
+
+// seekField walks to value of field with given key in upcoming object at
+// ptr.
+func (d *Decoder) seekField(key, ptr string) (bool, error) {
+	if err := d.consume('{'); err != nil {
+		return false, err
+	}
+	if err := d.incDepth(); err != nil {
+		return false, errors.Wrap(err, "inc")
+	}
+	c, err := d.more()
+	if err != nil {
+		return false, expected(err, `'"' or "}"`)
+	}
+	switch {
+	case c == '}':
+		return false, d.decDepth()
+	case c == '"' || d.keyStartExt(c):
+		d.unread()
+	default:
+		return false, expected(d.badToken(c, d.offset()-1), `'"' or "}"`)
+	}
+
+	for n := 1; ; n++ {
+		k, err := d.seekKey()
+		if err != nil {
+			if n > 1 && d.trailingComma(err, '}') {
+				return false, d.decDepth()
+			}
+			return false, err
+		}
+		if err := d.checkElements(n); err != nil {
+			return false, err
+		}
+		if err := d.consume(':'); err != nil {
+			return false, expected(err, `":"`)
+		}
+		if string(k) == key {
+			d.seekEnter(ptr, true, n)
+			return true, d.skipSpace()
+		}
+		if err := d.Skip(); err != nil {
+			return false, d.withKey(err, k, "")
+		}
+
+		c, err := d.more()
+		if err != nil {
+			return false, expected(err, `"," or "}"`)
+		}
+		switch c {
+		case ',':
+			continue
+		case '}':
+			return false, d.decDepth()
+		default:
+			return false, expected(d.badToken(c, d.offset()-1), `"," or "}"`)
+		}
+	}
+}
+
+// seekObjEnd skips rest of object after n fields.
+func (d *Decoder) seekObjEnd(n int) error {
+	for ; ; n++ {
+		c, err := d.more()
+		if err != nil {
+			return expected(err, `"," or "}"`)
+		}
+		switch c {
+		case ',':
+		case '}':
+			return d.decDepth()
+		default:
+			return expected(d.badToken(c, d.offset()-1), `"," or "}"`)
+		}
+
+		k, err := d.seekKey()
+		if err != nil {
+			if d.trailingComma(err, '}') {
+				return d.decDepth()
+			}
+			return err
+		}
+		if err := d.checkElements(n + 1); err != nil {
+			return err
+		}
+		if err := d.consume(':'); err != nil {
+			return expected(err, `":"`)
+		}
+		if err := d.Skip(); err != nil {
+			return d.withKey(err, k, "")
+		}
+	}
+}
+
+// seekKey reads object key, returning decoded key.
+//
+// Keys without escapes are not copied for buffered input.
+func (d *Decoder) seekKey() ([]byte, error) {
+	if c, err := d.more(); err == nil && c == '"' && d.reader == nil && d.utf8 != UTF8Replace {
+		start := d.head
+		if err := d.skipStr(); err == nil {
+			if key := d.buf[start : d.head-1]; bytes.IndexByte(key, '\\') < 0 {
+				return key, nil
+			}
+		}
+		// Decode key or report error.
+		d.head = start - 1
+	} else if err == nil {
+		d.unread()
+	}
+	return d.skipKeyCopy()
+}
+
+// seekElem walks to element of upcoming array at ptr with index from tok.
+func (d *Decoder) seekElem(tok, ptr string) (bool, error) {
+	idx := pointerIndex(tok)
+	if idx < 0 {
+		return false, d.Skip()
+	}
+	if err := d.consume('['); err != nil {
+		return false, err
+	}
+	if err := d.incDepth(); err != nil {
+		return false, errors.Wrap(err, "inc")
+	}
+	c, err := d.more()
+	if err != nil {
+		return false, expected(err, `value or "]"`)
+	}
+	if c == ']' {
+		return false, d.decDepth()
+	}
+	d.unread()
+
+	for n := 1; n <= idx; n++ {
+		if err := d.checkElements(n); err != nil {
+			return false, err
+		}
+		if err := d.Skip(); err != nil {
+			return false, d.withIndex(err, n-1, "")
+		}
+		c, err := d.more()
+		if err != nil {
+			return false, expected(err, `"," or "]"`)
+		}
+		switch c {
+		case ',':
+			if d.ext&ExtTrailingCommas == 0 {
+				continue
+			}
+			if c, err = d.more(); err != nil {
+				return false, err
+			}
+			if c == ']' {
+				return false, d.decDepth()
+			}
+			d.unread()
+		case ']':
+			return false, d.decDepth()
+		default:
+			return false, expected(d.badToken(c, d.offset()-1), `"," or "]"`)
+		}
+	}
+	if err := d.checkElements(idx + 1); err != nil {
+		return false, err
+	}
+	d.seekEnter(ptr, false, idx+1)
+	return true, d.skipSpace()
+}
+
+// seekArrEnd skips rest of array after n elements.
+func (d *Decoder) seekArrEnd(n int) error {
+	for ; ; n++ {
+		c, err := d.more()
+		if err != nil {
+			return expected(err, `"," or "]"`)
+		}
+		switch c {
+		case ',':
+			if d.ext&ExtTrailingCommas != 0 {
+				if c, err = d.more(); err != nil {
+					return err
+				}
+				if c == ']' {
+					return d.decDepth()
+				}
+				d.unread()
+			}
+		case ']':
+			return d.decDepth()
+		default:
+			return expected(d.badToken(c, d.offset()-1), `"," or "]"`)
+		}
+
+		if err := d.checkElements(n + 1); err != nil {
+			return err
+		}
+		if err := d.Skip(); err != nil {
+			return d.withIndex(err, n, "")
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package jx
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/go-faster/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecoder_Seek(t *testing.T) {
+	const input = `{
+  "data": [
+    {"id": 1, "attributes": {"name": "foo", "tags": ["a", "b"]}},
+    {"id": 2, "attributes": {"name": "bar", "a/b": 1, "m~n": 2, "": 3, "q\"r": 4, "x": 5}}
+  ],
+  "data": "repeated",
+  "empty": {},
+  "0": "zero"
+}`
+	for _, tt := range []struct {
+		Ptr    string
+		Expect string
+	}{
+		{"", input},
+		{"/data/0/id", `1`},
+		{"/data/0/attributes/name", `"foo"`},
+		{"/data/0/attributes/tags/1", `"b"`},
+		{"/data/1/attributes/name", `"bar"`},
+		{"/data/1/attributes/a~1b", `1`},
+		{"/data/1/attributes/m~0n", `2`},
+		{"/data/1/attributes/", `3`},
+		{`/data/1/attributes/q"r`, `4`},
+		{"/data/1/attributes/x", `5`},
+		{"/empty", `{}`},
+		{"/0", `"zero"`},
+	} {
+		t.Run(tt.Ptr, testBufferReader(input, func(t *testing.T, d *Decoder) {
+			require.NoError(t, d.Seek(tt.Ptr))
+			raw, err := d.Raw()
+			require.NoError(t, err)
+			require.Equal(t, tt.Expect, raw.String())
+		}))
+	}
+	t.Run("NotFound", func(t *testing.T) {
+		for _, tt := range []struct {
+			Ptr    string
+			Prefix string
+		}{
+			{"/missing", "/missing"},
+			{"/data/2", "/data/2"},
+			{"/data/-", "/data/-"},
+			{"/data/01", "/data/01"},
+			{"/data/x/id", "/data/x"},
+			{"/data/0/id/foo", "/data/0/id/foo"},
+			{"/empty/a", "/empty/a"},
+			{"/data/0/attributes/tags/2", "/data/0/attributes/tags/2"},
+		} {
+			t.Run(tt.Ptr, testBufferReader(input, func(t *testing.T, d *Decoder) {
+				err := d.Seek(tt.Ptr)
+				require.ErrorIs(t, err, ErrNotFound)
+				require.EqualError(t, err, fmt.Sprintf("%q: value not found", tt.Prefix))
+			}))
+		}
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		for _, ptr := range []string{
+			"data",
+			"/data/~2",
+			"/data~",
+		} {
+			d := DecodeStr(input)
+			err := d.Seek(ptr)
+			require.Error(t, err, ptr)
+			require.False(t, errors.Is(err, ErrNotFound), ptr)
+		}
+	})
+	t.Run("Syntax", func(t *testing.T) {
+		for _, tt := range []struct {
+			Input  string
+			Ptr    string
+			Expect string
+		}{
+			{`{"a": [1, tru], "b": 1}`, "/b", "/a/1"},
+			// Pointer includes path to enclosing value.
+			{`{"c": {"x": [nul, 1], "y": 1}}`, "/c/y", "/c/x/0"},
+			{`{"c": {"x": [nul, 1], "y": 1}}`, "/c/x/1", "/c/x/0"},
+			{`[0, {"a~b": [{}, tru]}]`, "/1/a~0b/2", "/1/a~0b/1"},
+		} {
+			t.Run(tt.Ptr, testBufferReader(tt.Input, func(t *testing.T, d *Decoder) {
+				var se *SyntaxError
+				require.ErrorAs(t, d.Seek(tt.Ptr), &se)
+				require.Equal(t, tt.Expect, se.Pointer)
+			}))
+		}
+	})
+	t.Run("Depth", func(t *testing.T) {
+		d := DecodeStr(`{"a": {"b": [1, 2]}} {"c": 3}`)
+		require.NoError(t, d.Seek("/a/b/1"))
+		require.Equal(t, 3, d.depth)
+		v, err := d.Int()
+		require.NoError(t, err)
+		require.Equal(t, 2, v)
+	})
+	t.Run("End", func(t *testing.T) {
+		for _, tt := range []struct {
+			Name   string
+			Input  string
+			Ptrs   []string
+			Values []int
+		}{
+			{"Repeated", `{"a":{"b":1}} {"a":{"b":2}}`, []string{"/a/b", "/a/b"}, []int{1, 2}},
+			{"Siblings", `{"a": [1, {"b": 2, "c": [3]}, 4], "d": {"e": 5}} [6, 7]`, []string{"/a/1/b", "/1"}, []int{2, 7}},
+			{"Root", `1 {"a": 2}`, []string{"", "/a"}, []int{1, 2}},
+		} {
+			tt := tt
+			t.Run(tt.Name, testBufferReader(tt.Input, func(t *testing.T, d *Decoder) {
+				for i, ptr := range tt.Ptrs {
+					require.NoError(t, d.Seek(ptr))
+					v, err := d.Int()
+					require.NoError(t, err)
+					require.Equal(t, tt.Values[i], v)
+					require.NoError(t, d.SeekEnd())
+					require.Zero(t, d.depth)
+				}
+				require.ErrorIs(t, d.Skip(), io.EOF)
+			}))
+		}
+		t.Run("NotFound", testBufferReader(strings.Repeat(`{"a": {"b": [1]}, "c": 2} `, 4), func(t *testing.T, d *Decoder) {
+			for _, ptr := range []string{"/a/x", "/a/b/-", "/c/d", "/x"} {
+				require.ErrorIs(t, d.Seek(ptr), ErrNotFound)
+				require.NoError(t, d.SeekEnd())
+				require.Zero(t, d.depth, ptr)
+			}
+			require.ErrorIs(t, d.Skip(), io.EOF)
+		}))
+		t.Run("TrailingComma", testBufferReader(`{"a": [1,], "b": 2,} [3]`, func(t *testing.T, d *Decoder) {
+			d.SetExtensions(ExtTrailingCommas)
+			require.NoError(t, d.Seek("/a/0"))
+			require.NoError(t, d.Skip())
+			require.NoError(t, d.SeekEnd())
+			require.NoError(t, d.Seek("/0"))
+		}))
+		t.Run("Syntax", func(t *testing.T) {
+			for _, tt := range []struct {
+				Input  string
+				Ptr    string
+				Expect string
+			}{
+				{`{"a": {"b": 1, "c": tru}}`, "/a/b", "/a/c"},
+				{`[[1, 2, x]]`, "/0/0", "/0/2"},
+				{`{"a": [1], "b": [nul]}`, "/a/0", "/b/0"},
+			} {
+				tt := tt
+				t.Run(tt.Ptr, testBufferReader(tt.Input, func(t *testing.T, d *Decoder) {
+					require.NoError(t, d.Seek(tt.Ptr))
+					require.NoError(t, d.Skip())
+					var se *SyntaxError
+					require.ErrorAs(t, d.SeekEnd(), &se)
+					require.Equal(t, tt.Expect, se.Pointer)
+				}))
+			}
+		})
+	})
+	t.Run("MaxDepth", func(t *testing.T) {
+		const input = `{"a": {"b": [[1], 2]}}`
+		t.Run("Value", testBufferReader(input, func(t *testing.T, d *Decoder) {
+			d.SetLimits(DecoderLimits{MaxDepth: 3})
+			require.NoError(t, d.Seek("/a/b"))
+			// Enclosing objects are counted.
+			var le *LimitError
+			require.ErrorAs(t, d.Skip(), &le)
+			require.Equal(t, LimitDepth, le.Limit)
+			require.Equal(t, 3, le.Max)
+		}))
+		t.Run("Seek", testBufferReader(input, func(t *testing.T, d *Decoder) {
+			d.SetLimits(DecoderLimits{MaxDepth: 3})
+			var le *LimitError
+			require.ErrorAs(t, d.Seek("/a/b/0/0"), &le)
+			require.Equal(t, LimitDepth, le.Limit)
+		}))
+	})
+	t.Run("TrailingComma", testBufferReader(`{"a": [1,], "b": {"c": 1,},}`, func(t *testing.T, d *Decoder) {
+		d.SetExtensions(ExtTrailingCommas)
+		require.ErrorIs(t, d.Seek("/a/1"), ErrNotFound)
+	}))
+}
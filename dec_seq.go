@@ -0,0 +1,26 @@
+package jx
+
+// seqSeparator is record separator of json text sequences.
+const seqSeparator = 0x1e
+
+// Seq reads RFC 7464 json text sequence (application/json-seq), calling f
+// for every non-blank record.
+//
+// Records are separated by RS (0x1E) byte. Number, true, false and null
+// that are not followed by whitespace are treated as truncated.
+//
+// See Lines for f and onErr semantics.
+func (d *Decoder) Seq(f func(d *Decoder, r Record) error, onErr func(err *RecordError) error) error {
+	return d.records(seqSeparator, f, onErr)
+}
+
+// seqTruncated reports whether value of json text sequence record can be
+// truncated, see RFC 7464 Section 2.4.
+func (d *Decoder) seqTruncated(rec []byte) bool {
+	switch d.Next() {
+	case Number, Bool, Null:
+		return spaceSet[rec[len(rec)-1]] != 1
+	default:
+		return false
+	}
+}
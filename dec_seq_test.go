@@ -0,0 +1,86 @@
+package jx
+
+import (
+	"io"
+	"testing"
+
+	"github.com/go-faster/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecoder_Seq(t *testing.T) {
+	const input = "\x1e{\"a\": 1}\n\x1e[1,\n2]\n\x1e\x1e \n\x1e\"s\"\n\x1e1\n"
+	testBufferReader(input, func(t *testing.T, d *Decoder) {
+		var (
+			records []Record
+			values  []string
+		)
+		require.NoError(t, d.Seq(func(d *Decoder, r Record) error {
+			raw, err := d.Raw()
+			if err != nil {
+				return err
+			}
+			records = append(records, r)
+			values = append(values, raw.String())
+			return nil
+		}, nil))
+		require.Equal(t, []Record{
+			{Index: 0, Offset: 1},
+			{Index: 1, Offset: 11},
+			{Index: 2, Offset: 23},
+			{Index: 3, Offset: 28},
+		}, records)
+		require.Equal(t, []string{`{"a": 1}`, "[1,\n2]", `"s"`, `1`}, values)
+	})(t)
+}
+
+func TestDecoder_SeqErrors(t *testing.T) {
+	const input = "\x1e123\x1e{\"a\":\n\x1e[1]\n\x1e{} {}\n\x1e\"s\"\n\x1etrue"
+	testBufferReader(input, func(t *testing.T, d *Decoder) {
+		var (
+			values []string
+			errs   []*RecordError
+		)
+		require.NoError(t, d.Seq(func(d *Decoder, r Record) error {
+			raw, err := d.Raw()
+			if err != nil {
+				return err
+			}
+			values = append(values, raw.String())
+			return nil
+		}, func(err *RecordError) error {
+			errs = append(errs, err)
+			return nil
+		}))
+		require.Equal(t, []string{`[1]`, `{}`, `"s"`}, values)
+
+		var indexes []int
+		for _, err := range errs {
+			indexes = append(indexes, err.Record.Index)
+		}
+		require.Equal(t, []int{0, 1, 3, 5}, indexes)
+		require.ErrorIs(t, errs[0], io.ErrUnexpectedEOF)
+		require.ErrorIs(t, errs[3], io.ErrUnexpectedEOF)
+
+		se, ok := errors.Into[*SyntaxError](errs[2])
+		require.True(t, ok)
+		require.Equal(t, 3, se.Line)
+		require.Equal(t, 5, se.Column)
+	})(t)
+}
+
+func TestDecoder_SeqDuplicateKeys(t *testing.T) {
+	const input = "\x1e{\"a\": 1, \"a\": 2}\n\x1e{\"a\": 1}\n"
+	t.Run("Last", testBufferReader(input, func(t *testing.T, d *Decoder) {
+		d.SetDuplicateKeys(DuplicateKeysLast)
+		var values []int
+		require.NoError(t, d.Seq(func(d *Decoder, r Record) error {
+			return d.Obj(func(d *Decoder, key string) error {
+				v, err := d.Int()
+				values = append(values, v)
+				return err
+			})
+		}, nil))
+		require.Equal(t, []int{2, 1}, values)
+	}))
+}
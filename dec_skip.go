@@ -4,10 +4,29 @@ import (
 	"io"
 
 	"github.com/go-faster/errors"
+
+	"github.com/go-faster/jx/internal/scan"
 )
 
 // Skip skips a json object and positions to relatively the next json object.
 func (d *Decoder) Skip() error {
+	if d.slow || d.head == d.tail || !d.canSkipFast() {
+		// Empty buffer is end of input or needs read.
+		return d.skip()
+	}
+	if n := skipFast(d.buf[d.head:d.tail], d.depthLimit()-d.depth); n >= 0 {
+		d.head += n
+		return nil
+	}
+	// Value is invalid or too deep, slow path reports error.
+	d.slow = true
+	err := d.skip()
+	d.slow = false
+	return err
+}
+
+// skip is Skip without fast path.
+func (d *Decoder) skip() error {
 	c, err := d.next()
 	if err != nil {
 		return err
@@ -27,6 +46,10 @@ func (d *Decoder) Skip() error {
 		return err
 	case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
 		d.unread()
+		if d.ext&extNumbers != 0 {
+			_, err := d.numberExt(nil)
+			return err
+		}
 		return d.skipNumber()
 	case '[':
 		if err := d.skipArr(); err != nil {
@@ -39,7 +62,10 @@ func (d *Decoder) Skip() error {
 		}
 		return nil
 	default:
-		return badToken(c, d.offset()-1)
+		if d.ext != 0 {
+			return d.skipExt(c)
+		}
+		return d.badToken(c, d.offset()-1)
 	}
 }
 
@@ -68,6 +94,26 @@ var skipNumberSet = [256]byte{
 //
 // Assumes d.buf is not empty.
 func (d *Decoder) skipNumber() error {
+	if d.limits.MaxNumLen <= 0 {
+		return d.skipNumberUnlimited()
+	}
+	start := d.offset()
+	if err := d.skipNumberUnlimited(); err != nil {
+		return err
+	}
+	return d.checkNumLen(start, d.offset())
+}
+
+// skipNumberUnlimited is skipNumber without MaxNumLen check.
+func (d *Decoder) skipNumberUnlimited() error {
+	// Fast path for valid number which is terminated in buffer, errors are
+	// reported by slow path.
+	buf := d.buf[d.head:d.tail]
+	if n := scan.Number(buf); n > 0 && n < len(buf) {
+		d.head += n
+		return nil
+	}
+
 	const (
 		digitTag  byte = 1
 		closerTag byte = 2
@@ -82,7 +128,7 @@ func (d *Decoder) skipNumber() error {
 		}
 		// Character after '-' must be a digit.
 		if skipNumberSet[c] != digitTag {
-			return badToken(c, d.offset()-1)
+			return d.badToken(c, d.offset()-1)
 		}
 		if c != '0' {
 			break
@@ -111,7 +157,10 @@ func (d *Decoder) skipNumber() error {
 		case 'e', 'E':
 			goto stateExp
 		default:
-			return badToken(c, d.offset())
+			if d.commentStart(c) {
+				return nil
+			}
+			return d.badToken(c, d.offset())
 		}
 	}
 	for {
@@ -132,7 +181,11 @@ func (d *Decoder) skipNumber() error {
 				d.head += i
 				goto stateExp
 			default:
-				return badToken(c, d.offset()+i)
+				if d.commentStart(c) {
+					d.head += i
+					return nil
+				}
+				return d.badToken(c, d.offset()+i)
 			}
 		}
 
@@ -168,12 +221,16 @@ stateDot:
 				switch c {
 				case 'e', 'E':
 					if last == '.' {
-						return badToken(c, d.offset()+i)
+						return d.badToken(c, d.offset()+i)
 					}
 					d.head += i
 					goto stateExp
 				default:
-					return badToken(c, d.offset()+i)
+					if d.commentStart(c) && last != '.' {
+						d.head += i
+						return nil
+					}
+					return d.badToken(c, d.offset()+i)
 				}
 			}
 
@@ -207,10 +264,10 @@ stateExp:
 				}
 				// There must be a number after sign.
 				if skipNumberSet[num] != digitTag {
-					return badToken(num, d.offset()-1)
+					return d.badToken(num, d.offset()-1)
 				}
 			} else {
-				return badToken(numOrSign, d.offset()-1)
+				return d.badToken(numOrSign, d.offset()-1)
 			}
 		}
 	}
@@ -221,7 +278,11 @@ stateExp:
 				return nil
 			}
 			if skipNumberSet[c] == 0 {
-				return badToken(c, d.offset()+i)
+				if d.commentStart(c) {
+					d.head += i
+					return nil
+				}
+				return d.badToken(c, d.offset()+i)
 			}
 		}
 
@@ -265,73 +326,25 @@ var (
 //
 // Assumes first quote was consumed.
 func (d *Decoder) skipStr() error {
+	if d.utf8 == UTF8Reject {
+		return d.skipStrUTF8()
+	}
 	var (
-		c byte
-		i int
+		c     byte
+		i     int
+		start = d.offset()
 	)
 readStr:
 	for {
-		i = 0
 		buf := d.buf[d.head:d.tail]
-		for len(buf) >= 8 {
-			c = buf[0]
-			if safeSet[c] != 0 {
-				goto readTok
-			}
-			i++
-
-			c = buf[1]
-			if safeSet[c] != 0 {
-				goto readTok
-			}
-			i++
-
-			c = buf[2]
-			if safeSet[c] != 0 {
-				goto readTok
-			}
-			i++
-
-			c = buf[3]
-			if safeSet[c] != 0 {
-				goto readTok
-			}
-			i++
-
-			c = buf[4]
-			if safeSet[c] != 0 {
-				goto readTok
-			}
-			i++
-
-			c = buf[5]
-			if safeSet[c] != 0 {
-				goto readTok
-			}
-			i++
-
-			c = buf[6]
-			if safeSet[c] != 0 {
-				goto readTok
-			}
-			i++
-
-			c = buf[7]
-			if safeSet[c] != 0 {
-				goto readTok
-			}
-			i++
-
-			buf = buf[8:]
-		}
-		var n int
-		for n, c = range buf {
-			if safeSet[c] != 0 {
-				i += n
-				goto readTok
-			}
+		if i = scan.Str(buf); i < len(buf) {
+			c = buf[i]
+			goto readTok
 		}
 
+		if err := d.checkStrLen(start, d.streamOffset+d.tail); err != nil {
+			return err
+		}
 		if err := d.read(); err != nil {
 			if err == io.EOF {
 				err = io.ErrUnexpectedEOF
@@ -345,7 +358,7 @@ readTok:
 	switch {
 	case c == '"':
 		d.head += i + 1
-		return nil
+		return d.checkStrLen(start, d.offset()-1)
 	case c == '\\':
 		d.head += i + 1
 		v, err := d.byte()
@@ -360,14 +373,19 @@ readTok:
 					return err
 				}
 				if hexSet[h] == 0 {
-					return badToken(h, d.offset()-1)
+					return d.badToken(h, d.offset()-1)
 				}
 			}
 		case 0:
-			return badToken(v, d.offset()-1)
+			if d.ext == 0 {
+				return d.badToken(v, d.offset()-1)
+			}
+			if _, err := d.escapedCharExt(value{}, v); err != nil {
+				return err
+			}
 		}
 	case c < ' ':
-		return badToken(c, d.offset()+i)
+		return d.badToken(c, d.offset()+i)
 	}
 	goto readStr
 }
@@ -382,33 +400,65 @@ func (d *Decoder) skipObj() error {
 
 	c, err := d.more()
 	if err != nil {
-		return errors.Wrap(err, `'"' or "}" expected`)
+		return expected(err, `'"' or "}"`)
 	}
-	switch c {
-	case '}':
+	switch {
+	case c == '}':
 		return d.decDepth()
-	case '"':
+	case c == '"' || d.keyStartExt(c):
 		d.unread()
 	default:
-		return badToken(c, d.offset()-1)
+		return expected(d.badToken(c, d.offset()-1), `'"' or "}"`)
 	}
 
-	for {
-		if err := d.consume('"'); err != nil {
-			return errors.Wrap(err, `'"' expected`)
+	level := d.depth
+	dup := d.dup == DuplicateKeysReject
+	if dup {
+		d.startKeys()
+	}
+	for n := 1; ; n++ {
+		var (
+			key    []byte
+			offset int
+			err    error
+		)
+		if dup {
+			// Keys must be decoded to be compared.
+			offset = d.keyOffset()
+			key, err = d.skipKeyCopy()
+		} else {
+			key, err = d.skipKey()
 		}
-		if err := d.skipStr(); err != nil {
-			return errors.Wrap(err, "read field name")
+		if err != nil {
+			if n > 1 && d.trailingComma(err, '}') {
+				return d.decDepth()
+			}
+			return err
+		}
+		if err := d.checkElements(n); err != nil {
+			return err
 		}
 		if err := d.consume(':'); err != nil {
-			return errors.Wrap(err, `":" expected`)
+			return expected(err, `":"`)
+		}
+		if dup && !d.keySets[d.depth-1].put(key, n, false) {
+			return d.dupKeyErr(key, offset)
 		}
 		if err := d.Skip(); err != nil {
-			return err
+			switch {
+			case dup:
+				return d.withKey(err, key, "")
+			case key == nil:
+				return d.withRawKey(err, d.skippedKey(level), "")
+			case d.reader == nil:
+				return d.withRawKey(err, key, "")
+			default:
+				return d.withKey(err, key, "")
+			}
 		}
 		c, err := d.more()
 		if err != nil {
-			return errors.Wrap(err, `"," or "}" expected`)
+			return expected(err, `"," or "}"`)
 		}
 		switch c {
 		case ',':
@@ -416,11 +466,119 @@ func (d *Decoder) skipObj() error {
 		case '}':
 			return d.decDepth()
 		default:
-			return badToken(c, d.offset()-1)
+			return expected(d.badToken(c, d.offset()-1), `"," or "}"`)
 		}
 	}
 }
 
+// skipKey reads object key, returning it for error reporting.
+//
+// If d is buffered, returns raw key as sub-slice of d.buf. Otherwise,
+// returns nil and keeps raw key for skippedKey, because skipping of value
+// may overwrite it.
+func (d *Decoder) skipKey() ([]byte, error) {
+	if err := d.consume('"'); err != nil {
+		if !d.keyExt(err) {
+			return nil, expected(err, `'"'`)
+		}
+		return d.skipKeyCopy()
+	}
+	if d.reader == nil {
+		start := d.head
+		if err := d.skipStr(); err != nil {
+			return nil, errors.Wrap(err, "read field name")
+		}
+		return d.buf[start : d.head-1], nil
+	}
+
+	start := d.offset()
+	if buf := d.buf[d.head:d.tail]; d.utf8 != UTF8Reject {
+		// Key without escapes is usually in buffer and needs no pin.
+		if i := scan.Str(buf); i < len(buf) && buf[i] == '"' {
+			d.head += i + 1
+			if err := d.checkStrLen(start, d.offset()-1); err != nil {
+				return nil, errors.Wrap(err, "read field name")
+			}
+			d.setSkipped(start)
+			return nil, nil
+		}
+	}
+
+	// Key is pinned, so it is not discarded by reads while skipping.
+	d.pin()
+	err := d.skipStr()
+	d.unpin()
+	if err != nil {
+		return nil, errors.Wrap(err, "read field name")
+	}
+	d.setSkipped(start)
+	return nil, nil
+}
+
+// setSkipped keeps raw key of object being skipped at current depth, which
+// starts at given offset and ends before current one.
+func (d *Decoder) setSkipped(start int) {
+	for len(d.skipped) < d.depth {
+		if n := len(d.skipped); n < cap(d.skipped) {
+			// Reuse buffer of saved key.
+			d.skipped = d.skipped[:n+1]
+			continue
+		}
+		d.skipped = append(d.skipped, skippedKey{})
+	}
+	k := &d.skipped[d.depth-1]
+	k.start, k.end = start, d.offset()-1
+	k.saved = false
+}
+
+// skippedKey is raw key of object being skipped from reader, see skipKey.
+type skippedKey struct {
+	start, end int    // offsets of key in stream
+	raw        []byte // copy of key, if saved
+	saved      bool
+}
+
+// saveSkipped copies raw keys of objects being skipped before buffer is
+// overwritten.
+func (d *Decoder) saveSkipped() {
+	for i := 0; i < len(d.skipped) && i < d.depth; i++ {
+		k := &d.skipped[i]
+		if k.saved || k.start < d.streamOffset || k.end > d.streamOffset+d.tail {
+			continue
+		}
+		k.raw = append(k.raw[:0], d.buf[k.start-d.streamOffset:k.end-d.streamOffset]...)
+		k.saved = true
+	}
+}
+
+// skippedKey returns raw key of object being skipped at given depth.
+func (d *Decoder) skippedKey(depth int) []byte {
+	if depth < 1 || depth > len(d.skipped) {
+		return nil
+	}
+	k := &d.skipped[depth-1]
+	if k.saved {
+		return k.raw
+	}
+	if k.start < d.streamOffset || k.end > d.streamOffset+d.tail {
+		return nil
+	}
+	return d.buf[k.start-d.streamOffset : k.end-d.streamOffset]
+}
+
+// skipKeyCopy reads object key, copying it to internal buffer.
+func (d *Decoder) skipKeyCopy() ([]byte, error) {
+	for len(d.keys) < d.depth {
+		d.keys = append(d.keys, nil)
+	}
+	k, err := d.key(value{buf: d.keys[d.depth-1][:0]})
+	if err != nil {
+		return nil, errors.Wrap(err, "read field name")
+	}
+	d.keys[d.depth-1] = k.buf
+	return k.buf, nil
+}
+
 // skipArr reads JSON array.
 //
 // Assumes first bracket was consumed.
@@ -431,28 +589,40 @@ func (d *Decoder) skipArr() error {
 
 	c, err := d.more()
 	if err != nil {
-		return errors.Wrap(err, `value or "]" expected`)
+		return expected(err, `value or "]"`)
 	}
 	if c == ']' {
 		return d.decDepth()
 	}
 	d.unread()
 
-	for {
-		if err := d.Skip(); err != nil {
+	for n := 1; ; n++ {
+		if err := d.checkElements(n); err != nil {
 			return err
 		}
+		if err := d.Skip(); err != nil {
+			return d.withIndex(err, n-1, "")
+		}
 		c, err := d.more()
 		if err != nil {
-			return errors.Wrap(err, `"," or "]" expected`)
+			return expected(err, `"," or "]"`)
 		}
 		switch c {
 		case ',':
-			continue
+			if d.ext&ExtTrailingCommas == 0 {
+				continue
+			}
+			if c, err = d.more(); err != nil {
+				return err
+			}
+			if c == ']' {
+				return d.decDepth()
+			}
+			d.unread()
 		case ']':
 			return d.decDepth()
 		default:
-			return badToken(c, d.offset()-1)
+			return expected(d.badToken(c, d.offset()-1), `"," or "]"`)
 		}
 	}
 }
@@ -0,0 +1,459 @@
+package jx
+
+import (
+	"math/bits"
+
+	"github.com/go-faster/jx/internal/scan"
+)
+
+// skipFastDepth is maximum nesting depth handled by skipFast.
+const skipFastDepth = 1024
+
+// canSkipFast reports whether Skip can use skipFast.
+//
+// Fast path is used only for buffered input with default settings, which
+// need no state besides depth.
+func (d *Decoder) canSkipFast() bool {
+	return d.reader == nil &&
+		d.ext == 0 &&
+		d.utf8 != UTF8Reject &&
+		d.dup != DuplicateKeysReject &&
+		d.limits.MaxStrLen == 0 &&
+		d.limits.MaxNumLen == 0 &&
+		d.limits.MaxElements == 0 &&
+		d.limits.MaxBytes == 0
+}
+
+// skipFast returns length of valid json value at the start of b, including
+// leading whitespace, or -1 if value is invalid, incomplete or deeper than
+// maxDepth.
+//
+// Accepts the same values as Skip, so error can be reported by Skip itself.
+// Containers are skipped by skipFastBlocks if scan.Block is vectorised, and
+// by skipFastBytes otherwise or if b is shorter than a block.
+func skipFast(b []byte, maxDepth int) int {
+	i := skipFastSpace(b, 0)
+	if i == len(b) {
+		return -1
+	}
+	switch b[i] {
+	case '"':
+		return skipFastStr(b, i+1)
+	case '{', '[':
+		if maxDepth <= 0 {
+			return -1
+		}
+		if maxDepth > skipFastDepth {
+			maxDepth = skipFastDepth
+		}
+		if scan.BlockFast && len(b)-i >= 64 {
+			return skipFastBlocks(b, i, maxDepth)
+		}
+		return skipFastBytes(b, i, maxDepth)
+	default:
+		n := skipFastScalar(b[i:])
+		if n == 0 {
+			return -1
+		}
+		return i + n
+	}
+}
+
+// skipFastScalar returns length of literal or number at the start of b,
+// which is not empty, or 0 if there is none.
+func skipFastScalar(b []byte) int {
+	switch b[0] {
+	case 'n':
+		if len(b) < 4 || string(b[:4]) != "null" {
+			return 0
+		}
+		return 4
+	case 't':
+		if len(b) < 4 || string(b[:4]) != "true" {
+			return 0
+		}
+		return 4
+	case 'f':
+		if len(b) < 5 || string(b[:5]) != "false" {
+			return 0
+		}
+		return 5
+	default:
+		return scan.Number(b)
+	}
+}
+
+// skipFastBytes is skipFast for container at i, which reads b byte by byte.
+func skipFastBytes(b []byte, i, maxDepth int) int {
+	var (
+		// Bit is set for object and unset for array, by depth.
+		stack [skipFastDepth / 64]uint64
+		depth int
+	)
+
+value:
+	i = skipFastSpace(b, i)
+	if i == len(b) {
+		return -1
+	}
+	switch b[i] {
+	case '"':
+		if i = skipFastStr(b, i+1); i < 0 {
+			return -1
+		}
+	case '{':
+		if depth == maxDepth {
+			return -1
+		}
+		stack[depth/64] |= 1 << (depth % 64)
+		depth++
+		if i = skipFastSpace(b, i+1); i < len(b) && b[i] == '}' {
+			i++
+			depth--
+			break
+		}
+		goto key
+	case '[':
+		if depth == maxDepth {
+			return -1
+		}
+		stack[depth/64] &^= 1 << (depth % 64)
+		depth++
+		if i = skipFastSpace(b, i+1); i < len(b) && b[i] == ']' {
+			i++
+			depth--
+			break
+		}
+		goto value
+	default:
+		n := skipFastScalar(b[i:])
+		if n == 0 {
+			return -1
+		}
+		i += n
+	}
+
+	// End of value, i is offset of the next byte.
+	for depth > 0 {
+		if i = skipFastSpace(b, i); i == len(b) {
+			return -1
+		}
+		obj := stack[(depth-1)/64]&(1<<((depth-1)%64)) != 0
+		switch c := b[i]; {
+		case c == ',':
+			i++
+			if obj {
+				goto key
+			}
+			goto value
+		case c == '}' && obj, c == ']' && !obj:
+			i++
+			depth--
+		default:
+			return -1
+		}
+	}
+	return i
+
+key:
+	if i = skipFastSpace(b, i); i == len(b) || b[i] != '"' {
+		return -1
+	}
+	if i = skipFastStr(b, i+1); i < 0 {
+		return -1
+	}
+	if i = skipFastSpace(b, i); i == len(b) || b[i] != ':' {
+		return -1
+	}
+	i++
+	goto value
+}
+
+// Expected structural characters in skipFastBlocks.
+const (
+	skipValue      = iota
+	skipValueOrEnd // after '['
+	skipKey
+	skipKeyOrEnd // after '{'
+	skipColon
+	skipNext // ',' or closing bracket after value
+)
+
+// skipFastBlocks is skipFast for container at i, which visits only
+// structural characters found by structIndex.
+//
+// Whitespace and strings are never read here: strings are validated by
+// structIndex and the next structural character after a value is the
+// first non-whitespace byte after it.
+func skipFastBlocks(b []byte, i, maxDepth int) int {
+	var (
+		s = structIndex{b: b, off: i}
+		// Structural characters of block at base not visited yet.
+		m     uint64
+		base  int
+		state = skipValue
+		// Bit is set for object and unset for array, by depth.
+		stack [skipFastDepth / 64]uint64
+		depth uint
+		// Whether container at depth is object.
+		obj bool
+	)
+	for {
+		for m == 0 {
+			var ok bool
+			base = s.off
+			if m, ok = s.block(); !ok {
+				return -1
+			}
+		}
+		i := base + bits.TrailingZeros64(m)
+		m &= m - 1
+
+		c := b[i]
+		switch state {
+		case skipValueOrEnd:
+			if c == ']' {
+				goto end
+			}
+		case skipKey, skipKeyOrEnd:
+			if c == '}' && state == skipKeyOrEnd {
+				goto end
+			}
+			if c != '"' {
+				return -1
+			}
+			state = skipColon
+			continue
+		case skipColon:
+			if c != ':' {
+				return -1
+			}
+			state = skipValue
+			continue
+		case skipNext:
+			switch {
+			case c == ',' && obj:
+				state = skipKey
+				continue
+			case c == ',':
+				state = skipValue
+				continue
+			case c == '}' && obj, c == ']' && !obj:
+				goto end
+			default:
+				return -1
+			}
+		}
+
+		// Value is expected.
+		switch c {
+		case '"':
+		case '{', '[':
+			if depth == uint(maxDepth) {
+				return -1
+			}
+			obj = c == '{'
+			if obj {
+				stack[depth/64] |= 1 << (depth % 64)
+				state = skipKeyOrEnd
+			} else {
+				stack[depth/64] &^= 1 << (depth % 64)
+				state = skipValueOrEnd
+			}
+			depth++
+			continue
+		case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+			// Number is checked to end with delimiter.
+			if scan.Number(b[i:]) == 0 {
+				return -1
+			}
+		default:
+			n := skipFastScalar(b[i:])
+			if n == 0 {
+				return -1
+			}
+			// Literal continues up to the next structural character, so
+			// check that it ends here.
+			if j := i + n; j < len(b) && !skipFastDelim(b[j]) {
+				return -1
+			}
+		}
+		state = skipNext
+		continue
+
+	end:
+		// Closing bracket of container at depth.
+		if depth--; depth == 0 {
+			return i + 1
+		}
+		obj = stack[(depth-1)/64]&(1<<((depth-1)%64)) != 0
+		state = skipNext
+	}
+}
+
+// skipFastDelim reports whether c can follow scalar value.
+func skipFastDelim(c byte) bool {
+	switch c {
+	case ' ', '\t', '\n', '\r', ',', ']', '}', ':':
+		return true
+	default:
+		return false
+	}
+}
+
+// structIndex finds structural characters of json input: operators and
+// starts of strings and scalars outside of strings. They are found by
+// bitmask pass over 64-byte blocks, as in simdjson, which also validates
+// strings.
+type structIndex struct {
+	b   []byte
+	off int // offset of the next block
+
+	// State carried over from the previous block.
+	inStr   uint64 // all ones if block ended inside string
+	escaped uint64 // 1 if block ended with escape
+	scalar  uint64 // 1 if block ended inside scalar
+}
+
+// block returns mask of structural characters of the next block, or false
+// if there is no more input or string is invalid.
+func (s *structIndex) block() (uint64, bool) {
+	if s.off >= len(s.b) {
+		return 0, false
+	}
+	var m scan.Block
+	if len(s.b)-s.off >= 64 {
+		m.Classify(s.b[s.off:])
+	} else {
+		// Pad the last block with whitespace.
+		var block [64]byte
+		n := copy(block[:], s.b[s.off:])
+		for i := n; i < len(block); i++ {
+			block[i] = ' '
+		}
+		m.Classify(block[:])
+	}
+
+	escaped := s.escapes(m.Backslash)
+	quote := m.Quote &^ escaped
+	// Set from opening quote up to closing quote, excluding the latter.
+	inStr := prefixXor(quote) ^ s.inStr
+	s.inStr = uint64(int64(inStr) >> 63)
+	if m.Control&inStr != 0 {
+		return 0, false
+	}
+	if e := escaped & inStr; e != 0 && !s.validEscapes(e) {
+		return 0, false
+	}
+
+	scalar := ^(m.Op | m.Space | m.Quote | inStr)
+	scalarStart := scalar &^ (scalar<<1 | s.scalar)
+	s.scalar = scalar >> 63
+
+	s.off += 64
+	return (m.Op|scalarStart)&^inStr | quote&inStr, true
+}
+
+// escapes returns mask of escaped characters, given mask of backslashes.
+func (s *structIndex) escapes(backslash uint64) uint64 {
+	if backslash == 0 {
+		escaped := s.escaped
+		s.escaped = 0
+		return escaped
+	}
+	const even = 0x5555555555555555
+	// Escaped backslash does not escape.
+	backslash &^= s.escaped
+	followsEscape := backslash<<1 | s.escaped
+	// Sequences of backslashes starting at odd bit, and at even bit after
+	// carry is propagated through them.
+	oddStarts := backslash &^ even &^ followsEscape
+	evenStarts, carry := bits.Add64(oddStarts, backslash, 0)
+	s.escaped = carry
+	// Every other character after start of sequence is escaped.
+	return (even ^ evenStarts<<1) & followsEscape
+}
+
+// validEscapes reports whether escaped characters of the current block,
+// given by mask e, are valid escape sequences.
+func (s *structIndex) validEscapes(e uint64) bool {
+	b := s.b
+	for ; e != 0; e &= e - 1 {
+		i := s.off + bits.TrailingZeros64(e)
+		if i >= len(b) {
+			return false
+		}
+		switch escapedStrSet[b[i]] {
+		case 0:
+			return false
+		case 'u':
+			if len(b)-i < 5 {
+				return false
+			}
+			for _, h := range b[i+1 : i+5] {
+				if hexSet[h] == 0 {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// prefixXor returns mask where bit i is xor of bits 0 to i of x.
+func prefixXor(x uint64) uint64 {
+	x ^= x << 1
+	x ^= x << 2
+	x ^= x << 4
+	x ^= x << 8
+	x ^= x << 16
+	x ^= x << 32
+	return x
+}
+
+// skipFastSpace returns offset of the first non-whitespace byte in b starting
+// from i, or len(b).
+func skipFastSpace(b []byte, i int) int {
+	for i < len(b) && spaceSet[b[i]] == 1 {
+		i++
+	}
+	return i
+}
+
+// skipFastStr returns offset after closing quote of string, which starts at
+// i after opening quote, or -1 if string is invalid.
+func skipFastStr(b []byte, i int) int {
+	for {
+		i += scan.Str(b[i:])
+		if i == len(b) {
+			return -1
+		}
+		switch b[i] {
+		case '"':
+			return i + 1
+		case '\\':
+			if i+1 == len(b) {
+				return -1
+			}
+			switch escapedStrSet[b[i+1]] {
+			case 0:
+				return -1
+			case 'u':
+				if len(b)-i < 6 {
+					return -1
+				}
+				for _, h := range b[i+2 : i+6] {
+					if hexSet[h] == 0 {
+						return -1
+					}
+				}
+				i += 6
+			default:
+				i += 2
+			}
+		default:
+			// Control character.
+			return -1
+		}
+	}
+}
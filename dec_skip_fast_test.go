@@ -0,0 +1,175 @@
+package jx
+
+import (
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// requireSkipFast checks that skipFast, and both skipFastBytes and
+// skipFastBlocks for containers, accept the same values as Skip.
+func requireSkipFast(t *testing.T, input []byte) {
+	t.Helper()
+	d := DecodeBytes(input)
+	d.slow = true
+	err := d.Skip()
+
+	results := []int{skipFast(input, skipFastDepth)}
+	if i := skipFastSpace(input, 0); i < len(input) && (input[i] == '{' || input[i] == '[') {
+		results = append(results,
+			skipFastBytes(input, i, skipFastDepth),
+			skipFastBlocks(input, i, skipFastDepth),
+		)
+	}
+	for _, n := range results {
+		if err != nil {
+			require.Equal(t, -1, n, "%q: %v", input, err)
+			continue
+		}
+		if n < 0 {
+			// Value must be too deep for skipFast.
+			d := DecodeBytes(input)
+			d.slow = true
+			d.SetLimits(DecoderLimits{MaxDepth: skipFastDepth})
+			requireLimitErr(t, d.Skip(), LimitDepth)
+			continue
+		}
+		require.Equal(t, d.head, n, "%q", input)
+	}
+}
+
+func TestSkipFast(t *testing.T) {
+	for _, set := range [][]string{
+		testBools,
+		testNumbers,
+		testStrings,
+		testObjs,
+		testArrs,
+		{
+			``,
+			` `,
+			`"é"`,
+			`"\u00e"`,
+			`"\x"`,
+			"\"\x1f\"",
+			`"\`,
+			`truex`,
+			`nul`,
+			`[1, 2`,
+			`[1 2]`,
+			`[1,]`,
+			`{"a" 1}`,
+			`{"a": 1,}`,
+			`{"a": 1]`,
+			`[1}`,
+			`{1: 2}`,
+			`-`,
+			`1.`,
+			`1e+`,
+			`01`,
+			`1 `,
+			`1/`,
+			`[truex]`,
+			`[true"a"]`,
+			`[1"a"]`,
+			`["a"1]`,
+			`["a" "b"]`,
+			`["a\"]`,
+			`["a\\"]`,
+			`["\\\"", 1]`,
+			`[1\"]`,
+			`{"a":}`,
+			`{"a" "b": 1}`,
+			`[{]`,
+			`[[]}`,
+			`{"a": [1, {"b": null}], "c": "d"}`,
+			"[\"\x01\"]",
+			"[\"a\tb\"]",
+			`["\u00e"]`,
+			`["\u00e1"]`,
+			`["\`,
+			`["`,
+			`[-]`,
+		},
+	} {
+		for _, s := range set {
+			requireSkipFast(t, []byte(s))
+		}
+	}
+	t.Run("Suite", func(t *testing.T) {
+		dir := path.Join("testdata", "test_parsing")
+		files, err := testdata.ReadDir(dir)
+		require.NoError(t, err)
+		for _, f := range files {
+			data, err := testdata.ReadFile(path.Join(dir, f.Name()))
+			require.NoError(t, err)
+			requireSkipFast(t, data)
+		}
+	})
+	t.Run("Blocks", func(t *testing.T) {
+		// Values crossing 64-byte blocks at every offset.
+		for _, v := range []string{
+			`"abc"`,
+			`"a\\\"b"`,
+			`"\\"`,
+			`"\\\\"`,
+			`"\\\"`,
+			`"\u00e1"`,
+			`"\u00e"`,
+			"\"\x01\"",
+			`true`,
+			`tru`,
+			`-12.5e3`,
+			`{"a": [1, 2]}`,
+			`{"a" 1}`,
+		} {
+			for n := 0; n < 70; n++ {
+				requireSkipFast(t, []byte("["+strings.Repeat(" ", n)+v+"]"))
+				requireSkipFast(t, []byte("["+strings.Repeat(`"\\",`, n)+v+"]"))
+			}
+		}
+	})
+	t.Run("Testdata", func(t *testing.T) {
+		runTestdata(t.Fatal, func(name string, data []byte) {
+			requireSkipFast(t, data)
+		})
+	})
+}
+
+func TestDecoder_SkipFast(t *testing.T) {
+	t.Run("Error", func(t *testing.T) {
+		for _, input := range []string{
+			`{"foo": [1, {"bar": tru}]}`,
+			`[1, 2, "\x"]`,
+			`{"a": 1 "b": 2}`,
+		} {
+			slow := DecodeStr(input)
+			slow.slow = true
+			expected := slow.Skip()
+			require.Error(t, expected)
+
+			d := DecodeStr(input)
+			require.EqualError(t, d.Skip(), expected.Error(), input)
+			require.False(t, d.slow)
+		}
+	})
+	t.Run("Deep", func(t *testing.T) {
+		const depth = skipFastDepth + 10
+		input := strings.Repeat("[", depth) + strings.Repeat("]", depth)
+
+		d := DecodeStr(input)
+		require.NoError(t, d.Skip())
+		require.Equal(t, len(input), d.head)
+
+		d = DecodeStr(input)
+		d.SetLimits(DecoderLimits{MaxDepth: 10})
+		requireLimitErr(t, d.Skip(), LimitDepth)
+	})
+	t.Run("Depth", func(t *testing.T) {
+		d := DecodeStr(`[[1]]`)
+		d.depth = maxDepth - 1
+		requireLimitErr(t, d.Skip(), LimitDepth)
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"io"
 	"strings"
 	"testing"
+	"testing/iotest"
 
 	"github.com/stretchr/testify/require"
 )
@@ -81,3 +82,24 @@ func TestDecoder_SkipObjDepth(t *testing.T) {
 	}
 	require.Error(t, DecodeBytes(input).Skip())
 }
+
+func TestDecoder_SkipKeyPointer(t *testing.T) {
+	long := strings.Repeat("x", 100)
+	input := `{"a": 1, "k~/\\\"": [{"` + long + `": "` + long + `", "b": {"c": [` +
+		`"` + long + `", tru]}}]}`
+	for _, r := range []struct {
+		Name   string
+		Reader func() io.Reader
+	}{
+		{"OneByte", func() io.Reader { return iotest.OneByteReader(strings.NewReader(input)) }},
+		{"Half", func() io.Reader { return iotest.HalfReader(strings.NewReader(input)) }},
+	} {
+		t.Run(r.Name, func(t *testing.T) {
+			// Keys are overwritten in buffer while skipping values.
+			d := Decode(r.Reader(), 16)
+			var se *SyntaxError
+			require.ErrorAs(t, d.Skip(), &se)
+			require.Equal(t, `/k~0~1\"/0/b/c/1`, se.Pointer)
+		})
+	}
+}
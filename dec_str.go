@@ -6,6 +6,8 @@ import (
 	"unicode/utf8"
 
 	"github.com/go-faster/errors"
+
+	"github.com/go-faster/jx/internal/scan"
 )
 
 // StrAppend reads string and appends it to byte slice.
@@ -35,80 +37,31 @@ func (v value) rune(r rune) value {
 
 func (d *Decoder) str(v value) (value, error) {
 	if err := d.consume('"'); err != nil {
+		if d.ext&ExtSingleQuotes != 0 {
+			return d.strExt(v, err)
+		}
 		return value{}, err
 	}
 	var (
-		c byte
-		i int
+		start = d.offset()
+		buf   = d.buf[d.head:d.tail]
+		i     = scan.Str(buf)
 	)
-	for {
-		buf := d.buf[d.head:d.tail]
-		for len(buf) >= 8 {
-			c = buf[0]
-			if safeSet[c] != 0 {
-				goto readTok
-			}
-			i++
-
-			c = buf[1]
-			if safeSet[c] != 0 {
-				goto readTok
-			}
-			i++
-
-			c = buf[2]
-			if safeSet[c] != 0 {
-				goto readTok
-			}
-			i++
-
-			c = buf[3]
-			if safeSet[c] != 0 {
-				goto readTok
-			}
-			i++
-
-			c = buf[4]
-			if safeSet[c] != 0 {
-				goto readTok
-			}
-			i++
-
-			c = buf[5]
-			if safeSet[c] != 0 {
-				goto readTok
-			}
-			i++
-
-			c = buf[6]
-			if safeSet[c] != 0 {
-				goto readTok
-			}
-			i++
-
-			c = buf[7]
-			if safeSet[c] != 0 {
-				goto readTok
-			}
-			i++
-
-			buf = buf[8:]
-		}
-		var n int
-		for n, c = range buf {
-			if safeSet[c] != 0 {
-				i += n
-				goto readTok
-			}
-		}
-		return d.strSlow(v)
+	if i == len(buf) {
+		// String is not terminated in buffer.
+		return d.strSlow(v, start)
 	}
-readTok:
-	buf := d.buf[d.head:d.tail]
+	c := buf[i]
 	str := buf[:i]
 
 	switch {
 	case c == '"':
+		if err := d.checkStrLen(start, start+i); err != nil {
+			return value{}, err
+		}
+		if d.utf8 != UTF8PassThrough && !utf8.Valid(str) {
+			return d.strSlow(v, start)
+		}
 		// Skip string + last quote.
 		d.head += i + 1
 		if v.raw {
@@ -116,82 +69,39 @@ readTok:
 		}
 		return value{buf: append(v.buf, str...)}, nil
 	case c == '\\':
+		if d.utf8 != UTF8PassThrough {
+			return d.strSlow(v, start)
+		}
 		// Skip only string, keep quote in buffer.
 		d.head += i
 		// We need a copy anyway, because string is escaped.
-		return d.strSlow(value{buf: append(v.buf, str...)})
+		return d.strSlow(value{buf: append(v.buf, str...)}, start)
 	default:
-		return v, badToken(c, d.offset()+i)
+		return v, d.badToken(c, d.offset()+i)
 	}
 }
 
-func (d *Decoder) strSlow(v value) (value, error) {
+// strSlow reads rest of string started at start offset.
+func (d *Decoder) strSlow(v value, start int) (value, error) {
 	var (
 		c byte
 		i int
+		// Raw bytes of string not checked by UTF-8 policy are v.buf[run:],
+		// starting at runOffset in input.
+		run       = len(v.buf)
+		runOffset = d.offset()
 	)
 readStr:
 	for {
-		i = 0
 		buf := d.buf[d.head:d.tail]
-		for len(buf) >= 8 {
-			c = buf[0]
-			if safeSet[c] != 0 {
-				goto readTok
-			}
-			i++
-
-			c = buf[1]
-			if safeSet[c] != 0 {
-				goto readTok
-			}
-			i++
-
-			c = buf[2]
-			if safeSet[c] != 0 {
-				goto readTok
-			}
-			i++
-
-			c = buf[3]
-			if safeSet[c] != 0 {
-				goto readTok
-			}
-			i++
-
-			c = buf[4]
-			if safeSet[c] != 0 {
-				goto readTok
-			}
-			i++
-
-			c = buf[5]
-			if safeSet[c] != 0 {
-				goto readTok
-			}
-			i++
-
-			c = buf[6]
-			if safeSet[c] != 0 {
-				goto readTok
-			}
-			i++
-
-			c = buf[7]
-			if safeSet[c] != 0 {
-				goto readTok
-			}
-			i++
-
-			buf = buf[8:]
-		}
-		for _, c = range buf {
-			if safeSet[c] != 0 {
-				goto readTok
-			}
-			i++
+		if i = scan.Str(buf); i < len(buf) {
+			c = buf[i]
+			goto readTok
 		}
 
+		if err := d.checkStrLen(start, d.offset()+i); err != nil {
+			return value{}, err
+		}
 		v.buf = append(v.buf, d.buf[d.head:d.head+i]...)
 		if err := d.read(); err != nil {
 			if err == io.EOF {
@@ -201,26 +111,36 @@ readStr:
 		}
 	}
 readTok:
+	if err := d.checkStrLen(start, d.offset()+i); err != nil {
+		return value{}, err
+	}
 	buf := d.buf[d.head:d.tail]
 	str := buf[:i]
 	d.head += i + 1
+	if c != '"' && c != '\\' {
+		return v, d.badToken(c, d.offset()-1)
+	}
 
-	switch {
-	case c == '"':
-		return value{buf: append(v.buf, str...)}, nil
-	case c == '\\':
-		v.buf = append(v.buf, str...)
-		c, err := d.byte()
-		if err != nil {
+	v.buf = append(v.buf, str...)
+	if d.utf8 != UTF8PassThrough {
+		var err error
+		if v.buf, err = d.checkUTF8(v.buf, run, runOffset); err != nil {
 			return value{}, err
 		}
-		v, err = d.escapedChar(v, c)
-		if err != nil {
-			return v, errors.Wrap(err, "escape")
-		}
-	default:
-		return v, badToken(c, d.offset()-1)
 	}
+	if c == '"' {
+		return value{buf: v.buf}, nil
+	}
+
+	c, err := d.byte()
+	if err != nil {
+		return value{}, err
+	}
+	v, err = d.escapedChar(v, c)
+	if err != nil {
+		return v, errors.Wrap(err, "escape")
+	}
+	run, runOffset = len(v.buf), d.offset()
 	goto readStr
 }
 
@@ -249,41 +169,20 @@ func (d *Decoder) escapedChar(v value, c byte) (value, error) {
 	default:
 		v.buf = append(v.buf, val)
 	case 'u':
+		offset := d.offset() - 2
 		r1, err := d.readU4()
 		if err != nil {
 			return value{}, errors.Wrap(err, "read u4")
 		}
 		if utf16.IsSurrogate(r1) {
-			c, err := d.byte()
-			if err != nil {
-				return value{}, err
-			}
-			if c != '\\' {
-				d.unread()
-				return v.rune(r1), nil
-			}
-			c, err = d.byte()
-			if err != nil {
-				return value{}, err
-			}
-			if c != 'u' {
-				return d.escapedChar(v.rune(r1), c)
-			}
-			r2, err := d.readU4()
-			if err != nil {
-				return value{}, err
-			}
-			combined := utf16.DecodeRune(r1, r2)
-			if combined == '\uFFFD' {
-				v = v.rune(r1).rune(r2)
-			} else {
-				v = v.rune(combined)
-			}
-		} else {
-			v = v.rune(r1)
+			return d.surrogate(v, r1, offset)
 		}
+		v = v.rune(r1)
 	case 0:
-		err := badToken(c, d.offset()-1)
+		if d.ext != 0 {
+			return d.escapedCharExt(v, c)
+		}
+		err := d.badToken(c, d.offset()-1)
 		return v, errors.Wrap(err, "bad escape")
 	}
 	return v, nil
@@ -300,7 +199,7 @@ func (d *Decoder) readU4() (v rune, _ error) {
 	for i, c := range b {
 		val := hexSet[c]
 		if val == 0 {
-			return 0, badToken(c, offset+i)
+			return 0, d.badToken(c, offset+i)
 		}
 		v = v*16 + rune(val-1)
 	}
@@ -107,7 +107,7 @@ func testReadString(t *testing.T, input string, expectValue string, expectError
 func TestDecoder_strSlow(t *testing.T) {
 	r := errReader{}
 	d := Decode(r, 1)
-	_, err := d.strSlow(value{})
+	_, err := d.strSlow(value{}, 0)
 	require.ErrorIs(t, err, r.Err())
 }
 
@@ -0,0 +1,369 @@
+package jx
+
+import "github.com/go-faster/errors"
+
+// Token is kind of json token.
+type Token byte
+
+// Token kinds.
+const (
+	TokenInvalid Token = iota
+	// TokenObjStart is object start, "{".
+	TokenObjStart
+	// TokenObjEnd is object end, "}".
+	TokenObjEnd
+	// TokenArrStart is array start, "[".
+	TokenArrStart
+	// TokenArrEnd is array end, "]".
+	TokenArrEnd
+	// TokenKey is object key, like "foo" in {"foo": 1}.
+	TokenKey
+	// TokenString is string value.
+	TokenString
+	// TokenNumber is number value.
+	TokenNumber
+	// TokenTrue is true.
+	TokenTrue
+	// TokenFalse is false.
+	TokenFalse
+	// TokenNull is null.
+	TokenNull
+)
+
+func (t Token) String() string {
+	switch t {
+	case TokenObjStart:
+		return "ObjStart"
+	case TokenObjEnd:
+		return "ObjEnd"
+	case TokenArrStart:
+		return "ArrStart"
+	case TokenArrEnd:
+		return "ArrEnd"
+	case TokenKey:
+		return "Key"
+	case TokenString:
+		return "String"
+	case TokenNumber:
+		return "Number"
+	case TokenTrue:
+		return "True"
+	case TokenFalse:
+		return "False"
+	case TokenNull:
+		return "Null"
+	default:
+		return "Invalid"
+	}
+}
+
+// tokenWant is what tokenizer expects next.
+type tokenWant byte
+
+const (
+	wantValue tokenWant = iota // value, top-level or after "," in array
+	wantFirst                  // first key or value, or container end
+	wantComma                  // "," or container end
+	wantKey                    // key after "," in object
+	wantColon                  // ":" after key
+)
+
+// tokenFrame is single level of tokenizer nesting.
+type tokenFrame struct {
+	obj bool // object or array
+	n   int  // count of elements
+}
+
+// tokenState is tokenizer state of Decoder.
+//
+// Zero value is valid and expects top-level value.
+type tokenState struct {
+	stack []tokenFrame
+	want  tokenWant
+	saved []tokenFrame // stacks of marks, see Decoder.Mark
+}
+
+func (s *tokenState) reset() {
+	s.stack = s.stack[:0]
+	s.want = wantValue
+	s.saved = s.saved[:0]
+}
+
+// save saves stack, returning start of it in saved.
+func (s *tokenState) save() int {
+	start := len(s.saved)
+	s.saved = append(s.saved, s.stack...)
+	return start
+}
+
+// restore restores stack of n frames saved at start.
+func (s *tokenState) restore(start, n int, want tokenWant) {
+	s.stack = append(s.stack[:0], s.saved[start:start+n]...)
+	s.want = want
+	s.release(start, n)
+}
+
+// release releases saved stack, if it is the last one.
+func (s *tokenState) release(start, n int) {
+	if len(s.saved) == start+n {
+		s.saved = s.saved[:start]
+	}
+}
+
+var (
+	tokenObjStart = []byte("{")
+	tokenObjEnd   = []byte("}")
+	tokenArrStart = []byte("[")
+	tokenArrEnd   = []byte("]")
+	tokenTrue     = []byte("true")
+	tokenFalse    = []byte("false")
+	tokenNull     = []byte("null")
+)
+
+// Token reads next json token, returning its kind and raw json.
+//
+// Commas and colons are consumed automatically, so sequence of tokens
+// for {"foo": [1, true]} is ObjStart, Key, ArrStart, Number, True,
+// ArrEnd, ObjEnd. Raw of Key and String tokens is quoted string, as
+// written in input.
+//
+// Raw is valid only until next call to any Decoder method.
+//
+// Returns io.EOF if there are no tokens anymore. Multiple top-level
+// values are allowed.
+//
+// Token keeps its own state, so it must not be mixed with other reads,
+// except via TokenValue.
+func (d *Decoder) Token() (Token, []byte, error) {
+	c, err := d.tokenNext()
+	if err != nil {
+		return TokenInvalid, nil, err
+	}
+	s := &d.tok
+
+	if s.want == wantFirst || s.want == wantComma {
+		top := s.stack[len(s.stack)-1]
+		switch {
+		case c == '}' && top.obj:
+			return TokenObjEnd, tokenObjEnd, d.tokenEnd()
+		case c == ']' && !top.obj:
+			return TokenArrEnd, tokenArrEnd, d.tokenEnd()
+		case s.want == wantComma:
+			if c != ',' {
+				return TokenInvalid, nil, d.tokenErr(c)
+			}
+			if c, err = d.more(); err != nil {
+				return TokenInvalid, nil, err
+			}
+			if d.ext&ExtTrailingCommas != 0 {
+				switch {
+				case c == '}' && top.obj:
+					return TokenObjEnd, tokenObjEnd, d.tokenEnd()
+				case c == ']' && !top.obj:
+					return TokenArrEnd, tokenArrEnd, d.tokenEnd()
+				}
+			}
+		}
+		if top.obj {
+			s.want = wantKey
+		} else {
+			s.want = wantValue
+		}
+		if err := d.tokenElem(); err != nil {
+			return TokenInvalid, nil, err
+		}
+	}
+
+	if s.want == wantKey {
+		if c != '"' && !d.keyStartExt(c) {
+			return TokenInvalid, nil, d.tokenErr(c)
+		}
+		d.unread()
+		raw, err := d.raw(skipTokenKey)
+		if err != nil {
+			return TokenInvalid, nil, errors.Wrap(err, "field name")
+		}
+		// Colon is consumed by next call, because it can overwrite raw.
+		s.want = wantColon
+		return TokenKey, raw, nil
+	}
+
+	return d.tokenValue(c)
+}
+
+// TokenValue calls f to decode next value using any other Decoder method,
+// keeping Token state consistent.
+//
+// Can be called where Token would return value, e.g. after Key.
+func (d *Decoder) TokenValue(f func(d *Decoder) error) error {
+	c, err := d.tokenNext()
+	if err != nil {
+		return err
+	}
+	s := &d.tok
+	switch s.want {
+	case wantKey:
+		return d.tokenErr(c)
+	case wantFirst, wantComma:
+		top := s.stack[len(s.stack)-1]
+		if top.obj {
+			return d.tokenErr(c)
+		}
+		if s.want == wantComma {
+			if c != ',' {
+				return d.tokenErr(c)
+			}
+			if _, err := d.more(); err != nil {
+				return err
+			}
+		} else if c == ']' {
+			return d.tokenErr(c)
+		}
+		if err := d.tokenElem(); err != nil {
+			return err
+		}
+	}
+	d.unread()
+	if err := f(d); err != nil {
+		return errors.Wrap(err, "callback")
+	}
+	d.tokenAfterValue()
+	return nil
+}
+
+// tokenNext reads next non-whitespace byte, returning io.ErrUnexpectedEOF
+// if container is not closed.
+//
+// Consumes colon after key, if needed.
+func (d *Decoder) tokenNext() (byte, error) {
+	if len(d.tok.stack) == 0 {
+		return d.next()
+	}
+	c, err := d.more()
+	if err != nil || d.tok.want != wantColon {
+		return c, err
+	}
+	if c != ':' {
+		return 0, d.tokenErr(c)
+	}
+	d.tok.want = wantValue
+	return d.more()
+}
+
+// skipTokenKey skips object key.
+func skipTokenKey(d *Decoder) error {
+	_, err := d.skipKey()
+	return err
+}
+
+// tokenValue reads value starting with c.
+func (d *Decoder) tokenValue(c byte) (Token, []byte, error) {
+	var (
+		tok Token
+		raw []byte
+	)
+	switch c {
+	case '{', '[':
+		if err := d.incDepth(); err != nil {
+			return TokenInvalid, nil, err
+		}
+		obj := c == '{'
+		d.tok.stack = append(d.tok.stack, tokenFrame{obj: obj})
+		d.tok.want = wantFirst
+		if obj {
+			return TokenObjStart, tokenObjStart, nil
+		}
+		return TokenArrStart, tokenArrStart, nil
+	case '"', '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		d.unread()
+		r, err := d.Raw()
+		if err != nil {
+			return TokenInvalid, nil, err
+		}
+		tok, raw = TokenNumber, r
+		if c == '"' {
+			tok = TokenString
+		}
+	case 'n':
+		d.unread()
+		if err := d.Null(); err != nil {
+			return TokenInvalid, nil, err
+		}
+		tok, raw = TokenNull, tokenNull
+	case 't', 'f':
+		d.unread()
+		v, err := d.Bool()
+		if err != nil {
+			return TokenInvalid, nil, err
+		}
+		tok, raw = TokenFalse, tokenFalse
+		if v {
+			tok, raw = TokenTrue, tokenTrue
+		}
+	default:
+		switch d.typeExt(c) {
+		case String:
+			tok = TokenString
+		case Number:
+			tok = TokenNumber
+		default:
+			return TokenInvalid, nil, d.tokenErr(c)
+		}
+		d.unread()
+		r, err := d.Raw()
+		if err != nil {
+			return TokenInvalid, nil, err
+		}
+		raw = r
+	}
+	d.tokenAfterValue()
+	return tok, raw, nil
+}
+
+// tokenElem counts element of current container.
+func (d *Decoder) tokenElem() error {
+	top := &d.tok.stack[len(d.tok.stack)-1]
+	top.n++
+	return d.checkElements(top.n)
+}
+
+// tokenEnd closes current container.
+func (d *Decoder) tokenEnd() error {
+	d.tok.stack = d.tok.stack[:len(d.tok.stack)-1]
+	d.tokenAfterValue()
+	return d.decDepth()
+}
+
+// tokenAfterValue updates state after complete value.
+func (d *Decoder) tokenAfterValue() {
+	if len(d.tok.stack) == 0 {
+		d.tok.want = wantValue
+		return
+	}
+	d.tok.want = wantComma
+}
+
+// tokenErr returns error for unexpected c.
+func (d *Decoder) tokenErr(c byte) error {
+	err := d.badToken(c, d.offset()-1)
+	switch d.tok.want {
+	case wantKey:
+		return expected(err, `'"'`)
+	case wantColon:
+		return expected(err, `":"`)
+	case wantComma, wantFirst:
+		top := d.tok.stack[len(d.tok.stack)-1]
+		switch {
+		case top.obj && d.tok.want == wantComma:
+			return expected(err, `"," or "}"`)
+		case top.obj:
+			return expected(err, `'"' or "}"`)
+		case d.tok.want == wantComma:
+			return expected(err, `"," or "]"`)
+		default:
+			return expected(err, `value or "]"`)
+		}
+	default:
+		return expected(err, "value")
+	}
+}
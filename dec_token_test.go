@@ -0,0 +1,239 @@
+package jx
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// tokenCompact writes compact json from tokens of d.
+func tokenCompact(d *Decoder) ([]byte, error) {
+	var (
+		e     Encoder
+		depth int
+	)
+	for {
+		tok, raw, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch tok {
+		case TokenObjStart:
+			e.ObjStart()
+			depth++
+		case TokenArrStart:
+			e.ArrStart()
+			depth++
+		case TokenObjEnd:
+			e.ObjEnd()
+			depth--
+		case TokenArrEnd:
+			e.ArrEnd()
+			depth--
+		case TokenKey:
+			s, err := DecodeBytes(raw).Str()
+			if err != nil {
+				return nil, err
+			}
+			e.FieldStart(s)
+		default:
+			e.Raw(raw)
+		}
+		if depth == 0 && tok != TokenKey {
+			break
+		}
+	}
+	if _, _, err := d.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected trailing data: %v", err)
+	}
+	return e.Bytes(), nil
+}
+
+func TestToken_String(t *testing.T) {
+	met := map[string]bool{}
+	for i := TokenInvalid; i <= TokenNull+1; i++ {
+		s := i.String()
+		if i > TokenNull {
+			require.Equal(t, "Invalid", s)
+			continue
+		}
+		require.NotEmpty(t, s)
+		require.False(t, met[s], s)
+		met[s] = true
+	}
+}
+
+func TestDecoder_Token(t *testing.T) {
+	type token struct {
+		Tok Token
+		Raw string
+	}
+	const input = ` {"foo": [1, true, false, null, "\n", {}, []], "bar" : -1.5e1}  "s" 0`
+	expected := []token{
+		{TokenObjStart, `{`},
+		{TokenKey, `"foo"`},
+		{TokenArrStart, `[`},
+		{TokenNumber, `1`},
+		{TokenTrue, `true`},
+		{TokenFalse, `false`},
+		{TokenNull, `null`},
+		{TokenString, `"\n"`},
+		{TokenObjStart, `{`},
+		{TokenObjEnd, `}`},
+		{TokenArrStart, `[`},
+		{TokenArrEnd, `]`},
+		{TokenArrEnd, `]`},
+		{TokenKey, `"bar"`},
+		{TokenNumber, `-1.5e1`},
+		{TokenObjEnd, `}`},
+		{TokenString, `"s"`},
+		{TokenNumber, `0`},
+	}
+	testBufferReader(input, func(t *testing.T, d *Decoder) {
+		var got []token
+		for {
+			tok, raw, err := d.Token()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			got = append(got, token{tok, string(raw)})
+		}
+		require.Equal(t, expected, got)
+	})(t)
+}
+
+func TestDecoder_TokenValue(t *testing.T) {
+	const input = `{"skip": {"a": [1, 2]}, "arr": [1, {"b": 2}, "c"], "num": 10}`
+	testBufferReader(input, func(t *testing.T, d *Decoder) {
+		a := require.New(t)
+
+		tok, _, err := d.Token()
+		a.NoError(err)
+		a.Equal(TokenObjStart, tok)
+
+		// Skip value of "skip".
+		tok, raw, err := d.Token()
+		a.NoError(err)
+		a.Equal(TokenKey, tok)
+		a.Equal(`"skip"`, string(raw))
+		a.NoError(d.TokenValue((*Decoder).Skip))
+
+		// Read "arr", skipping every element.
+		tok, _, err = d.Token()
+		a.NoError(err)
+		a.Equal(TokenKey, tok)
+		tok, _, err = d.Token()
+		a.NoError(err)
+		a.Equal(TokenArrStart, tok)
+		var elems []string
+		for i := 0; i < 3; i++ {
+			a.NoError(d.TokenValue(func(d *Decoder) error {
+				raw, err := d.Raw()
+				elems = append(elems, raw.String())
+				return err
+			}))
+		}
+		a.Equal([]string{`1`, `{"b": 2}`, `"c"`}, elems)
+		// No more elements.
+		a.Error(d.TokenValue((*Decoder).Skip))
+	})(t)
+
+	t.Run("Invalid", func(t *testing.T) {
+		for _, input := range []string{
+			`{"a"`,
+			`{`,
+			`[1 2]`,
+			`{"a": 1 1}`,
+		} {
+			d := DecodeStr(input)
+			var err error
+			for err == nil {
+				err = d.TokenValue((*Decoder).Skip)
+				if err != nil {
+					break
+				}
+				_, _, err = d.Token()
+			}
+			require.Error(t, err, input)
+		}
+	})
+}
+
+func TestDecoder_TokenInvalid(t *testing.T) {
+	f := func(t *testing.T, d *Decoder) error {
+		_, err := tokenCompact(d)
+		return err
+	}
+	t.Run("Objects", func(t *testing.T) {
+		runTestCases(t, testObjs, f)
+	})
+	t.Run("Arrays", func(t *testing.T) {
+		runTestCases(t, testArrs, f)
+	})
+	t.Run("Cases", func(t *testing.T) {
+		runTestCases(t, []string{
+			``,
+			`[`,
+			`[1,]`,
+			`{"a":1,}`,
+			`{"a" 1}`,
+			`{1:1}`,
+			`{"a":1]`,
+			`[1}`,
+			`[1 2]`,
+			`{,}`,
+			`{"a":}`,
+			`tru`,
+			`"a`,
+			`01`,
+		}, f)
+	})
+}
+
+func TestDecoder_TokenTestdata(t *testing.T) {
+	runTestdata(t.Fatal, func(name string, data []byte) {
+		t.Run(name, func(t *testing.T) {
+			var expected bytes.Buffer
+			require.NoError(t, json.Compact(&expected, data))
+
+			testBufferReader(string(data), func(t *testing.T, d *Decoder) {
+				got, err := tokenCompact(d)
+				require.NoError(t, err)
+				require.Equal(t, expected.String(), string(got))
+			})(t)
+		})
+	})
+}
+
+func TestDecoder_TokenDepth(t *testing.T) {
+	d := DecodeStr(strings.Repeat(`[`, 10))
+	d.SetLimits(DecoderLimits{MaxDepth: 5})
+	var err error
+	for err == nil {
+		_, _, err = d.Token()
+	}
+	requireLimitErr(t, err, LimitDepth)
+}
+
+func BenchmarkDecoder_Token(b *testing.B) {
+	d := DecodeBytes(benchData)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(benchData)))
+	for i := 0; i < b.N; i++ {
+		d.ResetBytes(benchData)
+		for {
+			if _, _, err := d.Token(); err != nil {
+				if err == io.EOF {
+					break
+				}
+				b.Fatal(err)
+			}
+		}
+	}
+}
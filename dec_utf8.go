@@ -0,0 +1,199 @@
+package jx
+
+import (
+	"fmt"
+	"io"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/go-faster/errors"
+)
+
+// UTF8Policy configures handling of invalid UTF-8 and unpaired surrogate
+// escapes, like "\ud800", in strings.
+type UTF8Policy byte
+
+// UTF-8 policies.
+const (
+	// UTF8PassThrough copies invalid UTF-8 as is. Unpaired surrogates are
+	// replaced with U+FFFD. This is default.
+	UTF8PassThrough UTF8Policy = iota
+	// UTF8Reject returns error for invalid UTF-8 or unpaired surrogate.
+	UTF8Reject
+	// UTF8Replace replaces every byte of invalid UTF-8 and every unpaired
+	// surrogate with U+FFFD, like encoding/json.
+	UTF8Replace
+)
+
+func (p UTF8Policy) String() string {
+	switch p {
+	case UTF8PassThrough:
+		return "PassThrough"
+	case UTF8Reject:
+		return "Reject"
+	case UTF8Replace:
+		return "Replace"
+	default:
+		return fmt.Sprintf("UTF8Policy(%d)", int(p))
+	}
+}
+
+// SetUTF8Policy sets handling of invalid UTF-8 in strings.
+//
+// Policy is applied by Str, StrBytes, StrAppend and object keys. Skip and
+// Validate return error only for UTF8Reject. Raw returns input as is.
+//
+// Policy is kept on Reset and ResetBytes and cleared by PutDecoder.
+func (d *Decoder) SetUTF8Policy(p UTF8Policy) {
+	d.utf8 = p
+}
+
+// UTF8Policy returns current UTF-8 policy.
+func (d *Decoder) UTF8Policy() UTF8Policy {
+	return d.utf8
+}
+
+// invalidUTF8 returns index of first invalid UTF-8 byte in b, or -1.
+func invalidUTF8(b []byte) int {
+	if utf8.Valid(b) {
+		return -1
+	}
+	for i := 0; i < len(b); {
+		r, size := utf8.DecodeRune(b[i:])
+		if r == utf8.RuneError && size == 1 {
+			return i
+		}
+		i += size
+	}
+	return -1
+}
+
+// appendValidUTF8 appends b to p, replacing every invalid byte with U+FFFD.
+func appendValidUTF8(p, b []byte) []byte {
+	for len(b) > 0 {
+		r, size := utf8.DecodeRune(b)
+		if r == utf8.RuneError && size == 1 {
+			p = append(p, "\uFFFD"...)
+		} else {
+			p = append(p, b[:size]...)
+		}
+		b = b[size:]
+	}
+	return p
+}
+
+// utf8Err returns error for invalid UTF-8 byte c at offset.
+func (d *Decoder) utf8Err(c byte, offset int) error {
+	return errors.Wrap(d.badToken(c, offset), "invalid UTF-8")
+}
+
+// checkUTF8 applies UTF-8 policy to raw string bytes b[run:], which start
+// at offset in input.
+func (d *Decoder) checkUTF8(b []byte, run, offset int) ([]byte, error) {
+	i := invalidUTF8(b[run:])
+	if i < 0 {
+		return b, nil
+	}
+	if d.utf8 == UTF8Reject {
+		return nil, d.utf8Err(b[run+i], offset+i)
+	}
+	valid := appendValidUTF8(nil, b[run+i:])
+	return append(b[:run+i], valid...), nil
+}
+
+// strRune reads rest of UTF-8 sequence started by consumed byte c,
+// applying UTF-8 policy.
+func (d *Decoder) strRune(v value, c byte) (value, error) {
+	var (
+		offset = d.offset() - 1
+		b      = [utf8.UTFMax]byte{c}
+		n      = 1
+	)
+	for n < len(b) && !utf8.FullRune(b[:n]) {
+		c, err := d.peek()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return value{}, err
+		}
+		if utf8.RuneStart(c) {
+			break
+		}
+		b[n] = c
+		n++
+		d.head++
+	}
+	if r, size := utf8.DecodeRune(b[:n]); r != utf8.RuneError || size != 1 {
+		v.buf = append(v.buf, b[:n]...)
+		return v, nil
+	}
+	if d.utf8 == UTF8Reject {
+		return value{}, d.utf8Err(b[0], offset)
+	}
+	for i := 0; i < n; i++ {
+		v.buf = append(v.buf, "\uFFFD"...)
+	}
+	return v, nil
+}
+
+// surrogate reads second half of surrogate pair, which first half r1 is
+// escape at offset.
+func (d *Decoder) surrogate(v value, r1 rune, offset int) (value, error) {
+	c, err := d.byte()
+	if err != nil {
+		return value{}, err
+	}
+	if c != '\\' {
+		d.unread()
+		return d.loneSurrogate(v, r1, offset)
+	}
+	c, err = d.byte()
+	if err != nil {
+		return value{}, err
+	}
+	if c != 'u' {
+		if v, err = d.loneSurrogate(v, r1, offset); err != nil {
+			return value{}, err
+		}
+		return d.escapedChar(v, c)
+	}
+	offset2 := d.offset() - 2
+	r2, err := d.readU4()
+	if err != nil {
+		return value{}, err
+	}
+	if combined := utf16.DecodeRune(r1, r2); combined != utf8.RuneError {
+		return v.rune(combined), nil
+	}
+	if d.utf8 == UTF8PassThrough {
+		return v.rune(r1).rune(r2), nil
+	}
+	if v, err = d.loneSurrogate(v, r1, offset); err != nil {
+		return value{}, err
+	}
+	if utf16.IsSurrogate(r2) {
+		// Second escape can start another pair.
+		return d.surrogate(v, r2, offset2)
+	}
+	return v.rune(r2), nil
+}
+
+// loneSurrogate handles unpaired surrogate r, which escape is at offset.
+func (d *Decoder) loneSurrogate(v value, r rune, offset int) (value, error) {
+	if d.utf8 == UTF8Reject {
+		return value{}, errors.Wrapf(d.badToken('\\', offset), "unpaired surrogate %U", r)
+	}
+	// Surrogates are encoded as U+FFFD.
+	return v.rune(r), nil
+}
+
+// skipStrUTF8 is skipStr that validates UTF-8, see UTF8Reject.
+func (d *Decoder) skipStrUTF8() error {
+	v, err := d.strSlow(value{buf: d.strBuf[:0]}, d.offset())
+	if err != nil {
+		return err
+	}
+	d.strBuf = v.buf
+	return nil
+}
@@ -0,0 +1,179 @@
+package jx
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-faster/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUTF8Policy_String(t *testing.T) {
+	for p, s := range map[UTF8Policy]string{
+		UTF8PassThrough: "PassThrough",
+		UTF8Reject:      "Reject",
+		UTF8Replace:     "Replace",
+		10:              "UTF8Policy(10)",
+	} {
+		require.Equal(t, s, p.String())
+	}
+}
+
+func TestDecoder_SetUTF8Policy(t *testing.T) {
+	d := DecodeStr(`"foo"`)
+	require.Equal(t, UTF8PassThrough, d.UTF8Policy())
+	d.SetUTF8Policy(UTF8Reject)
+	require.Equal(t, UTF8Reject, d.UTF8Policy())
+
+	d.ResetBytes([]byte(`"\xff"`))
+	require.Equal(t, UTF8Reject, d.UTF8Policy())
+	_, err := d.Str()
+	require.Error(t, err)
+
+	PutDecoder(d)
+	require.Equal(t, UTF8PassThrough, d.UTF8Policy())
+}
+
+func TestDecoder_StrUTF8(t *testing.T) {
+	long := strings.Repeat("x", 600)
+	for i, tt := range []struct {
+		Input       string
+		PassThrough string
+		Replace     string
+		Offset      int // offset of error for UTF8Reject, -1 if valid
+	}{
+		{`"foo"`, "foo", "foo", -1},
+		{`"привет, 世界 😀"`, "привет, 世界 😀", "привет, 世界 😀", -1},
+		{`"\u043f\ud83d\ude00"`, "п😀", "п😀", -1},
+		{`"\ufffd"`, "\ufffd", "\ufffd", -1},
+		{"\"a\xffb\"", "a\xffb", "a\ufffdb", 2},
+		{"\"\xff\xfe\"", "\xff\xfe", "\ufffd\ufffd", 1},
+		{"\"\\u0041\xff\"", "A\xff", "A\ufffd", 7},
+		// Truncated sequence.
+		{"\"\xe4\xb8\"", "\xe4\xb8", "\ufffd\ufffd", 1},
+		{"\"\xe4\xb8\n\"", "", "", -2},
+		{"\"\xe4\xb8\\\"\xe4\"", "\xe4\xb8\"\xe4", "\ufffd\ufffd\"\ufffd", 1},
+		// Encoded surrogate.
+		{"\"\xed\xa0\x80\"", "\xed\xa0\x80", "\ufffd\ufffd\ufffd", 1},
+		// Unpaired surrogates.
+		{`"\ud800"`, "\ufffd", "\ufffd", 1},
+		{`"\udc00"`, "\ufffd", "\ufffd", 1},
+		{`"a\ud800b"`, "a\ufffdb", "a\ufffdb", 2},
+		{`"\ud800\n"`, "\ufffd\n", "\ufffd\n", 1},
+		{`"\ud800\u0041"`, "\ufffdA", "\ufffdA", 1},
+		{`"\ud800\ud800\udc00"`, "\ufffd\ufffd\ufffd", "\ufffd\U00010000", 1},
+		// Long string is split between reads.
+		{`"` + long + "\xe4\xb8\x96" + long + `"`, long + "世" + long, long + "世" + long, -1},
+		{`"` + long + "\xe4\xb8" + long + `"`, long + "\xe4\xb8" + long, long + "\ufffd\ufffd" + long, 601},
+	} {
+		tt := tt
+		t.Run(fmt.Sprintf("Test%d", i+1), func(t *testing.T) {
+			run := func(p UTF8Policy, name, input string, f func(d *Decoder) (string, error)) {
+				// Offset of string in input.
+				shift := strings.Index(input, tt.Input)
+				t.Run(p.String()+name, testBufferReader(input, func(t *testing.T, d *Decoder) {
+					d.SetUTF8Policy(p)
+					s, err := f(d)
+					if tt.Offset == -2 {
+						require.Error(t, err)
+						return
+					}
+					switch p {
+					case UTF8PassThrough:
+						require.NoError(t, err)
+						require.Equal(t, tt.PassThrough, s)
+					case UTF8Replace:
+						require.NoError(t, err)
+						require.Equal(t, tt.Replace, s)
+					case UTF8Reject:
+						if tt.Offset < 0 {
+							require.NoError(t, err)
+							require.Equal(t, tt.PassThrough, s)
+							return
+						}
+						se, ok := errors.Into[*SyntaxError](err)
+						require.True(t, ok, "%v", err)
+						require.Equal(t, tt.Offset+shift, se.Offset)
+					}
+				}))
+			}
+			for _, p := range []UTF8Policy{UTF8PassThrough, UTF8Reject, UTF8Replace} {
+				run(p, "Str", tt.Input, func(d *Decoder) (string, error) {
+					return d.Str()
+				})
+				run(p, "StrAppend", tt.Input, func(d *Decoder) (string, error) {
+					s, err := d.StrAppend([]byte("prefix"))
+					if err != nil {
+						return "", err
+					}
+					require.True(t, strings.HasPrefix(string(s), "prefix"))
+					return strings.TrimPrefix(string(s), "prefix"), nil
+				})
+				run(p, "Skip", tt.Input, func(d *Decoder) (string, error) {
+					if err := d.Skip(); err != nil {
+						return "", err
+					}
+					// Nothing to check, return expected value.
+					if d.UTF8Policy() == UTF8Replace {
+						return tt.Replace, nil
+					}
+					return tt.PassThrough, nil
+				})
+				run(p, "Key", "{"+tt.Input+": 1}", func(d *Decoder) (string, error) {
+					var key string
+					if err := d.ObjBytes(func(d *Decoder, k []byte) error {
+						key = string(k)
+						return d.Skip()
+					}); err != nil {
+						return "", err
+					}
+					return key, nil
+				})
+			}
+		})
+	}
+}
+
+func TestDecoder_ValidateUTF8(t *testing.T) {
+	const input = "{\"foo\": [\"bar\", \"\xff\"]}"
+	testBufferReader(input, func(t *testing.T, d *Decoder) {
+		d.SetUTF8Policy(UTF8Reject)
+		err := d.Validate()
+		se, ok := errors.Into[*SyntaxError](err)
+		require.True(t, ok, "%v", err)
+		require.Equal(t, SyntaxError{Token: 0xff, Offset: 17, Line: 1, Column: 18, Pointer: "/foo/1"}, *se)
+	})(t)
+	for _, p := range []UTF8Policy{UTF8PassThrough, UTF8Replace} {
+		t.Run(p.String(), testBufferReader(input, func(t *testing.T, d *Decoder) {
+			d.SetUTF8Policy(p)
+			require.NoError(t, d.Validate())
+		}))
+	}
+}
+
+func TestDecoder_UTF8Extensions(t *testing.T) {
+	const input = "{ключ\xff: 'значение\xff', '\xe4\xb8': 1}"
+	testBufferReader(input, func(t *testing.T, d *Decoder) {
+		d.SetExtensions(JSON5)
+		d.SetUTF8Policy(UTF8Replace)
+		var values []string
+		require.NoError(t, d.ObjBytes(func(d *Decoder, key []byte) error {
+			values = append(values, string(key))
+			if d.Next() == String {
+				v, err := d.Str()
+				values = append(values, v)
+				return err
+			}
+			return d.Skip()
+		}))
+		require.Equal(t, []string{"ключ�", "значение�", "��"}, values)
+	})(t)
+	t.Run("Reject", testBufferReader(input, func(t *testing.T, d *Decoder) {
+		d.SetExtensions(JSON5)
+		d.SetUTF8Policy(UTF8Reject)
+		se, ok := errors.Into[*SyntaxError](d.Skip())
+		require.True(t, ok)
+		require.Equal(t, len("{ключ"), se.Offset)
+	}))
+}
@@ -1,13 +1,18 @@
 package jx
 
-import "io"
+import (
+	"io"
+	"strings"
+)
 
 // Encoder encodes json to underlying buffer.
 //
 // Zero value is valid.
 type Encoder struct {
-	w      Writer // underlying writer
-	indent int    // count of spaces for single indentation level
+	w      Writer        // underlying writer
+	indent IndentOptions // formatting options, see SetIndent
+	inline inlineArr     // array that can be inlined, see IndentOptions
+	check  *encCheck     // nil if checks are disabled, see SetCheck
 
 	// first handles state for comma and indentation writing.
 	//
@@ -36,8 +41,14 @@ func (e *Encoder) WriteTo(w io.Writer) (n int64, err error) {
 }
 
 // SetIdent sets length of single indentation step.
+//
+// Use SetIndent for other formatting options.
 func (e *Encoder) SetIdent(n int) {
-	e.indent = n
+	var opts IndentOptions
+	if n > 0 {
+		opts.Indent = strings.Repeat(" ", n)
+	}
+	e.SetIndent(opts)
 }
 
 // String returns string of underlying buffer.
@@ -51,19 +62,30 @@ func (e Encoder) String() string {
 func (e *Encoder) Reset() {
 	e.w.Reset()
 	e.first = e.first[:0]
+	e.inline.level = 0
+	if e.check != nil {
+		e.check.reset()
+	}
 }
 
 // ResetWriter resets underlying buffer and sets output writer.
 func (e *Encoder) ResetWriter(out io.Writer) {
 	e.w.ResetWriter(out)
 	e.first = e.first[:0]
+	e.inline.level = 0
+	if e.check != nil {
+		e.check.reset()
+	}
 }
 
 // Bytes returns underlying buffer.
 func (e Encoder) Bytes() []byte { return e.w.Buf }
 
 // SetBytes sets underlying buffer.
-func (e *Encoder) SetBytes(buf []byte) { e.w.Buf = buf }
+func (e *Encoder) SetBytes(buf []byte) {
+	e.w.Buf = buf
+	e.inline.level = 0
+}
 
 // byte writes a single byte.
 func (e *Encoder) byte(c byte) bool {
@@ -98,19 +120,50 @@ func (e *Encoder) Bool(v bool) bool {
 //
 // Use Obj as convenience helper for writing objects.
 func (e *Encoder) ObjStart() (fail bool) {
-	fail = e.comma() || e.w.ObjStart()
+	if e.check != nil && e.checkValue() {
+		return true
+	}
+	fail = e.writeComma() || e.w.ObjStart()
+	// Array with object is not inlined.
+	e.inline.level = 0
 	e.begin()
+	e.checkBegin(true)
 	return fail || e.writeIndent()
 }
 
 // FieldStart encodes field name and writes colon.
 //
-// For non-zero indentation also writes single space after colon.
+// If indentation or spaced output is set, also writes single space after
+// colon.
 //
 // Use Field as convenience helper for encoding fields.
 func (e *Encoder) FieldStart(field string) (fail bool) {
-	fail = e.comma() || e.w.FieldStart(field)
-	if e.indent > 0 {
+	if e.check != nil && e.checkField() {
+		return true
+	}
+	return e.fieldEnd(e.comma() || e.w.Str(field))
+}
+
+// fieldStartBytes is FieldStart for byte slice.
+func (e *Encoder) fieldStartBytes(field []byte) (fail bool) {
+	if e.check != nil && e.checkField() {
+		return true
+	}
+	return e.fieldEnd(e.comma() || e.w.ByteStr(field))
+}
+
+// fieldStartRaw is FieldStart for raw quoted field name.
+func (e *Encoder) fieldStartRaw(field []byte) (fail bool) {
+	if e.check != nil && e.checkField() {
+		return true
+	}
+	return e.fieldEnd(e.comma() || e.w.Raw(field))
+}
+
+// fieldEnd writes colon after field name.
+func (e *Encoder) fieldEnd(fail bool) bool {
+	fail = fail || e.byte(':')
+	if e.indent.Indent != "" || e.indent.Spaced {
 		fail = fail || e.byte(' ')
 	}
 	if len(e.first) > 0 {
@@ -133,6 +186,9 @@ func (e *Encoder) Field(name string, f func(e *Encoder)) (fail bool) {
 //
 // Use Obj as convenience helper for writing objects.
 func (e *Encoder) ObjEnd() bool {
+	if e.check != nil && e.checkEnd(true) {
+		return true
+	}
 	e.end()
 	return e.writeIndent() || e.w.ObjEnd()
 }
@@ -161,8 +217,13 @@ func (e *Encoder) Obj(f func(e *Encoder)) (fail bool) {
 //
 // Use Arr as convenience helper for writing arrays.
 func (e *Encoder) ArrStart() (fail bool) {
-	fail = e.comma() || e.w.ArrStart()
+	if e.check != nil && e.checkValue() {
+		return true
+	}
+	fail = e.writeComma() || e.w.ArrStart()
 	e.begin()
+	e.checkBegin(false)
+	e.inlineStart()
 	return fail || e.writeIndent()
 }
 
@@ -177,6 +238,13 @@ func (e *Encoder) ArrEmpty() bool {
 //
 // Use Arr as convenience helper for writing arrays.
 func (e *Encoder) ArrEnd() bool {
+	if e.check != nil && e.checkEnd(false) {
+		return true
+	}
+	if e.inline.level != 0 && e.inlineEnd() {
+		e.end()
+		return e.w.ArrEnd()
+	}
 	e.end()
 	return e.writeIndent() ||
 		e.w.ArrEnd()
@@ -194,14 +262,3 @@ func (e *Encoder) Arr(f func(e *Encoder)) (fail bool) {
 	f(e)
 	return fail || e.ArrEnd()
 }
-
-func (e *Encoder) writeIndent() (fail bool) {
-	if e.indent == 0 {
-		return false
-	}
-	fail = e.byte('\n')
-	for i := 0; i < len(e.first)*e.indent && !fail; i++ {
-		fail = fail || e.byte(' ')
-	}
-	return fail
-}
@@ -0,0 +1,185 @@
+package jx
+
+import (
+	"fmt"
+
+	"github.com/go-faster/errors"
+)
+
+// EncoderCheck configures checks of Encoder usage, see Encoder.SetCheck.
+type EncoderCheck byte
+
+// Encoder check modes.
+const (
+	// EncoderCheckNone disables checks. This is default.
+	EncoderCheckNone EncoderCheck = iota
+	// EncoderCheckError makes misused method write nothing and report
+	// failure. The first error is returned by Err.
+	EncoderCheckError
+	// EncoderCheckPanic panics on misuse, useful for tests.
+	EncoderCheckPanic
+)
+
+func (c EncoderCheck) String() string {
+	switch c {
+	case EncoderCheckNone:
+		return "None"
+	case EncoderCheckError:
+		return "Error"
+	case EncoderCheckPanic:
+		return "Panic"
+	default:
+		return fmt.Sprintf("EncoderCheck(%d)", int(c))
+	}
+}
+
+// encCheck is state of checked Encoder.
+type encCheck struct {
+	mode EncoderCheck
+	// objs reports whether container is object, for every level of first.
+	objs  []bool
+	field bool // field name is being written
+	key   bool // field name is written, value is expected
+	done  bool // top-level value is written
+	err   error
+}
+
+func (c *encCheck) reset() {
+	c.objs = c.objs[:0]
+	c.field = false
+	c.key = false
+	c.done = false
+	c.err = nil
+}
+
+// incomplete returns error if value is not complete.
+func (c *encCheck) incomplete() error {
+	switch {
+	case c.key:
+		return errors.New("value of field expected")
+	case len(c.objs) > 0 && c.objs[len(c.objs)-1]:
+		return errors.New("object is not closed")
+	case len(c.objs) > 0:
+		return errors.New("array is not closed")
+	default:
+		return nil
+	}
+}
+
+// SetCheck sets checks of structural misuse, like FieldStart in array,
+// ArrEnd of object, value without field name or second top-level value.
+//
+// Should be called before writing. Write and SetBytes are not checked.
+// Mode is kept on Reset and ResetWriter and cleared by PutEncoder.
+func (e *Encoder) SetCheck(c EncoderCheck) {
+	if c == EncoderCheckNone {
+		e.check = nil
+		return
+	}
+	e.check = &encCheck{mode: c}
+}
+
+// Err returns the first misuse error of checked Encoder, or error if value
+// is incomplete, like object that is not closed.
+//
+// Returns nil if checks are disabled.
+func (e *Encoder) Err() error {
+	c := e.check
+	if c == nil {
+		return nil
+	}
+	if c.err != nil {
+		return c.err
+	}
+	return c.incomplete()
+}
+
+// writeErr returns misuse error or write error, for methods reporting
+// failure.
+func (e *Encoder) writeErr() error {
+	if e.check != nil && e.check.err != nil {
+		return e.check.err
+	}
+	return e.w.writeErr()
+}
+
+// misuse reports misuse error, returning true as failure.
+func (e *Encoder) misuse(err error) bool {
+	c := e.check
+	if c.mode == EncoderCheckPanic {
+		panic(err)
+	}
+	if c.err == nil {
+		c.err = err
+	}
+	return true
+}
+
+// checkValue is called before writing value or field name.
+func (e *Encoder) checkValue() (fail bool) {
+	c := e.check
+	switch {
+	case c.field:
+		// Checked by checkField.
+		c.field = false
+		c.key = true
+	case len(c.objs) == 0:
+		if c.done {
+			return e.misuse(errors.New("second top-level value"))
+		}
+		c.done = true
+	case c.objs[len(c.objs)-1]:
+		if !c.key {
+			return e.misuse(errors.New("value in object without field name"))
+		}
+		c.key = false
+	}
+	return false
+}
+
+// checkField is called before writing field name.
+func (e *Encoder) checkField() (fail bool) {
+	c := e.check
+	switch {
+	case len(c.objs) == 0 || !c.objs[len(c.objs)-1]:
+		return e.misuse(errors.New("FieldStart: not in object"))
+	case c.key:
+		return e.misuse(errors.New("FieldStart: value of previous field expected"))
+	}
+	c.field = true
+	return false
+}
+
+// checkBegin is called after begin.
+func (e *Encoder) checkBegin(obj bool) {
+	if e.check != nil {
+		e.check.objs = append(e.check.objs, obj)
+	}
+}
+
+// checkEnd is called before end of object or array.
+func (e *Encoder) checkEnd(obj bool) (fail bool) {
+	c := e.check
+	switch {
+	case obj && (len(c.objs) == 0 || !c.objs[len(c.objs)-1]):
+		return e.misuse(errors.New("ObjEnd: not in object"))
+	case !obj && (len(c.objs) == 0 || c.objs[len(c.objs)-1]):
+		return e.misuse(errors.New("ArrEnd: not in array"))
+	case c.key:
+		return e.misuse(errors.New("ObjEnd: value of field expected"))
+	}
+	c.objs = c.objs[:len(c.objs)-1]
+	return false
+}
+
+// checkSeq is called before start or end of json text sequence record.
+func (e *Encoder) checkSeq(start bool) (fail bool) {
+	c := e.check
+	if err := c.incomplete(); err != nil {
+		return e.misuse(errors.Wrap(err, "record"))
+	}
+	if start {
+		c.done = false
+	}
+	return false
+}
@@ -0,0 +1,273 @@
+package jx
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncoder_SetCheck(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		for _, tt := range []struct {
+			Name string
+			F    func(e *Encoder)
+		}{
+			{"SmallObject", encodeSmallObject},
+			{"Callback", encodeSmallCallback},
+			{"Indent", encodeIndentSample},
+			{"Scalar", func(e *Encoder) { e.Str("foo") }},
+			{"Empty", func(e *Encoder) {
+				e.Arr(func(e *Encoder) {
+					e.ObjEmpty()
+					e.ArrEmpty()
+					e.Obj(nil)
+				})
+			}},
+			{"RawIndented", func(e *Encoder) {
+				e.Obj(func(e *Encoder) {
+					e.Field("a", func(e *Encoder) {
+						if err := e.RawIndented([]byte(`{"b": [1, {"c": {}}]}`)); err != nil {
+							t.Fatal(err)
+						}
+					})
+				})
+			}},
+			{"Seq", func(e *Encoder) {
+				for i := 0; i < 3; i++ {
+					e.Seq(func(e *Encoder) {
+						e.Obj(func(e *Encoder) {
+							e.Field("i", func(e *Encoder) { e.Int(i) })
+						})
+					})
+				}
+			}},
+		} {
+			t.Run(tt.Name, func(t *testing.T) {
+				var expect Encoder
+				tt.F(&expect)
+
+				var e Encoder
+				e.SetCheck(EncoderCheckPanic)
+				tt.F(&e)
+				require.NoError(t, e.Err())
+				require.NoError(t, e.Close())
+				require.Equal(t, expect.String(), e.String())
+			})
+		}
+	})
+	t.Run("Testdata", func(t *testing.T) {
+		runTestdata(t.Fatal, func(name string, data []byte) {
+			t.Run(name, func(t *testing.T) {
+				var e Encoder
+				e.SetCheck(EncoderCheckPanic)
+				require.NoError(t, Indent(DecodeBytes(data), &e))
+				require.NoError(t, e.Err())
+			})
+		})
+	})
+
+	misuse := []struct {
+		Name   string
+		F      func(e *Encoder) bool
+		Expect string
+		Err    string
+	}{
+		{
+			Name:   "FieldInArray",
+			F:      func(e *Encoder) bool { e.ArrStart(); return e.FieldStart("a") },
+			Expect: "[",
+			Err:    "FieldStart: not in object",
+		},
+		{
+			Name: "FieldTopLevel",
+			F:    func(e *Encoder) bool { return e.FieldStart("a") },
+			Err:  "FieldStart: not in object",
+		},
+		{
+			Name:   "FieldWithoutValue",
+			F:      func(e *Encoder) bool { e.ObjStart(); e.FieldStart("a"); return e.FieldStart("b") },
+			Expect: `{"a":`,
+			Err:    "FieldStart: value of previous field expected",
+		},
+		{
+			Name:   "ValueWithoutField",
+			F:      func(e *Encoder) bool { e.ObjStart(); return e.Int(1) },
+			Expect: "{",
+			Err:    "value in object without field name",
+		},
+		{
+			Name:   "ObjInObjectWithoutField",
+			F:      func(e *Encoder) bool { e.ObjStart(); return e.ObjStart() },
+			Expect: "{",
+			Err:    "value in object without field name",
+		},
+		{
+			Name:   "ArrEndOfObject",
+			F:      func(e *Encoder) bool { e.ObjStart(); return e.ArrEnd() },
+			Expect: "{",
+			Err:    "ArrEnd: not in array",
+		},
+		{
+			Name:   "ObjEndOfArray",
+			F:      func(e *Encoder) bool { e.ArrStart(); return e.ObjEnd() },
+			Expect: "[",
+			Err:    "ObjEnd: not in object",
+		},
+		{
+			Name:   "ObjEndWithoutValue",
+			F:      func(e *Encoder) bool { e.ObjStart(); e.FieldStart("a"); return e.ObjEnd() },
+			Expect: `{"a":`,
+			Err:    "ObjEnd: value of field expected",
+		},
+		{
+			Name: "EndTopLevel",
+			F:    func(e *Encoder) bool { return e.ArrEnd() },
+			Err:  "ArrEnd: not in array",
+		},
+		{
+			Name:   "SecondValue",
+			F:      func(e *Encoder) bool { e.ArrEmpty(); return e.Null() },
+			Expect: "[]",
+			Err:    "second top-level value",
+		},
+		{
+			Name:   "SecondObject",
+			F:      func(e *Encoder) bool { e.ObjStart(); e.ObjEnd(); return e.ObjStart() },
+			Expect: "{}",
+			Err:    "second top-level value",
+		},
+		{
+			Name:   "ArrInObjectWithoutField",
+			F:      func(e *Encoder) bool { e.ObjStart(); return e.ArrStart() },
+			Expect: "{",
+			Err:    "value in object without field name",
+		},
+		{
+			Name:   "SeqIncomplete",
+			F:      func(e *Encoder) bool { e.SeqStart(); e.ArrStart(); return e.SeqEnd() },
+			Expect: "\x1e[",
+			Err:    "record: array is not closed",
+		},
+	}
+	t.Run("Error", func(t *testing.T) {
+		for _, tt := range misuse {
+			t.Run(tt.Name, func(t *testing.T) {
+				var e Encoder
+				e.SetCheck(EncoderCheckError)
+				require.True(t, tt.F(&e))
+				// Nothing is written by misused method.
+				require.Equal(t, tt.Expect, e.String())
+				require.EqualError(t, e.Err(), tt.Err)
+
+				// The first error is kept.
+				e.FieldStart("x")
+				e.ArrEnd()
+				require.EqualError(t, e.Err(), tt.Err)
+				require.EqualError(t, e.Close(), tt.Err)
+			})
+		}
+	})
+	t.Run("RejectedStart", func(t *testing.T) {
+		// Rejected container start does not add level, so following end is
+		// rejected too.
+		var e Encoder
+		e.SetCheck(EncoderCheckError)
+		e.ObjStart()
+		e.ObjEnd()
+		require.True(t, e.ObjStart())
+		require.True(t, e.ObjEnd())
+		require.True(t, e.ArrStart())
+		require.True(t, e.ArrEnd())
+		require.Equal(t, "{}", e.String())
+		require.EqualError(t, e.Err(), "second top-level value")
+
+		e.Reset()
+		e.ObjStart()
+		require.True(t, e.ArrStart())
+		e.Int(1)
+		require.True(t, e.ArrEnd())
+		require.False(t, e.ObjEnd())
+		require.Equal(t, "{}", e.String())
+	})
+	t.Run("ErrorReturned", func(t *testing.T) {
+		var e Encoder
+		e.SetCheck(EncoderCheckError)
+		e.Null()
+		require.EqualError(t, Indent(DecodeStr(`{"a":1}`), &e), "second top-level value")
+
+		p, err := CompileProjection(ProjectionRules{})
+		require.NoError(t, err)
+		require.EqualError(t, DecodeStr(`[1]`).Project(p, &e), "second top-level value")
+	})
+	t.Run("Panic", func(t *testing.T) {
+		for _, tt := range misuse {
+			t.Run(tt.Name, func(t *testing.T) {
+				var e Encoder
+				e.SetCheck(EncoderCheckPanic)
+				require.PanicsWithError(t, tt.Err, func() { tt.F(&e) })
+			})
+		}
+	})
+	t.Run("Incomplete", func(t *testing.T) {
+		for _, tt := range []struct {
+			Name string
+			F    func(e *Encoder)
+			Err  string
+		}{
+			{"Object", func(e *Encoder) { e.ObjStart() }, "object is not closed"},
+			{"Array", func(e *Encoder) { e.ObjStart(); e.FieldStart("a"); e.ArrStart() }, "array is not closed"},
+			{"Field", func(e *Encoder) { e.ObjStart(); e.FieldStart("a") }, "value of field expected"},
+		} {
+			t.Run(tt.Name, func(t *testing.T) {
+				var out bytes.Buffer
+				e := NewStreamingEncoder(&out, -1)
+				e.SetCheck(EncoderCheckPanic)
+				tt.F(e)
+				require.EqualError(t, e.Err(), tt.Err)
+				require.EqualError(t, e.Close(), tt.Err)
+				// Buffer is flushed anyway.
+				require.NotEmpty(t, out.String())
+			})
+		}
+	})
+	t.Run("Reset", func(t *testing.T) {
+		e := GetEncoder()
+		e.SetCheck(EncoderCheckError)
+		e.ObjStart()
+		e.Int(1)
+		require.Error(t, e.Err())
+
+		// Mode is kept.
+		e.Reset()
+		require.NoError(t, e.Err())
+		e.Int(1)
+		e.Int(2)
+		require.Error(t, e.Err())
+
+		var sb strings.Builder
+		e.ResetWriter(&sb)
+		require.NoError(t, e.Err())
+		e.ArrEnd()
+		require.Error(t, e.Err())
+
+		// Mode is cleared.
+		PutEncoder(e)
+		e = GetEncoder()
+		e.Int(1)
+		e.Int(2)
+		require.NoError(t, e.Err())
+		PutEncoder(e)
+	})
+	t.Run("String", func(t *testing.T) {
+		for c, s := range map[EncoderCheck]string{
+			EncoderCheckNone:  "None",
+			EncoderCheckError: "Error",
+			EncoderCheckPanic: "Panic",
+			EncoderCheck(10):  "EncoderCheck(10)",
+		} {
+			require.Equal(t, s, c.String())
+		}
+	})
+}
@@ -17,6 +17,14 @@ func (e *Encoder) current() int { return len(e.first) - 1 }
 
 // comma should be called before any new value.
 func (e *Encoder) comma() bool {
+	if e.check != nil && e.checkValue() {
+		return true
+	}
+	return e.writeComma()
+}
+
+// writeComma writes comma if needed, without checks.
+func (e *Encoder) writeComma() bool {
 	// Writing commas.
 	// 1. Before every field expect first.
 	// 2. Before every array element except first.
@@ -27,8 +35,14 @@ func (e *Encoder) comma() bool {
 	_ = e.first[current]
 	if e.first[current] {
 		e.first[current] = false
+		e.inlineElem(false)
 		return false
 	}
-	return e.byte(',') ||
-		e.writeIndent()
+	e.inlineElem(true)
+	if e.indent.Spaced {
+		return e.w.twoBytes(',', ' ')
+	}
+	fail := e.byte(',') || e.writeIndent()
+	e.inlineElem(false)
+	return fail
 }
@@ -0,0 +1,131 @@
+package jx
+
+import "bytes"
+
+// IndentOptions are formatting options of Encoder, see Encoder.SetIndent.
+//
+// Zero value is compact output.
+type IndentOptions struct {
+	// Indent is single indentation level, like "  " or "\t".
+	//
+	// If empty, output has no newlines.
+	Indent string
+	// Prefix is written at beginning of every line except the first one.
+	Prefix string
+	// Newline separates lines, "\n" if empty.
+	Newline string
+	// InlineArrays is maximum length of array of scalars that is written on
+	// single line, like [1, 2, 3]. Zero disables inlining.
+	//
+	// For streaming encoder, array is not inlined if buffer is flushed while
+	// writing it.
+	InlineArrays int
+	// Spaced writes single space after colon and comma if Indent is empty,
+	// like {"a": 1, "b": [1, 2]}.
+	Spaced bool
+}
+
+// SetIndent sets formatting options.
+func (e *Encoder) SetIndent(opts IndentOptions) {
+	if opts.Indent == "" {
+		opts.Prefix = ""
+		opts.InlineArrays = 0
+	} else {
+		opts.Spaced = false
+	}
+	if opts.Newline == "" {
+		opts.Newline = "\n"
+	}
+	e.indent = opts
+	e.inline.level = 0
+}
+
+// writeIndent writes newline, prefix and indentation of current level.
+func (e *Encoder) writeIndent() (fail bool) {
+	if e.indent.Indent == "" {
+		return false
+	}
+	fail = e.w.rawStr(e.indent.Newline) || e.w.rawStr(e.indent.Prefix)
+	for i := 0; i < len(e.first) && !fail; i++ {
+		fail = e.w.rawStr(e.indent.Indent)
+	}
+	return fail
+}
+
+// inlineArr is array that is written on single line if it fits, see
+// IndentOptions.InlineArrays.
+type inlineArr struct {
+	level   int   // len(first) of array, zero if there is no such array
+	start   int   // offset of '[' in buffer
+	flushes int   // count of flushes at start
+	elems   []int // start and end offsets of elements
+}
+
+// inlineStart is called after writing '[' of array.
+func (e *Encoder) inlineStart() {
+	if e.indent.InlineArrays <= 0 {
+		return
+	}
+	// Only innermost array is inlined, so it replaces any parent one.
+	e.inline.level = len(e.first)
+	e.inline.start = len(e.w.Buf) - 1
+	e.inline.flushes = e.w.flushes()
+	e.inline.elems = e.inline.elems[:0]
+}
+
+// inlineElem records start or end of array element.
+func (e *Encoder) inlineElem(end bool) {
+	if e.inline.level == 0 || e.inline.level != len(e.first) {
+		return
+	}
+	if end == (len(e.inline.elems)%2 == 0) {
+		// Unbalanced, e.g. after write error.
+		e.inline.level = 0
+		return
+	}
+	e.inline.elems = append(e.inline.elems, len(e.w.Buf))
+}
+
+// inlineEnd rewrites current array on single line, if possible, reporting
+// whether it was rewritten.
+//
+// Closing bracket is not written.
+func (e *Encoder) inlineEnd() bool {
+	a := &e.inline
+	if a.level == 0 || a.level != len(e.first) {
+		return false
+	}
+	a.level = 0
+	if e.w.flushes() != a.flushes || a.start >= len(e.w.Buf) {
+		// Buffer was flushed or write failed.
+		return false
+	}
+	if len(a.elems)%2 == 1 {
+		a.elems = append(a.elems, len(e.w.Buf))
+	}
+	buf := e.w.Buf
+	n := len("[]")
+	for i := 0; i < len(a.elems); i += 2 {
+		elem := buf[a.elems[i]:a.elems[i+1]]
+		if bytes.Contains(elem, []byte(e.indent.Newline)) {
+			return false
+		}
+		if i > 0 {
+			n += len(", ")
+		}
+		if n += len(elem); n > e.indent.InlineArrays {
+			return false
+		}
+	}
+	// Separators and indentation are longer than ", ", so elements are only
+	// moved towards start.
+	off := a.start + 1
+	for i := 0; i < len(a.elems); i += 2 {
+		if i > 0 {
+			off += copy(buf[off:], ", ")
+		}
+		off += copy(buf[off:], buf[a.elems[i]:a.elems[i+1]])
+	}
+	e.w.Buf = buf[:off]
+	return true
+}
@@ -0,0 +1,138 @@
+package jx
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func encodeIndentSample(e *Encoder) {
+	e.Obj(func(e *Encoder) {
+		e.Field("id", func(e *Encoder) { e.Int(1) })
+		e.Field("tags", func(e *Encoder) {
+			e.Arr(func(e *Encoder) {
+				e.Str("a")
+				e.Str("b")
+			})
+		})
+		e.Field("matrix", func(e *Encoder) {
+			e.Arr(func(e *Encoder) {
+				e.Arr(func(e *Encoder) {
+					e.Int(1)
+					e.Int(2)
+				})
+				e.ArrEmpty()
+			})
+		})
+		e.Field("objects", func(e *Encoder) {
+			e.Arr(func(e *Encoder) {
+				e.Obj(func(e *Encoder) {
+					e.Field("x", func(e *Encoder) { e.Null() })
+				})
+			})
+		})
+		e.Field("long", func(e *Encoder) {
+			e.Arr(func(e *Encoder) {
+				e.Str("foo bar baz")
+				e.Str("foo bar baz")
+			})
+		})
+		e.Field("empty", func(e *Encoder) { e.ObjEmpty() })
+	})
+}
+
+func TestEncoder_SetIndent(t *testing.T) {
+	t.Run("Tab", func(t *testing.T) {
+		opts := IndentOptions{Indent: "\t", Prefix: "> "}
+		e := GetEncoder()
+		encodeIndentSample(e)
+		var expect bytes.Buffer
+		require.NoError(t, json.Indent(&expect, e.Bytes(), opts.Prefix, opts.Indent))
+
+		testEncoderModes(t, func(e *Encoder) {
+			e.SetIndent(opts)
+			encodeIndentSample(e)
+		}, expect.String())
+	})
+	t.Run("Newline", func(t *testing.T) {
+		testEncoderModes(t, func(e *Encoder) {
+			e.SetIndent(IndentOptions{Indent: " ", Newline: "\r\n"})
+			e.Obj(func(e *Encoder) {
+				e.Field("a", func(e *Encoder) {
+					e.Arr(func(e *Encoder) { e.Int(1) })
+				})
+			})
+		}, "{\r\n \"a\": [\r\n  1\r\n ]\r\n}")
+	})
+	t.Run("InlineArrays", func(t *testing.T) {
+		testEncoderModes(t, func(e *Encoder) {
+			e.SetIndent(IndentOptions{Indent: "  ", InlineArrays: 20})
+			encodeIndentSample(e)
+		}, `{
+  "id": 1,
+  "tags": ["a", "b"],
+  "matrix": [
+    [1, 2],
+    []
+  ],
+  "objects": [
+    {
+      "x": null
+    }
+  ],
+  "long": [
+    "foo bar baz",
+    "foo bar baz"
+  ],
+  "empty": {}
+}`)
+	})
+	t.Run("InlineRoot", func(t *testing.T) {
+		testEncoderModes(t, func(e *Encoder) {
+			e.SetIndent(IndentOptions{Indent: "\t", Prefix: "//", InlineArrays: 80})
+			e.Arr(func(e *Encoder) {
+				e.Int(1)
+				e.Raw([]byte(`"raw"`))
+				e.Bool(true)
+			})
+		}, `[1, "raw", true]`)
+	})
+	t.Run("InlineFlushed", func(t *testing.T) {
+		var out strings.Builder
+		e := NewStreamingEncoder(&out, minEncoderBufSize)
+		e.SetIndent(IndentOptions{Indent: " ", InlineArrays: 1024})
+		e.Arr(func(e *Encoder) {
+			for i := 0; i < 20; i++ {
+				e.Int(i)
+			}
+		})
+		require.NoError(t, e.Close())
+
+		var expect bytes.Buffer
+		require.NoError(t, json.Indent(&expect, []byte(`[0,1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16,17,18,19]`), "", " "))
+		require.Equal(t, expect.String(), out.String())
+	})
+	t.Run("Spaced", func(t *testing.T) {
+		testEncoderModes(t, func(e *Encoder) {
+			e.SetIndent(IndentOptions{Spaced: true, Prefix: "ignored"})
+			encodeIndentSample(e)
+		}, `{"id": 1, "tags": ["a", "b"], "matrix": [[1, 2], []], "objects": [{"x": null}], "long": ["foo bar baz", "foo bar baz"], "empty": {}}`)
+	})
+	t.Run("Reset", func(t *testing.T) {
+		e := GetEncoder()
+		e.SetIndent(IndentOptions{Indent: "\t", InlineArrays: 10})
+		e.ArrStart()
+		e.Int(1)
+		e.Reset()
+		e.Arr(func(e *Encoder) { e.Int(2) })
+		require.Equal(t, "[2]", e.String())
+
+		e.SetIdent(0)
+		e.Reset()
+		encodeIndentSample(e)
+		require.NotContains(t, e.String(), "\n")
+	})
+}
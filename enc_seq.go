@@ -0,0 +1,29 @@
+package jx
+
+// SeqStart writes record separator (RS) of RFC 7464 json text sequence.
+//
+// Use Seq as convenience helper for writing records.
+func (e *Encoder) SeqStart() bool {
+	if e.check != nil && e.checkSeq(true) {
+		return true
+	}
+	return e.w.SeqStart()
+}
+
+// SeqEnd writes line feed that ends record of json text sequence.
+//
+// In streaming mode, also flushes buffer to writer.
+func (e *Encoder) SeqEnd() bool {
+	if e.check != nil && e.checkSeq(false) {
+		return true
+	}
+	return e.w.SeqEnd()
+}
+
+// Seq writes record of json text sequence, invoking callback to write
+// value.
+func (e *Encoder) Seq(f func(e *Encoder)) (fail bool) {
+	fail = e.SeqStart()
+	f(e)
+	return fail || e.SeqEnd()
+}
@@ -0,0 +1,71 @@
+package jx
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/go-faster/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriter_Seq(t *testing.T) {
+	var w Writer
+	w.SeqStart()
+	w.Null()
+	w.SeqEnd()
+	require.Equal(t, "\x1enull\n", w.String())
+}
+
+func TestEncoder_Seq(t *testing.T) {
+	t.Run("Buffer", func(t *testing.T) {
+		var e Encoder
+		e.Seq(func(e *Encoder) {
+			e.ArrStart()
+			e.Int(1)
+			e.Int(2)
+			e.ArrEnd()
+		})
+		e.Seq(func(e *Encoder) {
+			e.Str("foo")
+		})
+		require.Equal(t, "\x1e[1,2]\n\x1e\"foo\"\n", e.String())
+	})
+	t.Run("Streaming", func(t *testing.T) {
+		var (
+			out bytes.Buffer
+			e   = NewStreamingEncoder(&out, -1)
+		)
+		require.False(t, e.Seq(func(e *Encoder) {
+			e.Obj(func(e *Encoder) {
+				e.Field("a", func(e *Encoder) {
+					e.Int(1)
+				})
+			})
+		}))
+		// Record is flushed.
+		require.Equal(t, "\x1e{\"a\":1}\n", out.String())
+
+		require.False(t, e.SeqStart())
+		require.False(t, e.Null())
+		require.Equal(t, "\x1e{\"a\":1}\n", out.String())
+		require.False(t, e.SeqEnd())
+		require.Equal(t, "\x1e{\"a\":1}\n\x1enull\n", out.String())
+		require.NoError(t, e.Close())
+
+		var values []string
+		require.NoError(t, DecodeBytes(out.Bytes()).Seq(func(d *Decoder, r Record) error {
+			raw, err := d.Raw()
+			values = append(values, raw.String())
+			return err
+		}, nil))
+		require.Equal(t, []string{`{"a":1}`, `null`}, values)
+	})
+	t.Run("Error", func(t *testing.T) {
+		errTest := errors.New("test")
+		e := NewStreamingEncoder(&errWriter{err: errTest}, -1)
+		require.True(t, e.Seq(func(e *Encoder) {
+			e.Null()
+		}))
+		require.ErrorIs(t, e.Close(), errTest)
+	})
+}
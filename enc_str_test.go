@@ -132,3 +132,58 @@ func TestEncoder_StrEscape(t *testing.T) {
 		}, v)
 	})
 }
+
+func TestEncoder_StrLong(t *testing.T) {
+	for _, n := range []int{31, 32, 33, 64, 100} {
+		for _, special := range []string{"\"", "\\", "\n", "\x01", "<", ">", "&", "é", " "} {
+			for _, pos := range []int{0, n / 2, n - 1} {
+				input := strings.Repeat("a", pos) + special + strings.Repeat("b", n-pos)
+				t.Run("Str", func(t *testing.T) {
+					e := GetEncoder()
+					e.Str(input)
+					got, err := DecodeBytes(e.Bytes()).Str()
+					require.NoError(t, err)
+					require.Equal(t, input, got)
+				})
+				t.Run("StrEscape", func(t *testing.T) {
+					requireCompat(t, func(e *Encoder) {
+						e.StrEscape(input)
+					}, input)
+				})
+			}
+		}
+	}
+}
+
+func BenchmarkEncoder_Str(b *testing.B) {
+	for _, tt := range []struct {
+		name  string
+		input string
+	}{
+		{"Short", "hello"},
+		{"Long", strings.Repeat("Lorem ipsum dolor sit amet. ", 40)},
+		{"LongEscaped", strings.Repeat("Lorem ipsum \"dolor\" sit <amet>.\n", 40)},
+	} {
+		input := tt.input
+		b.Run(tt.name, func(b *testing.B) {
+			for _, enc := range []struct {
+				name string
+				enc  func(e *Encoder, v string) bool
+			}{
+				{"Str", (*Encoder).Str},
+				{"StrEscape", (*Encoder).StrEscape},
+			} {
+				enc := enc
+				b.Run(enc.name, func(b *testing.B) {
+					e := GetEncoder()
+					b.SetBytes(int64(len(input)))
+					b.ReportAllocs()
+					for i := 0; i < b.N; i++ {
+						e.Reset()
+						enc.enc(e, input)
+					}
+				})
+			}
+		})
+	}
+}
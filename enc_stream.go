@@ -25,6 +25,11 @@ func NewStreamingEncoder(w io.Writer, bufSize int) *Encoder {
 
 // Close flushes underlying buffer to writer in streaming mode.
 // Otherwise, it does nothing.
+//
+// If checks are set by SetCheck, also returns Err.
 func (e *Encoder) Close() error {
-	return e.w.Close()
+	if err := e.w.Close(); err != nil {
+		return err
+	}
+	return e.Err()
 }
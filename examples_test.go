@@ -154,6 +154,22 @@ func ExampleDecoder_Base64() {
 	// Hello
 }
 
+func ExampleDecoder_Any() {
+	v, err := jx.DecodeStr(`{"id":1,"tags":["a","b"],"price":1.50}`).Any()
+	if err != nil {
+		panic(err)
+	}
+
+	v.Field("tags").Append(jx.Any{Type: jx.AnyStr, Str: "c"})
+	v.Set("id", jx.Any{Type: jx.AnyNumber, Number: jx.Num("2")})
+	v.Delete("price")
+
+	var e jx.Encoder
+	e.Any(v)
+	fmt.Println(e)
+	// Output: {"id":2,"tags":["a","b","c"]}
+}
+
 func Example() {
 	var e jx.Encoder
 	e.Obj(func(e *jx.Encoder) {
@@ -195,3 +211,180 @@ func ExampleEncoder_SetIdent() {
 	//   ]
 	// }
 }
+
+func ExampleEncoder_SetIndent() {
+	var e jx.Encoder
+	e.SetIndent(jx.IndentOptions{
+		Indent:       "\t",
+		InlineArrays: 40,
+	})
+	e.Obj(func(e *jx.Encoder) {
+		e.Field("data", func(e *jx.Encoder) {
+			e.Arr(func(e *jx.Encoder) {
+				e.Int(1)
+				e.Int(2)
+			})
+		})
+		e.Field("meta", func(e *jx.Encoder) {
+			e.Obj(func(e *jx.Encoder) {
+				e.Field("total", func(e *jx.Encoder) { e.Int(2) })
+			})
+		})
+	})
+	fmt.Println(e)
+
+	e.Reset()
+	e.SetIndent(jx.IndentOptions{Spaced: true})
+	e.Obj(func(e *jx.Encoder) {
+		e.Field("data", func(e *jx.Encoder) {
+			e.Arr(func(e *jx.Encoder) {
+				e.Int(1)
+				e.Int(2)
+			})
+		})
+	})
+	fmt.Println(e)
+
+	// Output:
+	// {
+	// 	"data": [1, 2],
+	// 	"meta": {
+	// 		"total": 2
+	// 	}
+	// }
+	// {"data": [1, 2]}
+}
+
+func ExampleEncoder_RawIndented() {
+	var e jx.Encoder
+	e.SetIdent(2)
+	e.Obj(func(e *jx.Encoder) {
+		e.Field("data", func(e *jx.Encoder) {
+			if err := e.RawIndented([]byte(`{"id":1,"tags":["a"]}`)); err != nil {
+				panic(err)
+			}
+		})
+	})
+	fmt.Println(e)
+
+	// Output:
+	// {
+	//   "data": {
+	//     "id": 1,
+	//     "tags": [
+	//       "a"
+	//     ]
+	//   }
+	// }
+}
+
+func ExampleEncoder_SetCheck() {
+	var e jx.Encoder
+	e.SetCheck(jx.EncoderCheckError)
+	e.ArrStart()
+	e.FieldStart("foo")
+	fmt.Println(e.Err())
+
+	// Output:
+	// FieldStart: not in object
+}
+
+func ExampleDecoder_Seek() {
+	d := jx.DecodeStr(`{"data": [{"name": "foo"}, {"name": "bar"}]}`)
+	if err := d.Seek("/data/1/name"); err != nil {
+		panic(err)
+	}
+	name, err := d.Str()
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(name)
+	// Output: bar
+}
+
+func ExampleDecoder_SeekEnd() {
+	d := jx.DecodeStr(`{"user": {"id": 1, "name": "foo"}} {"user": {"id": 2, "name": "bar"}}`)
+	for d.Next() != jx.Invalid {
+		if err := d.Seek("/user/id"); err != nil {
+			panic(err)
+		}
+		id, err := d.Int()
+		if err != nil {
+			panic(err)
+		}
+		if err := d.SeekEnd(); err != nil {
+			panic(err)
+		}
+		fmt.Println(id)
+	}
+	// Output:
+	// 1
+	// 2
+}
+
+func ExampleDecoder_Extract() {
+	p, err := jx.CompilePointers("/user/name", "/user/id", "/tags/0")
+	if err != nil {
+		panic(err)
+	}
+	d := jx.DecodeStr(`{"user": {"id": 10, "name": "foo"}, "tags": ["a", "b"]}`)
+	if err := d.Extract(p, func(id int, d *jx.Decoder) error {
+		raw, err := d.Raw()
+		if err != nil {
+			return err
+		}
+		fmt.Println(id, raw)
+		return nil
+	}); err != nil {
+		panic(err)
+	}
+	// Output:
+	// 1 10
+	// 0 "foo"
+	// 2 "a"
+}
+
+func ExampleDecoder_Project() {
+	p, err := jx.CompileProjection(jx.ProjectionRules{
+		Exclude: []string{"/users/*/password"},
+		Mask:    []string{"/users/*/email"},
+	})
+	if err != nil {
+		panic(err)
+	}
+	d := jx.DecodeStr(`{"users": [{"name": "foo", "email": "foo@example.com", "password": "123"}]}`)
+	var e jx.Encoder
+	if err := d.Project(p, &e); err != nil {
+		panic(err)
+	}
+	fmt.Println(e)
+	// Output:
+	// {"users":[{"name":"foo","email":null}]}
+}
+
+func ExampleCanonical() {
+	var w jx.Writer
+	if err := jx.Canonical(jx.DecodeStr(`{"b": [1.50, 1e3], "a": "A"}`), &w); err != nil {
+		panic(err)
+	}
+	fmt.Println(w.String())
+	// Output:
+	// {"a":"A","b":[1.5,1000]}
+}
+
+func ExampleIndent() {
+	var e jx.Encoder
+	e.SetIdent(2)
+	if err := jx.Indent(jx.DecodeStr(`{"id":1,"tags":["a","b"]}`), &e); err != nil {
+		panic(err)
+	}
+	fmt.Println(e)
+	// Output:
+	// {
+	//   "id": 1,
+	//   "tags": [
+	//     "a",
+	//     "b"
+	//   ]
+	// }
+}
@@ -53,6 +53,24 @@ func FuzzValid(f *testing.F) {
 	})
 }
 
+func FuzzSkipFast(f *testing.F) {
+	for _, set := range [][]string{
+		testBools,
+		testNumbers,
+		testStrings,
+		testObjs,
+		testArrs,
+	} {
+		for _, s := range set {
+			f.Add([]byte(s))
+		}
+	}
+	addCorpus(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		requireSkipFast(t, data)
+	})
+}
+
 func FuzzDecEnc(f *testing.F) {
 	f.Add([]byte("{}"))
 	f.Add([]byte(`"foo"`))
@@ -0,0 +1,354 @@
+package jx
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/go-faster/errors"
+)
+
+// Index is structural index of json document, allowing random access to
+// its values without decoding the whole document.
+//
+// Index references indexed data, so data must not be modified while Index
+// is used.
+//
+// Every value takes 7 words, and every element of array or object takes
+// one more word.
+type Index struct {
+	data  []byte
+	nodes []indexNode // values in document order
+	// elems are indexes of elements of arrays and objects in nodes, list
+	// of every array or object ends with -1.
+	elems []int
+	stack []int // elements of arrays and objects being indexed
+}
+
+// indexNode is single value of Index.
+type indexNode struct {
+	typ   Type
+	start int // offset of value start
+	end   int // offset of value end
+	len   int // count of elements, for array or object
+	elems int // start of elements in Index.elems, for array or object
+
+	// Offsets of raw key, without quotes, for object field.
+	keyStart, keyEnd int
+}
+
+// BuildIndex builds Index of json document in data.
+func BuildIndex(data []byte) (*Index, error) {
+	x := &Index{}
+	if err := x.Reset(data); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// Reset builds index of data, reusing memory.
+func (x *Index) Reset(data []byte) error {
+	x.data = data
+	x.nodes = x.nodes[:0]
+	x.elems = x.elems[:0]
+	x.stack = x.stack[:0]
+
+	d := GetDecoder()
+	defer PutDecoder(d)
+	d.ResetBytes(data)
+
+	if err := x.build(d); err != nil {
+		x.nodes = x.nodes[:0]
+		x.elems = x.elems[:0]
+		return err
+	}
+	return nil
+}
+
+// build indexes single json document.
+func (x *Index) build(d *Decoder) error {
+	c, err := d.next()
+	if err != nil {
+		return err
+	}
+	if err := x.value(d, c); err != nil {
+		return err
+	}
+	// Check for trailing data.
+	c, err = d.next()
+	switch err {
+	case io.EOF:
+		return nil
+	case nil:
+		return errors.Wrap(d.badToken(c, d.offset()-1), "unexpected trailing data")
+	default:
+		return err
+	}
+}
+
+// Root returns root value of document.
+func (x *Index) Root() Node {
+	if len(x.nodes) == 0 {
+		return Node{}
+	}
+	return Node{x: x, pos: -1}
+}
+
+// value indexes value starting with c.
+func (x *Index) value(d *Decoder, c byte) error {
+	i := len(x.nodes)
+	x.nodes = append(x.nodes, indexNode{
+		typ:   types[c],
+		start: d.offset() - 1,
+	})
+	var err error
+	switch c {
+	case '{':
+		err = x.obj(d, i)
+	case '[':
+		err = x.arr(d, i)
+	default:
+		d.unread()
+		err = d.Skip()
+	}
+	if err != nil {
+		return err
+	}
+	x.nodes[i].end = d.offset()
+	return nil
+}
+
+// endElems saves elements of array or object i, which start at base in
+// stack.
+func (x *Index) endElems(i, base int) {
+	x.nodes[i].elems = len(x.elems)
+	x.elems = append(x.elems, x.stack[base:]...)
+	x.elems = append(x.elems, -1)
+	x.stack = x.stack[:base]
+}
+
+// obj indexes object fields, assuming first bracket was consumed.
+func (x *Index) obj(d *Decoder, i int) error {
+	if err := d.incDepth(); err != nil {
+		return errors.Wrap(err, "inc")
+	}
+	c, err := d.more()
+	if err != nil {
+		return expected(err, `'"' or "}"`)
+	}
+	if c == '}' {
+		return d.decDepth()
+	}
+
+	base := len(x.stack)
+	for n := 1; ; n++ {
+		if c != '"' {
+			return expected(d.badToken(c, d.offset()-1), `'"'`)
+		}
+		keyStart := d.offset()
+		if err := d.skipStr(); err != nil {
+			return errors.Wrap(err, "read field name")
+		}
+		keyEnd := d.offset() - 1
+		if err := d.consume(':'); err != nil {
+			return expected(err, `":"`)
+		}
+		if c, err = d.more(); err != nil {
+			return err
+		}
+
+		j := len(x.nodes)
+		if err := x.value(d, c); err != nil {
+			return d.withRawKey(err, x.data[keyStart:keyEnd], "")
+		}
+		x.nodes[j].keyStart, x.nodes[j].keyEnd = keyStart, keyEnd
+		x.stack = append(x.stack, j)
+		x.nodes[i].len = n
+
+		if c, err = d.more(); err != nil {
+			return expected(err, `"," or "}"`)
+		}
+		switch c {
+		case ',':
+			if c, err = d.more(); err != nil {
+				return expected(err, `'"'`)
+			}
+		case '}':
+			x.endElems(i, base)
+			return d.decDepth()
+		default:
+			return expected(d.badToken(c, d.offset()-1), `"," or "}"`)
+		}
+	}
+}
+
+// arr indexes array elements, assuming first bracket was consumed.
+func (x *Index) arr(d *Decoder, i int) error {
+	if err := d.incDepth(); err != nil {
+		return errors.Wrap(err, "inc")
+	}
+	c, err := d.more()
+	if err != nil {
+		return expected(err, `value or "]"`)
+	}
+	if c == ']' {
+		return d.decDepth()
+	}
+
+	base := len(x.stack)
+	for n := 1; ; n++ {
+		x.stack = append(x.stack, len(x.nodes))
+		if err := x.value(d, c); err != nil {
+			return d.withIndex(err, n-1, "")
+		}
+		x.nodes[i].len = n
+
+		if c, err = d.more(); err != nil {
+			return expected(err, `"," or "]"`)
+		}
+		switch c {
+		case ',':
+			if c, err = d.more(); err != nil {
+				return expected(err, "value")
+			}
+		case ']':
+			x.endElems(i, base)
+			return d.decDepth()
+		default:
+			return expected(d.badToken(c, d.offset()-1), `"," or "]"`)
+		}
+	}
+}
+
+// Node is value of Index.
+//
+// Zero value is invalid node.
+type Node struct {
+	x   *Index
+	i   int
+	pos int // index in Index.elems, -1 for root
+}
+
+func (n Node) node() *indexNode {
+	return &n.x.nodes[n.i]
+}
+
+// Type returns type of value, or Invalid for invalid node.
+func (n Node) Type() Type {
+	if n.x == nil {
+		return Invalid
+	}
+	return n.node().typ
+}
+
+// Raw returns raw json of value.
+func (n Node) Raw() Raw {
+	if n.x == nil {
+		return nil
+	}
+	v := n.node()
+	return n.x.data[v.start:v.end]
+}
+
+// Offset returns offset of value in indexed data.
+func (n Node) Offset() int {
+	if n.x == nil {
+		return 0
+	}
+	return n.node().start
+}
+
+// Key returns decoded key of object field, or nil if n is not a field.
+//
+// Key references indexed data, unless it has escape sequences.
+func (n Node) Key() []byte {
+	if n.x == nil {
+		return nil
+	}
+	v := n.node()
+	if v.keyStart == 0 {
+		return nil
+	}
+	key := n.x.data[v.keyStart:v.keyEnd]
+	if bytes.IndexByte(key, '\\') < 0 {
+		return key
+	}
+	// Data is valid, so error is not possible.
+	key, _ = DecodeBytes(n.x.data[v.keyStart-1 : v.keyEnd+1]).StrBytes()
+	return key
+}
+
+// Len returns count of elements of array or object.
+func (n Node) Len() int {
+	if n.x == nil {
+		return 0
+	}
+	return n.node().len
+}
+
+// First returns first element of array or object.
+func (n Node) First() (Node, bool) {
+	if n.Len() == 0 {
+		return Node{}, false
+	}
+	return n.elem(0), true
+}
+
+func (n Node) elem(i int) Node {
+	pos := n.node().elems + i
+	return Node{x: n.x, i: n.x.elems[pos], pos: pos}
+}
+
+// Next returns next element of parent array or object.
+func (n Node) Next() (Node, bool) {
+	if n.x == nil || n.pos < 0 {
+		return Node{}, false
+	}
+	pos := n.pos + 1
+	next := n.x.elems[pos]
+	if next < 0 {
+		return Node{}, false
+	}
+	return Node{x: n.x, i: next, pos: pos}, true
+}
+
+// Elem returns i-th element of array or object.
+func (n Node) Elem(i int) (Node, bool) {
+	if i < 0 || i >= n.Len() {
+		return Node{}, false
+	}
+	return n.elem(i), true
+}
+
+// Field returns value of object field with given key.
+//
+// If key is duplicated, the first value is returned.
+func (n Node) Field(key string) (Node, bool) {
+	if n.Type() != Object {
+		return Node{}, false
+	}
+	for e, ok := n.First(); ok; e, ok = e.Next() {
+		v := e.node()
+		raw := n.x.data[v.keyStart:v.keyEnd]
+		if string(raw) == key {
+			return e, true
+		}
+		if bytes.IndexByte(raw, '\\') >= 0 && string(e.Key()) == key {
+			return e, true
+		}
+	}
+	return Node{}, false
+}
+
+// Decoder returns Decoder positioned at value start, which reads only
+// this value.
+//
+// Offsets in errors of returned Decoder are relative to indexed data.
+func (n Node) Decoder() *Decoder {
+	if n.x == nil {
+		return DecodeBytes(nil)
+	}
+	v := n.node()
+	d := DecodeBytes(n.x.data[:v.end])
+	d.head = v.start
+	return d
+}
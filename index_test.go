@@ -0,0 +1,222 @@
+package jx
+
+import (
+	"encoding/json"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/go-faster/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndex(t *testing.T) {
+	const input = ` {"foo": [1, "bar", {"a\/b": null}], "baz": {}, "empty": [], "foo": true} `
+	x, err := BuildIndex([]byte(input))
+	require.NoError(t, err)
+
+	root := x.Root()
+	require.Equal(t, Object, root.Type())
+	require.Equal(t, strings.TrimSpace(input), root.Raw().String())
+	require.Equal(t, 1, root.Offset())
+	require.Equal(t, 4, root.Len())
+	require.Nil(t, root.Key())
+
+	var keys []string
+	for e, ok := root.First(); ok; e, ok = e.Next() {
+		keys = append(keys, string(e.Key()))
+	}
+	require.Equal(t, []string{"foo", "baz", "empty", "foo"}, keys)
+
+	foo, ok := root.Field("foo")
+	require.True(t, ok)
+	require.Equal(t, Array, foo.Type())
+	require.Equal(t, 3, foo.Len())
+
+	for i, typ := range []Type{Number, String, Object} {
+		e, ok := foo.Elem(i)
+		require.True(t, ok)
+		require.Equal(t, typ, e.Type())
+		require.Nil(t, e.Key())
+	}
+	_, ok = foo.Elem(3)
+	require.False(t, ok)
+	// Iteration continues from element.
+	bar, _ := foo.Elem(1)
+	next, ok := bar.Next()
+	require.True(t, ok)
+	require.Equal(t, Object, next.Type())
+	_, ok = next.Next()
+	require.False(t, ok)
+	_, ok = foo.Elem(-1)
+	require.False(t, ok)
+
+	obj, _ := foo.Elem(2)
+	v, ok := obj.Field("a/b")
+	require.True(t, ok)
+	require.Equal(t, Null, v.Type())
+	require.Equal(t, "a/b", string(v.Key()))
+	require.Equal(t, "null", v.Raw().String())
+
+	for _, key := range []string{"baz", "empty"} {
+		v, ok := root.Field(key)
+		require.True(t, ok)
+		require.Zero(t, v.Len())
+		_, ok = v.First()
+		require.False(t, ok)
+	}
+	for _, n := range []Node{root, foo} {
+		_, ok = n.Field("missing")
+		require.False(t, ok)
+	}
+	_, ok = root.Next()
+	require.False(t, ok)
+
+	t.Run("Zero", func(t *testing.T) {
+		var n Node
+		require.Equal(t, Invalid, n.Type())
+		require.Nil(t, n.Raw())
+		require.Nil(t, n.Key())
+		require.Zero(t, n.Offset())
+		require.Zero(t, n.Len())
+		_, ok := n.Next()
+		require.False(t, ok)
+		_, ok = n.Elem(0)
+		require.False(t, ok)
+		require.Equal(t, Invalid, n.Decoder().Next())
+		require.Equal(t, Invalid, new(Index).Root().Type())
+	})
+}
+
+func TestNode_Decoder(t *testing.T) {
+	const input = `{"foo": [1, 2], "bar": {"baz": "x"}}`
+	x, err := BuildIndex([]byte(input))
+	require.NoError(t, err)
+
+	foo, _ := x.Root().Field("foo")
+	d := foo.Decoder()
+	var sum int
+	require.NoError(t, d.Arr(func(d *Decoder) error {
+		v, err := d.Int()
+		sum += v
+		return err
+	}))
+	require.Equal(t, 3, sum)
+	// Decoder reads only node value.
+	require.Equal(t, Invalid, d.Next())
+
+	bar, _ := x.Root().Field("bar")
+	_, err = bar.Decoder().Int()
+	se, ok := errors.Into[*SyntaxError](err)
+	require.True(t, ok)
+	require.Equal(t, strings.Index(input, `{"baz"`), se.Offset)
+}
+
+func TestIndex_Errors(t *testing.T) {
+	for _, input := range []string{
+		``,
+		` `,
+		`{`,
+		`{"foo"}`,
+		`{"foo": 1,}`,
+		`{foo: 1}`,
+		`[1, 2`,
+		`[1,]`,
+		`[1 2]`,
+		`"foo`,
+		`nul`,
+		`1 2`,
+		`{} {}`,
+	} {
+		var x Index
+		require.Error(t, x.Reset([]byte(input)), input)
+		require.Equal(t, Invalid, x.Root().Type(), input)
+	}
+
+	_, err := BuildIndex([]byte(`{"foo": [1, {"bar": tru}]}`))
+	se, ok := errors.Into[*SyntaxError](err)
+	require.True(t, ok)
+	require.Equal(t, "/foo/1/bar", se.Pointer)
+}
+
+func TestIndex_Suite(t *testing.T) {
+	dir := path.Join("testdata", "test_parsing")
+	files, err := testdata.ReadDir(dir)
+	require.NoError(t, err)
+
+	for _, f := range files {
+		if !strings.HasSuffix(f.Name(), ".json") {
+			continue
+		}
+		data, err := testdata.ReadFile(path.Join(dir, f.Name()))
+		require.NoError(t, err)
+
+		_, err = BuildIndex(data)
+		require.Equal(t, Valid(data), err == nil, f.Name())
+	}
+}
+
+func TestIndex_Testdata(t *testing.T) {
+	runTestdataFile("twitter.json", t.Fatal, func(name string, data []byte) {
+		var expected struct {
+			Statuses []struct {
+				User json.RawMessage `json:"user"`
+				ID   json.RawMessage `json:"id_str"`
+			} `json:"statuses"`
+		}
+		require.NoError(t, json.Unmarshal(data, &expected))
+
+		x, err := BuildIndex(data)
+		require.NoError(t, err)
+		statuses, ok := x.Root().Field("statuses")
+		require.True(t, ok)
+		require.Equal(t, len(expected.Statuses), statuses.Len())
+
+		// Access in reverse order.
+		for i := len(expected.Statuses) - 1; i >= 0; i-- {
+			e, ok := statuses.Elem(i)
+			require.True(t, ok)
+			user, ok := e.Field("user")
+			require.True(t, ok)
+			require.Equal(t, string(expected.Statuses[i].User), user.Raw().String())
+			id, ok := e.Field("id_str")
+			require.True(t, ok)
+			require.Equal(t, string(expected.Statuses[i].ID), id.Raw().String())
+		}
+	})
+}
+
+func BenchmarkIndex_Reset(b *testing.B) {
+	runTestdataFile("twitter.json", b.Fatal, func(name string, data []byte) {
+		var x Index
+		b.ReportAllocs()
+		b.SetBytes(int64(len(data)))
+
+		for i := 0; i < b.N; i++ {
+			if err := x.Reset(data); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkNode_Elem(b *testing.B) {
+	runTestdataFile("twitter.json", b.Fatal, func(name string, data []byte) {
+		x, err := BuildIndex(data)
+		if err != nil {
+			b.Fatal(err)
+		}
+		statuses, ok := x.Root().Field("statuses")
+		if !ok {
+			b.Fatal("no statuses")
+		}
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			if _, ok := statuses.Elem(statuses.Len() - 1); !ok {
+				b.Fatal("no elem")
+			}
+		}
+	})
+}
@@ -0,0 +1,177 @@
+// Package scan implements vectorised search of special bytes in json input.
+package scan
+
+import (
+	"encoding/binary"
+	"math/bits"
+
+	"github.com/go-faster/jx/internal/byteseq"
+)
+
+const (
+	lsb = 0x0101010101010101
+	msb = 0x8080808080808080
+)
+
+// strGeneric is portable implementation of Str, processing 8 bytes at a
+// time.
+func strGeneric[S byteseq.Byteseq](b S) int {
+	i := 0
+	for ; len(b)-i >= 8; i += 8 {
+		w := load64(b, i)
+		// High bit is set for the first byte which is quote, backslash
+		// or less than 0x20, bits for the following bytes may be wrong.
+		m := eq(w, '"') | eq(w, '\\') | less(w, ' ')
+		if m &= msb; m != 0 {
+			return i + bits.TrailingZeros64(m)/8
+		}
+	}
+	for ; i < len(b); i++ {
+		if c := b[i]; c == '"' || c == '\\' || c < ' ' {
+			return i
+		}
+	}
+	return len(b)
+}
+
+// strEscapeGeneric is portable implementation of StrEscape, processing 8
+// bytes at a time.
+func strEscapeGeneric[S byteseq.Byteseq](b S) int {
+	i := 0
+	for ; len(b)-i >= 8; i += 8 {
+		w := load64(b, i)
+		m := eq(w, '"') | eq(w, '\\') | less(w, ' ') |
+			eq(w, '<') | eq(w, '>') | eq(w, '&') | w
+		if m &= msb; m != 0 {
+			return i + bits.TrailingZeros64(m)/8
+		}
+	}
+	for ; i < len(b); i++ {
+		if c := b[i]; c == '"' || c == '\\' || c < ' ' ||
+			c == '<' || c == '>' || c == '&' || c >= 0x80 {
+			return i
+		}
+	}
+	return len(b)
+}
+
+// Block is classification of 64 bytes of json input, bit i of each mask is
+// set if byte i is in its class.
+type Block struct {
+	Quote     uint64 // '"'
+	Backslash uint64 // '\\'
+	Op        uint64 // one of "{}[]:,"
+	Space     uint64 // one of " \t\n\r"
+	Control   uint64 // less than 0x20
+}
+
+// classifyGeneric is portable implementation of Block.Classify.
+func (m *Block) classifyGeneric(b []byte) {
+	*m = Block{}
+	for i, c := range b[:64] {
+		bit := uint64(1) << i
+		switch c {
+		case '"':
+			m.Quote |= bit
+		case '\\':
+			m.Backslash |= bit
+		case '{', '}', '[', ']', ':', ',':
+			m.Op |= bit
+		case ' ':
+			m.Space |= bit
+		case '\t', '\n', '\r':
+			m.Space |= bit
+			m.Control |= bit
+		default:
+			if c < ' ' {
+				m.Control |= bit
+			}
+		}
+	}
+}
+
+// load64 loads 8 bytes of b starting from i as little-endian word.
+func load64[S byteseq.Byteseq](b S, i int) uint64 {
+	_ = b[i+7]
+	return uint64(b[i]) | uint64(b[i+1])<<8 | uint64(b[i+2])<<16 | uint64(b[i+3])<<24 |
+		uint64(b[i+4])<<32 | uint64(b[i+5])<<40 | uint64(b[i+6])<<48 | uint64(b[i+7])<<56
+}
+
+// eq sets high bit of the first byte of w equal to c. High bits of the
+// following bytes may be set incorrectly.
+func eq(w uint64, c byte) uint64 {
+	x := w ^ (uint64(c) * lsb)
+	return (x - lsb) &^ x
+}
+
+// less sets high bit of the first byte of w less than c, which is at most
+// 0x80. High bits of the following bytes may be set incorrectly.
+func less(w uint64, c byte) uint64 {
+	return (w - uint64(c)*lsb) &^ w
+}
+
+// Number returns length of valid json number at the start of b, which is
+// followed by comma, closing bracket, whitespace or end of b, or 0 if there
+// is none.
+func Number(b []byte) int {
+	i := 0
+	if i < len(b) && b[i] == '-' {
+		i++
+	}
+	switch {
+	case i == len(b):
+		return 0
+	case b[i] == '0':
+		i++
+	case b[i] >= '1' && b[i] <= '9':
+		i = digits(b, i+1)
+	default:
+		return 0
+	}
+	if i < len(b) && b[i] == '.' {
+		j := digits(b, i+1)
+		if j == i+1 {
+			return 0
+		}
+		i = j
+	}
+	if i < len(b) && (b[i] == 'e' || b[i] == 'E') {
+		i++
+		if i < len(b) && (b[i] == '-' || b[i] == '+') {
+			i++
+		}
+		j := digits(b, i)
+		if j == i {
+			return 0
+		}
+		i = j
+	}
+	if i == len(b) {
+		return i
+	}
+	switch b[i] {
+	case ',', ']', '}', ' ', '\t', '\n', '\r':
+		return i
+	default:
+		return 0
+	}
+}
+
+// digits returns index of the first non-digit byte in b starting from i.
+func digits(b []byte, i int) int {
+	for len(b)-i >= 8 {
+		w := binary.LittleEndian.Uint64(b[i:])
+		// High nibble of byte is zero iff it is in '0'-'9': then its high
+		// nibble is 3 both before and after adding 6. Carry from adding 6
+		// to non-digit byte only affects the following bytes.
+		m := (w&(0xf0*lsb) ^ 0x30*lsb) | ((w+6*lsb)&(0xf0*lsb) ^ 0x30*lsb)
+		if m != 0 {
+			return i + bits.TrailingZeros64(m)/8
+		}
+		i += 8
+	}
+	for i < len(b) && b[i] >= '0' && b[i] <= '9' {
+		i++
+	}
+	return i
+}
@@ -0,0 +1,67 @@
+//go:build amd64 && !purego
+
+package scan
+
+import (
+	"unsafe"
+
+	"github.com/segmentio/asm/cpu"
+	"github.com/segmentio/asm/cpu/x86"
+
+	"github.com/go-faster/jx/internal/byteseq"
+)
+
+var hasAVX2 = cpu.X86.Has(x86.AVX2)
+
+// Str returns index of the first quote, backslash or control character in b,
+// or len(b) if there is none.
+func Str[S byteseq.Byteseq](b S) int {
+	if hasAVX2 && len(b) >= 32 {
+		return strAVX2(data(b), len(b))
+	}
+	return strGeneric(b)
+}
+
+// StrEscape is Str that also stops at HTML special characters '<', '>', '&'
+// and at non-ASCII bytes.
+func StrEscape[S byteseq.Byteseq](b S) int {
+	if hasAVX2 && len(b) >= 32 {
+		return strEscapeAVX2(data(b), len(b))
+	}
+	return strEscapeGeneric(b)
+}
+
+// BlockFast reports whether Block.Classify is vectorised. Otherwise it is
+// slower than byte-at-a-time scan.
+var BlockFast = hasAVX2
+
+// Classify sets masks of m for the first 64 bytes of b.
+func (m *Block) Classify(b []byte) {
+	_ = b[63]
+	if hasAVX2 {
+		classifyAVX2(data(b), m)
+		return
+	}
+	m.classifyGeneric(b)
+}
+
+// data returns pointer to contents of b.
+func data[S byteseq.Byteseq](b S) unsafe.Pointer {
+	// Both string and slice headers start with data pointer.
+	return *(*unsafe.Pointer)(unsafe.Pointer(&b))
+}
+
+// strAVX2 is Str for n >= 32 bytes at p.
+//
+//go:noescape
+func strAVX2(p unsafe.Pointer, n int) int
+
+// strEscapeAVX2 is StrEscape for n >= 32 bytes at p.
+//
+//go:noescape
+func strEscapeAVX2(p unsafe.Pointer, n int) int
+
+// classifyAVX2 is Block.Classify for 64 bytes at p.
+//
+//go:noescape
+func classifyAVX2(p unsafe.Pointer, m *Block)
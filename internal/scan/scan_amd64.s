@@ -0,0 +1,279 @@
+//go:build amd64 && !purego
+
+#include "textflag.h"
+
+DATA quote<>+0(SB)/1, $0x22
+GLOBL quote<>(SB), RODATA|NOPTR, $1
+
+DATA backslash<>+0(SB)/1, $0x5c
+GLOBL backslash<>(SB), RODATA|NOPTR, $1
+
+DATA control<>+0(SB)/1, $0x1f
+GLOBL control<>(SB), RODATA|NOPTR, $1
+
+DATA less<>+0(SB)/1, $0x3c
+GLOBL less<>(SB), RODATA|NOPTR, $1
+
+DATA greater<>+0(SB)/1, $0x3e
+GLOBL greater<>(SB), RODATA|NOPTR, $1
+
+DATA amp<>+0(SB)/1, $0x26
+GLOBL amp<>(SB), RODATA|NOPTR, $1
+
+// STR sets bytes of x which are quote, backslash or control character to
+// 0xff, other bytes to zero. Clobbers Y7 and Y8.
+#define STR(x) \
+	VPCMPEQB x, Y1, Y7; \
+	VPCMPEQB x, Y2, Y8; \
+	VPOR     Y7, Y8, Y7; \
+	VPSUBUSB Y3, x, x; \
+	VPCMPEQB x, Y4, x; \
+	VPOR     Y7, x, x
+
+// ESC is STR that also matches '<', '>', '&' and non-ASCII bytes.
+#define ESC(x) \
+	VPCMPEQB x, Y1, Y7; \
+	VPCMPEQB x, Y2, Y8; \
+	VPOR     Y7, Y8, Y7; \
+	VPCMPEQB x, Y9, Y8; \
+	VPOR     Y7, Y8, Y7; \
+	VPCMPEQB x, Y10, Y8; \
+	VPOR     Y7, Y8, Y7; \
+	VPCMPEQB x, Y11, Y8; \
+	VPOR     Y7, Y8, Y7; \
+	VPCMPGTB x, Y4, Y8; \
+	VPOR     Y7, Y8, Y7; \
+	VPSUBUSB Y3, x, x; \
+	VPCMPEQB x, Y4, x; \
+	VPOR     Y7, x, x
+
+// func strAVX2(p unsafe.Pointer, n int) int
+//
+// Returns index of the first quote, backslash or control character.
+//
+// Requires: AVX, AVX2
+TEXT ·strAVX2(SB), NOSPLIT, $0-24
+	MOVQ p+0(FP), SI
+	MOVQ n+8(FP), CX
+	XORQ AX, AX
+
+	VPBROADCASTB quote<>(SB), Y1
+	VPBROADCASTB backslash<>(SB), Y2
+	VPBROADCASTB control<>(SB), Y3
+	VPXOR        Y4, Y4, Y4
+
+loop64:
+	LEAQ 64(AX), DX
+	CMPQ DX, CX
+	JA   loop32
+
+	VMOVDQU (SI)(AX*1), Y5
+	VMOVDQU 32(SI)(AX*1), Y6
+	STR(Y5)
+	STR(Y6)
+	VPOR    Y5, Y6, Y7
+	VPTEST  Y7, Y7
+	JNZ     found64
+	MOVQ    DX, AX
+	JMP     loop64
+
+found64:
+	VPMOVMSKB Y5, DX
+	VPMOVMSKB Y6, BX
+	SHLQ      $32, BX
+	ORQ       BX, DX
+	BSFQ      DX, DX
+	ADDQ      DX, AX
+	JMP       done
+
+loop32:
+	LEAQ 32(AX), DX
+	CMPQ DX, CX
+	JA   tail
+
+	VMOVDQU   (SI)(AX*1), Y5
+	STR(Y5)
+	VPMOVMSKB Y5, BX
+	TESTL     BX, BX
+	JNZ       found32
+	MOVQ      DX, AX
+	JMP       loop32
+
+tail:
+	// Check the last 32 bytes, which overlap with already checked ones.
+	MOVQ      CX, AX
+	SUBQ      $32, AX
+	VMOVDQU   (SI)(AX*1), Y5
+	STR(Y5)
+	VPMOVMSKB Y5, BX
+	TESTL     BX, BX
+	JNZ       found32
+	MOVQ      CX, AX
+	JMP       done
+
+found32:
+	BSFL BX, BX
+	ADDQ BX, AX
+
+done:
+	VZEROUPPER
+	MOVQ AX, ret+16(FP)
+	RET
+
+// func strEscapeAVX2(p unsafe.Pointer, n int) int
+//
+// Returns index of the first quote, backslash, control character, '<', '>',
+// '&' or non-ASCII byte.
+//
+// Requires: AVX, AVX2
+TEXT ·strEscapeAVX2(SB), NOSPLIT, $0-24
+	MOVQ p+0(FP), SI
+	MOVQ n+8(FP), CX
+	XORQ AX, AX
+
+	VPBROADCASTB quote<>(SB), Y1
+	VPBROADCASTB backslash<>(SB), Y2
+	VPBROADCASTB control<>(SB), Y3
+	VPXOR        Y4, Y4, Y4
+	VPBROADCASTB less<>(SB), Y9
+	VPBROADCASTB greater<>(SB), Y10
+	VPBROADCASTB amp<>(SB), Y11
+
+loop64:
+	LEAQ 64(AX), DX
+	CMPQ DX, CX
+	JA   loop32
+
+	VMOVDQU (SI)(AX*1), Y5
+	VMOVDQU 32(SI)(AX*1), Y6
+	ESC(Y5)
+	ESC(Y6)
+	VPOR    Y5, Y6, Y7
+	VPTEST  Y7, Y7
+	JNZ     found64
+	MOVQ    DX, AX
+	JMP     loop64
+
+found64:
+	VPMOVMSKB Y5, DX
+	VPMOVMSKB Y6, BX
+	SHLQ      $32, BX
+	ORQ       BX, DX
+	BSFQ      DX, DX
+	ADDQ      DX, AX
+	JMP       done
+
+loop32:
+	LEAQ 32(AX), DX
+	CMPQ DX, CX
+	JA   tail
+
+	VMOVDQU   (SI)(AX*1), Y5
+	ESC(Y5)
+	VPMOVMSKB Y5, BX
+	TESTL     BX, BX
+	JNZ       found32
+	MOVQ      DX, AX
+	JMP       loop32
+
+tail:
+	// Check the last 32 bytes, which overlap with already checked ones.
+	MOVQ      CX, AX
+	SUBQ      $32, AX
+	VMOVDQU   (SI)(AX*1), Y5
+	ESC(Y5)
+	VPMOVMSKB Y5, BX
+	TESTL     BX, BX
+	JNZ       found32
+	MOVQ      CX, AX
+	JMP       done
+
+found32:
+	BSFL BX, BX
+	ADDQ BX, AX
+
+done:
+	VZEROUPPER
+	MOVQ AX, ret+16(FP)
+	RET
+
+DATA space<>+0(SB)/1, $0x20
+GLOBL space<>(SB), RODATA|NOPTR, $1
+
+// Whitespace by low nibble, other entries do not match their index.
+DATA spaceTable<>+0(SB)/8, $0x0271641164646420
+DATA spaceTable<>+8(SB)/8, $0x64640d64700a0964
+DATA spaceTable<>+16(SB)/8, $0x0271641164646420
+DATA spaceTable<>+24(SB)/8, $0x64640d64700a0964
+GLOBL spaceTable<>(SB), RODATA|NOPTR, $32
+
+// Operators by low nibble, with 0x20 bit set.
+DATA opTable<>+0(SB)/8, $0
+DATA opTable<>+8(SB)/8, $0x00007d2c7b3a0000
+DATA opTable<>+16(SB)/8, $0
+DATA opTable<>+24(SB)/8, $0x00007d2c7b3a0000
+GLOBL opTable<>(SB), RODATA|NOPTR, $32
+
+// CLASSIFY sets quote, backslash, op, space and control masks of 32 bytes in
+// x to q, b, o, s and c.
+//
+// Whitespace and operators are found by lookup of low nibble, as in
+// simdjson. Byte is operator if its lookup is equal to byte with 0x20 bit
+// set, which turns brackets into braces and also matches control characters
+// 0x1a and 0x0c, so they are excluded. Clobbers Y13, Y14 and Y15.
+#define CLASSIFY(x, q, b, o, s, c) \
+	VPCMPEQB  x, Y1, Y14; \
+	VPMOVMSKB Y14, q; \
+	VPCMPEQB  x, Y2, Y14; \
+	VPMOVMSKB Y14, b; \
+	VPSHUFB   x, Y5, Y14; \
+	VPCMPEQB  x, Y14, Y14; \
+	VPMOVMSKB Y14, s; \
+	VPMINUB   x, Y3, Y13; \
+	VPCMPEQB  x, Y13, Y13; \
+	VPMOVMSKB Y13, c; \
+	VPSHUFB   x, Y6, Y14; \
+	VPOR      x, Y4, Y15; \
+	VPCMPEQB  Y14, Y15, Y14; \
+	VPANDN    Y14, Y13, Y14; \
+	VPMOVMSKB Y14, o
+
+// func classifyAVX2(p unsafe.Pointer, m *Block)
+//
+// Sets masks of m for 64 bytes at p.
+//
+// Requires: AVX, AVX2
+TEXT ·classifyAVX2(SB), NOSPLIT, $0-16
+	MOVQ p+0(FP), SI
+	MOVQ m+8(FP), DI
+
+	VPBROADCASTB quote<>(SB), Y1
+	VPBROADCASTB backslash<>(SB), Y2
+	VPBROADCASTB control<>(SB), Y3
+	VPBROADCASTB space<>(SB), Y4
+	VMOVDQU      spaceTable<>(SB), Y5
+	VMOVDQU      opTable<>(SB), Y6
+
+	VMOVDQU (SI), Y0
+	VMOVDQU 32(SI), Y12
+	CLASSIFY(Y0, AX, BX, CX, DX, R8)
+	CLASSIFY(Y12, R9, R10, R11, R12, R13)
+
+	SHLQ $32, R9
+	ORQ  R9, AX
+	MOVQ AX, 0(DI)
+	SHLQ $32, R10
+	ORQ  R10, BX
+	MOVQ BX, 8(DI)
+	SHLQ $32, R11
+	ORQ  R11, CX
+	MOVQ CX, 16(DI)
+	SHLQ $32, R12
+	ORQ  R12, DX
+	MOVQ DX, 24(DI)
+	SHLQ $32, R13
+	ORQ  R13, R8
+	MOVQ R8, 32(DI)
+
+	VZEROUPPER
+	RET
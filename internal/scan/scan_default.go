@@ -0,0 +1,26 @@
+//go:build !amd64 || purego
+
+package scan
+
+import "github.com/go-faster/jx/internal/byteseq"
+
+// Str returns index of the first quote, backslash or control character in b,
+// or len(b) if there is none.
+func Str[S byteseq.Byteseq](b S) int {
+	return strGeneric(b)
+}
+
+// StrEscape is Str that also stops at HTML special characters '<', '>', '&'
+// and at non-ASCII bytes.
+func StrEscape[S byteseq.Byteseq](b S) int {
+	return strEscapeGeneric(b)
+}
+
+// BlockFast reports whether Block.Classify is vectorised. Otherwise it is
+// slower than byte-at-a-time scan.
+const BlockFast = false
+
+// Classify sets masks of m for the first 64 bytes of b.
+func (m *Block) Classify(b []byte) {
+	m.classifyGeneric(b)
+}
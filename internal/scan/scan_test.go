@@ -0,0 +1,196 @@
+package scan
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func strNaive(b []byte) int {
+	for i, c := range b {
+		if c == '"' || c == '\\' || c < ' ' {
+			return i
+		}
+	}
+	return len(b)
+}
+
+func digitsNaive(b []byte) int {
+	for i, c := range b {
+		if c < '0' || c > '9' {
+			return i
+		}
+	}
+	return len(b)
+}
+
+func escapeNaive(b []byte) int {
+	for i, c := range b {
+		if c == '"' || c == '\\' || c < ' ' || c == '<' || c == '>' || c == '&' || c >= 0x80 {
+			return i
+		}
+	}
+	return len(b)
+}
+
+func testScan(t *testing.T, naive func(b []byte) int, fns ...func(b []byte) int) {
+	for _, n := range []int{0, 1, 7, 8, 9, 31, 32, 33, 63, 64, 65, 127, 200} {
+		for c := 0; c < 256; c++ {
+			for _, pos := range []int{0, n / 3, n / 2, n - 1, n} {
+				if pos < 0 {
+					continue
+				}
+				b := bytes.Repeat([]byte{'a'}, n)
+				if pos < n {
+					b[pos] = byte(c)
+				}
+				expected := naive(b)
+				for _, f := range fns {
+					require.Equal(t, expected, f(b), "n=%d pos=%d c=%#x", n, pos, c)
+				}
+			}
+		}
+	}
+}
+
+func TestStr(t *testing.T) {
+	testScan(t, strNaive,
+		Str[[]byte],
+		strGeneric[[]byte],
+		func(b []byte) int { return Str(string(b)) },
+		func(b []byte) int { return strGeneric(string(b)) },
+	)
+}
+
+func TestStrEscape(t *testing.T) {
+	testScan(t, escapeNaive,
+		StrEscape[[]byte],
+		strEscapeGeneric[[]byte],
+		func(b []byte) int { return StrEscape(string(b)) },
+		func(b []byte) int { return strEscapeGeneric(string(b)) },
+	)
+}
+
+func TestDigits(t *testing.T) {
+	for _, n := range []int{0, 1, 7, 8, 9, 16, 17} {
+		for c := 0; c < 256; c++ {
+			for pos := 0; pos <= n; pos++ {
+				b := bytes.Repeat([]byte{'5'}, n)
+				if pos < n {
+					b[pos] = byte(c)
+				}
+				require.Equal(t, digitsNaive(b), digits(b, 0), "n=%d pos=%d c=%#x", n, pos, c)
+			}
+		}
+	}
+}
+
+func TestNumber(t *testing.T) {
+	for _, tt := range []struct {
+		input string
+		n     int
+	}{
+		{"0", 1},
+		{"-0", 2},
+		{"1", 1},
+		{"123456789012345678", 18},
+		{"-65.613616999999977,", 19},
+		{"1.5e10]", 6},
+		{"1E+2}", 4},
+		{"1e-2 ", 4},
+		{"0.1\n", 3},
+		{"12\t", 2},
+		{"12\r", 2},
+
+		{"", 0},
+		{"-", 0},
+		{"01", 0},
+		{"-a", 0},
+		{"1.", 0},
+		{"1.e5", 0},
+		{".1", 0},
+		{"1e", 0},
+		{"1e+", 0},
+		{"1x", 0},
+		{"1:", 0},
+		{"1.2.3", 0},
+		{"+1", 0},
+	} {
+		require.Equal(t, tt.n, Number([]byte(tt.input)), "%q", tt.input)
+	}
+}
+
+func TestBlock(t *testing.T) {
+	for c := 0; c < 256; c++ {
+		for pos := 0; pos < 64; pos++ {
+			b := bytes.Repeat([]byte{'a'}, 64)
+			b[pos] = byte(c)
+			b[63-pos] = byte(255 - c)
+
+			var expected, got Block
+			expected.classifyGeneric(b)
+			got.Classify(b)
+			require.Equal(t, expected, got, "pos=%d c=%#x", pos, c)
+		}
+	}
+	var m Block
+	m.Classify([]byte(`{"a\\":[1, 2]}` + "\t\x01" + strings.Repeat(" ", 48)))
+	require.Equal(t, Block{
+		Quote:     1<<1 | 1<<5,
+		Backslash: 1<<3 | 1<<4,
+		Op:        1<<0 | 1<<6 | 1<<7 | 1<<9 | 1<<12 | 1<<13,
+		Space:     1<<10 | 1<<14 | (math.MaxUint64 - (1<<16 - 1)),
+		Control:   1<<14 | 1<<15,
+	}, m)
+}
+
+func BenchmarkStr(b *testing.B) {
+	for _, n := range []int{8, 32, 128, 1024} {
+		data := bytes.Repeat([]byte{'a'}, n)
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			b.SetBytes(int64(n))
+			for i := 0; i < b.N; i++ {
+				if Str(data) != n {
+					b.Fatal("mismatch")
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkStrEscape(b *testing.B) {
+	for _, n := range []int{8, 32, 128, 1024} {
+		data := strings.Repeat("a", n)
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			b.SetBytes(int64(n))
+			for i := 0; i < b.N; i++ {
+				if StrEscape(data) != n {
+					b.Fatal("mismatch")
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkBlock(b *testing.B) {
+	data := bytes.Repeat([]byte(`{"a":[1,2]} `), 6)
+	b.SetBytes(64)
+	var m Block
+	for i := 0; i < b.N; i++ {
+		m.Classify(data)
+	}
+}
+
+func BenchmarkNumber(b *testing.B) {
+	data := []byte("-65.613616999999977,")
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		if Number(data) != len(data)-1 {
+			b.Fatal("mismatch")
+		}
+	}
+}
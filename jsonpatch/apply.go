@@ -0,0 +1,262 @@
+package jsonpatch
+
+import (
+	"strings"
+
+	"github.com/go-faster/errors"
+
+	"github.com/go-faster/jx"
+)
+
+// Apply reads json value from d, applies patch to it and writes result to e.
+//
+// Patch is applied atomically, nothing is written if any operation fails.
+// Values that are not changed by patch are written as raw spans of input.
+//
+// Returns *Error if operation fails. Error wraps jx.ErrNotFound if
+// referenced value does not exist and ErrTestFailed if Test operation fails.
+func (p Patch) Apply(d *jx.Decoder, e *jx.Encoder) error {
+	raw, err := d.Raw()
+	if err != nil {
+		return errors.Wrap(err, "read document")
+	}
+	doc := &document{root: &node{raw: raw}}
+	for i, op := range p {
+		if ptr, err := doc.apply(op); err != nil {
+			return &Error{Index: i, Op: op.Op, Path: ptr, Err: err}
+		}
+	}
+	doc.root.write(e)
+	return nil
+}
+
+// document is patched json document.
+type document struct {
+	root *node
+}
+
+// apply applies single operation, returning failed pointer on error.
+func (doc *document) apply(op Operation) (string, error) {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return op.Path, err
+	}
+	switch op.Op {
+	case Add:
+		v, err := valueNode(op.Value)
+		if err != nil {
+			return op.Path, err
+		}
+		return op.Path, doc.add(path, v)
+	case Remove:
+		_, err := doc.remove(path)
+		return op.Path, err
+	case Replace:
+		v, err := valueNode(op.Value)
+		if err != nil {
+			return op.Path, err
+		}
+		return op.Path, doc.replace(path, v)
+	case Move, Copy:
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return op.From, err
+		}
+		if op.Op == Copy || op.From == op.Path {
+			v, err := doc.get(from)
+			if err != nil {
+				return op.From, err
+			}
+			if op.Op == Move {
+				return "", nil
+			}
+			return op.Path, doc.add(path, v.clone())
+		}
+		if strings.HasPrefix(op.Path, op.From+"/") {
+			return op.From, errors.New("value can not be moved into itself")
+		}
+		v, err := doc.remove(from)
+		if err != nil {
+			return op.From, err
+		}
+		return op.Path, doc.add(path, v)
+	case Test:
+		v, err := doc.get(path)
+		if err != nil {
+			return op.Path, err
+		}
+		equal, err := v.equal(op.Value)
+		if err != nil {
+			return op.Path, err
+		}
+		if !equal {
+			return op.Path, ErrTestFailed
+		}
+		return "", nil
+	default:
+		return op.Path, errors.Errorf("unknown op %q", op.Op)
+	}
+}
+
+// valueNode returns node of value of operation.
+func valueNode(raw jx.Raw) (*node, error) {
+	if err := jx.DecodeBytes(raw).Validate(); err != nil {
+		return nil, errors.Wrap(err, "value")
+	}
+	return &node{raw: raw}, nil
+}
+
+// equal reports whether n is equal to raw value.
+func (n *node) equal(raw jx.Raw) (bool, error) {
+	a, err := n.any()
+	if err != nil {
+		return false, err
+	}
+	b, err := jx.DecodeBytes(raw).Any()
+	if err != nil {
+		return false, errors.Wrap(err, "value")
+	}
+	return a.Equal(b), nil
+}
+
+// child returns child of n referenced by token.
+func (n *node) child(tok string) (*node, error) {
+	if err := n.expand(); err != nil {
+		return nil, err
+	}
+	switch n.kind {
+	case kindObj:
+		i := n.field(tok)
+		if i < 0 {
+			return nil, jx.ErrNotFound
+		}
+		return n.fields[i].val, nil
+	case kindArr:
+		i, err := index(tok, len(n.elems), false)
+		if err != nil {
+			return nil, err
+		}
+		return n.elems[i], nil
+	default:
+		return nil, jx.ErrNotFound
+	}
+}
+
+// get returns value referenced by tokens.
+func (doc *document) get(toks []string) (*node, error) {
+	n := doc.root
+	for _, tok := range toks {
+		c, err := n.child(tok)
+		if err != nil {
+			return nil, err
+		}
+		n = c
+	}
+	return n, nil
+}
+
+// parent returns expanded parent of value referenced by non-empty tokens and
+// the last token.
+func (doc *document) parent(toks []string) (*node, string, error) {
+	p, err := doc.get(toks[:len(toks)-1])
+	if err != nil {
+		return nil, "", err
+	}
+	if err := p.expand(); err != nil {
+		return nil, "", err
+	}
+	return p, toks[len(toks)-1], nil
+}
+
+// add adds value or replaces existing field.
+func (doc *document) add(toks []string, v *node) error {
+	if len(toks) == 0 {
+		doc.root = v
+		return nil
+	}
+	p, tok, err := doc.parent(toks)
+	if err != nil {
+		return err
+	}
+	switch p.kind {
+	case kindObj:
+		if i := p.field(tok); i >= 0 {
+			p.fields[i].val = v
+			return nil
+		}
+		p.fields = append(p.fields, field{key: tok, val: v})
+		return nil
+	case kindArr:
+		i, err := index(tok, len(p.elems), true)
+		if err != nil {
+			return err
+		}
+		p.elems = append(p.elems, nil)
+		copy(p.elems[i+1:], p.elems[i:])
+		p.elems[i] = v
+		return nil
+	default:
+		return jx.ErrNotFound
+	}
+}
+
+// remove removes value, returning it.
+func (doc *document) remove(toks []string) (*node, error) {
+	if len(toks) == 0 {
+		return nil, errors.New("root value can not be removed")
+	}
+	p, tok, err := doc.parent(toks)
+	if err != nil {
+		return nil, err
+	}
+	switch p.kind {
+	case kindObj:
+		i := p.field(tok)
+		if i < 0 {
+			return nil, jx.ErrNotFound
+		}
+		v := p.fields[i].val
+		p.fields = append(p.fields[:i], p.fields[i+1:]...)
+		return v, nil
+	case kindArr:
+		i, err := index(tok, len(p.elems), false)
+		if err != nil {
+			return nil, err
+		}
+		v := p.elems[i]
+		p.elems = append(p.elems[:i], p.elems[i+1:]...)
+		return v, nil
+	default:
+		return nil, jx.ErrNotFound
+	}
+}
+
+// replace replaces existing value.
+func (doc *document) replace(toks []string, v *node) error {
+	if len(toks) == 0 {
+		doc.root = v
+		return nil
+	}
+	p, tok, err := doc.parent(toks)
+	if err != nil {
+		return err
+	}
+	switch p.kind {
+	case kindObj:
+		i := p.field(tok)
+		if i < 0 {
+			return jx.ErrNotFound
+		}
+		p.fields[i].val = v
+		return nil
+	case kindArr:
+		i, err := index(tok, len(p.elems), false)
+		if err != nil {
+			return err
+		}
+		p.elems[i] = v
+		return nil
+	default:
+		return jx.ErrNotFound
+	}
+}
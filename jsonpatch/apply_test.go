@@ -0,0 +1,246 @@
+package jsonpatch
+
+import (
+	"testing"
+
+	"github.com/go-faster/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-faster/jx"
+)
+
+func apply(t *testing.T, doc, patch string) (string, error) {
+	t.Helper()
+	p, err := Parse(jx.DecodeStr(patch))
+	require.NoError(t, err)
+
+	var e jx.Encoder
+	if err := p.Apply(jx.DecodeStr(doc), &e); err != nil {
+		require.Empty(t, e.Bytes(), "patch must be atomic")
+		return "", err
+	}
+	return e.String(), nil
+}
+
+func requireJSONEqual(t *testing.T, expected, actual string) {
+	t.Helper()
+	a, err := jx.DecodeStr(expected).Any()
+	require.NoError(t, err)
+	b, err := jx.DecodeStr(actual).Any()
+	require.NoError(t, err)
+	require.True(t, a.Equal(b), "expected %s, got %s", expected, actual)
+}
+
+func TestPatch_Apply(t *testing.T) {
+	// Examples from RFC 6902, Appendix A.
+	for _, tt := range []struct {
+		Name   string
+		Doc    string
+		Patch  string
+		Expect string
+	}{
+		{
+			"AddField",
+			`{"foo": "bar"}`,
+			`[{"op": "add", "path": "/baz", "value": "qux"}]`,
+			`{"baz": "qux", "foo": "bar"}`,
+		},
+		{
+			"AddElem",
+			`{"foo": ["bar", "baz"]}`,
+			`[{"op": "add", "path": "/foo/1", "value": "qux"}]`,
+			`{"foo": ["bar", "qux", "baz"]}`,
+		},
+		{
+			"RemoveField",
+			`{"baz": "qux", "foo": "bar"}`,
+			`[{"op": "remove", "path": "/baz"}]`,
+			`{"foo": "bar"}`,
+		},
+		{
+			"RemoveElem",
+			`{"foo": ["bar", "qux", "baz"]}`,
+			`[{"op": "remove", "path": "/foo/1"}]`,
+			`{"foo": ["bar", "baz"]}`,
+		},
+		{
+			"Replace",
+			`{"baz": "qux", "foo": "bar"}`,
+			`[{"op": "replace", "path": "/baz", "value": "boo"}]`,
+			`{"baz": "boo", "foo": "bar"}`,
+		},
+		{
+			"Move",
+			`{"foo": {"bar": "baz", "waldo": "fred"}, "qux": {"corge": "grault"}}`,
+			`[{"op": "move", "from": "/foo/waldo", "path": "/qux/thud"}]`,
+			`{"foo": {"bar": "baz"}, "qux": {"corge": "grault", "thud": "fred"}}`,
+		},
+		{
+			"MoveElem",
+			`{"foo": ["all", "grass", "cows", "eat"]}`,
+			`[{"op": "move", "from": "/foo/1", "path": "/foo/3"}]`,
+			`{"foo": ["all", "cows", "eat", "grass"]}`,
+		},
+		{
+			"Test",
+			`{"baz": "qux", "foo": ["a", 2, "c"]}`,
+			`[
+  {"op": "test", "path": "/baz", "value": "qux"},
+  {"op": "test", "path": "/foo/1", "value": 2}
+]`,
+			`{"baz": "qux", "foo": ["a", 2, "c"]}`,
+		},
+		{
+			"AddNested",
+			`{"foo": "bar"}`,
+			`[{"op": "add", "path": "/child", "value": {"grandchild": {}}}]`,
+			`{"foo": "bar", "child": {"grandchild": {}}}`,
+		},
+		{
+			"IgnoreUnknown",
+			`{"foo": "bar"}`,
+			`[{"op": "add", "path": "/baz", "value": "qux", "xyz": 123}]`,
+			`{"foo": "bar", "baz": "qux"}`,
+		},
+		{
+			"AddArray",
+			`{"foo": ["bar"]}`,
+			`[{"op": "add", "path": "/foo/-", "value": ["abc", "def"]}]`,
+			`{"foo": ["bar", ["abc", "def"]]}`,
+		},
+		{
+			"Escape",
+			`{"/": 9, "~1": 10}`,
+			`[{"op": "test", "path": "/~01", "value": 10}, {"op": "remove", "path": "/~1"}]`,
+			`{"~1": 10}`,
+		},
+		{
+			"TestEqual",
+			`{"a": {"x": 1, "y": [1.0, null]}}`,
+			`[{"op": "test", "path": "/a", "value": {"y": [1, null], "x": 10e-1}}]`,
+			`{"a": {"x": 1, "y": [1.0, null]}}`,
+		},
+		{
+			"Copy",
+			`{"a": {"b": 1}}`,
+			`[{"op": "copy", "from": "/a", "path": "/c"}, {"op": "add", "path": "/c/d", "value": 2}]`,
+			`{"a": {"b": 1}, "c": {"b": 1, "d": 2}}`,
+		},
+		{
+			"Root",
+			`{"a": 1}`,
+			`[{"op": "replace", "path": "", "value": [1]}, {"op": "add", "path": "/0", "value": 0}]`,
+			`[0, 1]`,
+		},
+		{
+			"MoveSame",
+			`{"a": 1}`,
+			`[{"op": "move", "from": "/a", "path": "/a"}]`,
+			`{"a": 1}`,
+		},
+	} {
+		t.Run(tt.Name, func(t *testing.T) {
+			out, err := apply(t, tt.Doc, tt.Patch)
+			require.NoError(t, err)
+			requireJSONEqual(t, tt.Expect, out)
+		})
+	}
+}
+
+func TestPatch_ApplyRaw(t *testing.T) {
+	out, err := apply(t,
+		`{"keep": {"n": 1.50, "s": "A"}, "change": {"a": [1, 2], "b": 3}}`,
+		`[{"op": "replace", "path": "/change/b", "value": 4}]`,
+	)
+	require.NoError(t, err)
+	require.Equal(t, `{"keep":{"n": 1.50, "s": "A"},"change":{"a":[1, 2],"b":4}}`, out)
+}
+
+func TestPatch_ApplyError(t *testing.T) {
+	for _, tt := range []struct {
+		Name  string
+		Doc   string
+		Patch string
+		Index int
+		Path  string
+		Err   error
+	}{
+		{
+			"TestFailed",
+			`{"baz": "qux"}`,
+			`[{"op": "test", "path": "/baz", "value": "bar"}]`,
+			0, "/baz", ErrTestFailed,
+		},
+		{
+			"AddMissingParent",
+			`{"foo": "bar"}`,
+			`[{"op": "add", "path": "/baz", "value": 1}, {"op": "add", "path": "/baz/bat", "value": "qux"}]`,
+			1, "/baz/bat", jx.ErrNotFound,
+		},
+		{
+			"RemoveMissing",
+			`{"foo": "bar"}`,
+			`[{"op": "remove", "path": "/baz"}]`,
+			0, "/baz", jx.ErrNotFound,
+		},
+		{
+			"ReplaceOutOfRange",
+			`[1, 2]`,
+			`[{"op": "replace", "path": "/2", "value": 3}]`,
+			0, "/2", jx.ErrNotFound,
+		},
+		{
+			"MoveMissing",
+			`{"a": 1}`,
+			`[{"op": "move", "from": "/b", "path": "/c"}]`,
+			0, "/b", jx.ErrNotFound,
+		},
+		{
+			"CopyToMissing",
+			`{"a": 1}`,
+			`[{"op": "copy", "from": "/a", "path": "/b/c"}]`,
+			0, "/b/c", jx.ErrNotFound,
+		},
+		{
+			"TestNumber",
+			`{"foo": {"bar": [1, 2, 5, 4]}}`,
+			`[{"op": "test", "path": "/foo", "value": {"bar": [1, 2, 5, 4], "x": 1}}]`,
+			0, "/foo", ErrTestFailed,
+		},
+	} {
+		t.Run(tt.Name, func(t *testing.T) {
+			_, err := apply(t, tt.Doc, tt.Patch)
+			require.ErrorIs(t, err, tt.Err)
+			var pe *Error
+			require.ErrorAs(t, err, &pe)
+			require.Equal(t, tt.Index, pe.Index)
+			require.Equal(t, tt.Path, pe.Path)
+		})
+	}
+	t.Run("Invalid", func(t *testing.T) {
+		for _, patch := range []string{
+			`[{"op": "add", "path": "/0/2", "value": 1}]`,
+			`[{"op": "add", "path": "/01", "value": 1}]`,
+			`[{"op": "add", "path": "/x", "value": 1}]`,
+			`[{"op": "add", "path": "a", "value": 1}]`,
+			`[{"op": "add", "path": "/~2", "value": 1}]`,
+			`[{"op": "remove", "path": ""}]`,
+			`[{"op": "move", "from": "/0", "path": "/0/0"}]`,
+		} {
+			_, err := apply(t, `[[1]]`, patch)
+			var pe *Error
+			require.ErrorAs(t, err, &pe, patch)
+		}
+	})
+	t.Run("Document", func(t *testing.T) {
+		p := Patch{{Op: Add, Path: "/a", Value: jx.Raw(`1`)}}
+		require.Error(t, p.Apply(jx.DecodeStr(`{"a": tru}`), &jx.Encoder{}))
+	})
+	t.Run("Value", func(t *testing.T) {
+		p := Patch{{Op: Add, Path: "/a", Value: jx.Raw(`{`)}}
+		err := p.Apply(jx.DecodeStr(`{}`), &jx.Encoder{})
+		var pe *Error
+		require.ErrorAs(t, err, &pe)
+		require.False(t, errors.Is(err, jx.ErrNotFound))
+	})
+}
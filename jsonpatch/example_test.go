@@ -0,0 +1,39 @@
+package jsonpatch_test
+
+import (
+	"fmt"
+
+	"github.com/go-faster/jx"
+	"github.com/go-faster/jx/jsonpatch"
+)
+
+func ExamplePatch_Apply() {
+	p, err := jsonpatch.Parse(jx.DecodeStr(`[
+  {"op": "replace", "path": "/name", "value": "bar"},
+  {"op": "add", "path": "/tags/-", "value": "new"}
+]`))
+	if err != nil {
+		panic(err)
+	}
+	var e jx.Encoder
+	if err := p.Apply(jx.DecodeStr(`{"name": "foo", "tags": ["a"], "meta": {"id": 1}}`), &e); err != nil {
+		panic(err)
+	}
+	fmt.Println(e)
+	// Output:
+	// {"name":"bar","tags":["a","new"],"meta":{"id": 1}}
+}
+
+func ExampleMergePatch() {
+	var e jx.Encoder
+	if err := jsonpatch.MergePatch(
+		jx.DecodeStr(`{"title": "Goodbye!", "author": {"name": "John", "email": "john@example.com"}, "price": 10.00}`),
+		jx.DecodeStr(`{"title": "Hello!", "author": {"email": null}}`),
+		&e,
+	); err != nil {
+		panic(err)
+	}
+	fmt.Println(e)
+	// Output:
+	// {"title":"Hello!","author":{"name":"John"},"price":10.00}
+}
@@ -0,0 +1,183 @@
+package jsonpatch
+
+import (
+	"github.com/go-faster/errors"
+
+	"github.com/go-faster/jx"
+)
+
+// MergePatch reads target document and RFC 7386 merge patch, writing merged
+// document to e.
+//
+// Fields of target keep their order, new fields are appended in order of
+// patch. Values that are not changed by patch, including numbers, are
+// copied as is.
+func MergePatch(target, patch *jx.Decoder, e *jx.Encoder) error {
+	t, err := target.Raw()
+	if err != nil {
+		return errors.Wrap(err, "read target")
+	}
+	p, err := patch.Raw()
+	if err != nil {
+		return errors.Wrap(err, "read patch")
+	}
+	return merge(t, p, e)
+}
+
+type rawField struct {
+	key string
+	val jx.Raw
+}
+
+// rawFields returns fields of raw object.
+func rawFields(raw jx.Raw) ([]rawField, error) {
+	var fields []rawField
+	if err := jx.DecodeBytes(raw).ObjBytes(func(d *jx.Decoder, key []byte) error {
+		v, err := d.Raw()
+		if err != nil {
+			return err
+		}
+		fields = append(fields, rawField{key: string(key), val: v})
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// merge writes patch merged to target, which is nil if target value does not
+// exist.
+func merge(target, patch jx.Raw, e *jx.Encoder) error {
+	if patch.Type() != jx.Object {
+		e.Raw(patch)
+		return nil
+	}
+	pf, err := rawFields(patch)
+	if err != nil {
+		return errors.Wrap(err, "patch")
+	}
+	var tf []rawField
+	if target.Type() == jx.Object {
+		if tf, err = rawFields(target); err != nil {
+			return errors.Wrap(err, "target")
+		}
+	}
+
+	// The last repeated key of patch wins.
+	last := make(map[string]int, len(pf))
+	for i, f := range pf {
+		last[f.key] = i
+	}
+	e.ObjStart()
+	inTarget := make(map[string]struct{}, len(tf))
+	for _, f := range tf {
+		inTarget[f.key] = struct{}{}
+		i, ok := last[f.key]
+		if !ok {
+			e.FieldStart(f.key)
+			e.Raw(f.val)
+			continue
+		}
+		if pf[i].val.Type() == jx.Null {
+			continue
+		}
+		e.FieldStart(f.key)
+		if err := merge(f.val, pf[i].val, e); err != nil {
+			return errors.Wrapf(err, "%q", f.key)
+		}
+	}
+	for i, f := range pf {
+		if _, ok := inTarget[f.key]; ok || last[f.key] != i || f.val.Type() == jx.Null {
+			continue
+		}
+		e.FieldStart(f.key)
+		if err := merge(nil, f.val, e); err != nil {
+			return errors.Wrapf(err, "%q", f.key)
+		}
+	}
+	e.ObjEnd()
+	return nil
+}
+
+// CreateMergePatch reads original and modified documents, writing RFC 7386
+// merge patch that transforms original to modified to e.
+//
+// Values are compared exactly, so numbers with different text, like 1 and
+// 1.0, are different. Merge patch can not set value to null, so null in
+// modified object removes the field instead.
+func CreateMergePatch(original, modified *jx.Decoder, e *jx.Encoder) error {
+	a, err := original.Any()
+	if err != nil {
+		return errors.Wrap(err, "read original")
+	}
+	b, err := modified.Any()
+	if err != nil {
+		return errors.Wrap(err, "read modified")
+	}
+	createMerge(a, b, e)
+	return nil
+}
+
+func createMerge(a, b jx.Any, e *jx.Encoder) {
+	if a.Type != jx.AnyObj || b.Type != jx.AnyObj {
+		b.KeyValid = false
+		b.Write(e)
+		return
+	}
+	e.ObjStart()
+	for i, f := range a.Child {
+		if a.Field(f.Key) != &a.Child[i] {
+			// Repeated key.
+			continue
+		}
+		m := b.Field(f.Key)
+		switch {
+		case m == nil:
+			e.FieldStart(f.Key)
+			e.Null()
+		case !same(f, *m):
+			e.FieldStart(f.Key)
+			createMerge(f, *m, e)
+		}
+	}
+	for i, f := range b.Child {
+		if a.Field(f.Key) == nil && b.Field(f.Key) == &b.Child[i] {
+			f.Write(e)
+		}
+	}
+	e.ObjEnd()
+}
+
+// same reports whether values are equal, comparing numbers by text.
+func same(a, b jx.Any) bool {
+	if a.Type != b.Type || len(a.Child) != len(b.Child) {
+		return false
+	}
+	switch a.Type {
+	case jx.AnyNumber:
+		return a.Number.Equal(b.Number)
+	case jx.AnyStr:
+		return a.Str == b.Str
+	case jx.AnyBool:
+		return a.Bool == b.Bool
+	case jx.AnyArr:
+		for i := range a.Child {
+			if !same(a.Child[i], b.Child[i]) {
+				return false
+			}
+		}
+	case jx.AnyObj:
+		for _, c := range a.Child {
+			m := b.Field(c.Key)
+			if m == nil || !same(c, *m) {
+				return false
+			}
+		}
+		for _, c := range b.Child {
+			if a.Field(c.Key) == nil {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,92 @@
+package jsonpatch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-faster/jx"
+)
+
+func mergePatch(t *testing.T, target, patch string) string {
+	t.Helper()
+	var e jx.Encoder
+	require.NoError(t, MergePatch(jx.DecodeStr(target), jx.DecodeStr(patch), &e))
+	return e.String()
+}
+
+func TestMergePatch(t *testing.T) {
+	// Examples from RFC 7386, Appendix A.
+	for _, tt := range []struct {
+		Target string
+		Patch  string
+		Expect string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+		// Order of target and number text are kept.
+		{`{"z":1.50,"a":{"y":1e3,"x":2}}`, `{"a":{"x":3.0,"w":4},"b":0.10}`, `{"z":1.50,"a":{"y":1e3,"x":3.0,"w":4},"b":0.10}`},
+		// The last repeated key wins.
+		{`{"a":1}`, `{"a":2,"a":null,"b":1,"b":2}`, `{"b":2}`},
+	} {
+		require.Equal(t, tt.Expect, mergePatch(t, tt.Target, tt.Patch), "%s + %s", tt.Target, tt.Patch)
+	}
+	t.Run("Error", func(t *testing.T) {
+		var e jx.Encoder
+		require.Error(t, MergePatch(jx.DecodeStr(`{"a":`), jx.DecodeStr(`{}`), &e))
+		require.Error(t, MergePatch(jx.DecodeStr(`{}`), jx.DecodeStr(`{"a"}`), &e))
+	})
+}
+
+func TestCreateMergePatch(t *testing.T) {
+	for _, tt := range []struct {
+		Original string
+		Modified string
+		Expect   string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"a":"b","b":"c"}`, `{"b":"c"}`},
+		{`{"a":"b","b":"c"}`, `{"b":"c"}`, `{"a":null}`},
+		{`{"a":{"b":"c","d":1}}`, `{"a":{"b":"d","d":1}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[1,2]}`, `{"a":[1,3]}`, `{"a":[1,3]}`},
+		{`{"a":{"b":1}}`, `{"a":1}`, `{"a":1}`},
+		{`{"x":1,"y":{"z":[1]}}`, `{"y":{"z":[1]},"x":1}`, `{}`},
+		{`{"a":1}`, `{"a":1.0}`, `{"a":1.0}`},
+		{`[1]`, `{"a":1}`, `{"a":1}`},
+		{`{"a":1}`, `[1]`, `[1]`},
+		{`{"a":1,"a":2}`, `{"b":1,"b":2}`, `{"a":null,"b":1}`},
+	} {
+		var e jx.Encoder
+		require.NoError(t, CreateMergePatch(jx.DecodeStr(tt.Original), jx.DecodeStr(tt.Modified), &e))
+		require.Equal(t, tt.Expect, e.String(), "%s -> %s", tt.Original, tt.Modified)
+	}
+	t.Run("RoundTrip", func(t *testing.T) {
+		for _, tt := range [][2]string{
+			{`{"title":"Goodbye!","author":{"givenName":"John","familyName":"Doe"},"tags":["example","sample"],"content":"This will be unchanged"}`,
+				`{"title":"Hello!","author":{"givenName":"John"},"tags":["example"],"content":"This will be unchanged","phoneNumber":"+01-123-456-7890"}`},
+			{`{"n":1.50,"m":{"a":[true]}}`, `{"n":1.5,"m":{"a":[true],"b":2e10}}`},
+		} {
+			var e jx.Encoder
+			require.NoError(t, CreateMergePatch(jx.DecodeStr(tt[0]), jx.DecodeStr(tt[1]), &e))
+			require.Equal(t, tt[1], mergePatch(t, tt[0], e.String()))
+		}
+	})
+	t.Run("Error", func(t *testing.T) {
+		var e jx.Encoder
+		require.Error(t, CreateMergePatch(jx.DecodeStr(`{"a":`), jx.DecodeStr(`{}`), &e))
+		require.Error(t, CreateMergePatch(jx.DecodeStr(`{}`), jx.DecodeStr(`tru`), &e))
+	})
+}
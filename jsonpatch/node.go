@@ -0,0 +1,171 @@
+package jsonpatch
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/go-faster/errors"
+
+	"github.com/go-faster/jx"
+)
+
+// node is json value of patched document.
+//
+// Values are kept as raw spans of input until they are changed, so only
+// containers on paths of operations are decoded.
+type node struct {
+	raw    jx.Raw // if kind is kindRaw
+	kind   nodeKind
+	fields []field // kindObj
+	elems  []*node // kindArr
+}
+
+type nodeKind byte
+
+const (
+	kindRaw nodeKind = iota
+	kindObj
+	kindArr
+)
+
+type field struct {
+	key string
+	val *node
+}
+
+// expand decodes raw object or array to fields or elements.
+//
+// Scalar values are kept raw.
+func (n *node) expand() error {
+	if n.kind != kindRaw {
+		return nil
+	}
+	d := jx.DecodeBytes(n.raw)
+	switch d.Next() {
+	case jx.Object:
+		var fields []field
+		if err := d.ObjBytes(func(d *jx.Decoder, key []byte) error {
+			raw, err := d.Raw()
+			if err != nil {
+				return err
+			}
+			fields = append(fields, field{key: string(key), val: &node{raw: raw}})
+			return nil
+		}); err != nil {
+			return err
+		}
+		n.kind, n.fields, n.raw = kindObj, fields, nil
+	case jx.Array:
+		var elems []*node
+		if err := d.Arr(func(d *jx.Decoder) error {
+			raw, err := d.Raw()
+			if err != nil {
+				return err
+			}
+			elems = append(elems, &node{raw: raw})
+			return nil
+		}); err != nil {
+			return err
+		}
+		n.kind, n.elems, n.raw = kindArr, elems, nil
+	}
+	return nil
+}
+
+// field returns index of the first field with given key, or -1.
+func (n *node) field(key string) int {
+	for i, f := range n.fields {
+		if f.key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// clone returns deep copy of n.
+func (n *node) clone() *node {
+	c := &node{raw: n.raw, kind: n.kind}
+	if n.fields != nil {
+		c.fields = make([]field, len(n.fields))
+		for i, f := range n.fields {
+			c.fields[i] = field{key: f.key, val: f.val.clone()}
+		}
+	}
+	if n.elems != nil {
+		c.elems = make([]*node, len(n.elems))
+		for i, e := range n.elems {
+			c.elems[i] = e.clone()
+		}
+	}
+	return c
+}
+
+// write writes n to e, copying raw values as is.
+func (n *node) write(e *jx.Encoder) {
+	switch n.kind {
+	case kindObj:
+		e.ObjStart()
+		for _, f := range n.fields {
+			e.FieldStart(f.key)
+			f.val.write(e)
+		}
+		e.ObjEnd()
+	case kindArr:
+		e.ArrStart()
+		for _, v := range n.elems {
+			v.write(e)
+		}
+		e.ArrEnd()
+	default:
+		e.Raw(n.raw)
+	}
+}
+
+// any returns n as jx.Any.
+func (n *node) any() (jx.Any, error) {
+	var e jx.Encoder
+	n.write(&e)
+	return jx.DecodeBytes(e.Bytes()).Any()
+}
+
+// parsePointer splits JSON Pointer to unescaped reference tokens.
+func parsePointer(ptr string) ([]string, error) {
+	if ptr == "" {
+		return nil, nil
+	}
+	if ptr[0] != '/' {
+		return nil, errors.Errorf("invalid pointer: must start with %q", "/")
+	}
+	toks := strings.Split(ptr[1:], "/")
+	for i, tok := range toks {
+		if strings.IndexByte(tok, '~') < 0 {
+			continue
+		}
+		for j := 0; j < len(tok); j++ {
+			if tok[j] == '~' && (j+1 == len(tok) || tok[j+1] != '0' && tok[j+1] != '1') {
+				return nil, errors.New("invalid pointer: bad escape")
+			}
+		}
+		toks[i] = pointerUnescaper.Replace(tok)
+	}
+	return toks, nil
+}
+
+var pointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+
+// index parses array index from reference token.
+//
+// Token "-" references element after the last one, if allowed.
+func index(tok string, length int, end bool) (int, error) {
+	if tok == "-" && end {
+		return length, nil
+	}
+	if tok == "" || len(tok) > 1 && tok[0] == '0' || strings.TrimLeft(tok, "0123456789") != "" {
+		return 0, errors.Errorf("invalid index %q", tok)
+	}
+	i, err := strconv.Atoi(tok)
+	if err != nil || i > length || i == length && !end {
+		return 0, errors.Wrapf(jx.ErrNotFound, "index %s out of range", tok)
+	}
+	return i, nil
+}
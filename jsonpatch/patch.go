@@ -0,0 +1,126 @@
+// Package jsonpatch implements RFC 6902 JSON Patch and RFC 7386 JSON Merge
+// Patch.
+package jsonpatch
+
+import (
+	"fmt"
+
+	"github.com/go-faster/errors"
+
+	"github.com/go-faster/jx"
+)
+
+// Op is operation of JSON Patch.
+type Op string
+
+// Possible operations.
+const (
+	Add     Op = "add"
+	Remove  Op = "remove"
+	Replace Op = "replace"
+	Move    Op = "move"
+	Copy    Op = "copy"
+	Test    Op = "test"
+)
+
+// Operation is single operation of JSON Patch.
+type Operation struct {
+	Op    Op
+	Path  string // JSON Pointer of target location
+	From  string // JSON Pointer of source location, for Move and Copy
+	Value jx.Raw // for Add, Replace and Test
+}
+
+// Patch is JSON Patch document, a list of operations.
+type Patch []Operation
+
+// ErrTestFailed is returned if value does not match Test operation.
+var ErrTestFailed = errors.New("test failed")
+
+// Error is error of applying operation of patch.
+type Error struct {
+	Index int    // index of operation in patch
+	Op    Op     // operation
+	Path  string // failed JSON Pointer, "path" or "from" of operation
+	Err   error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("operation %d: %s %q: %s", e.Index, e.Op, e.Path, e.Err)
+}
+
+// Unwrap returns underlying error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Parse reads JSON Patch document.
+//
+// Values of operations are copied, so d can be reused.
+func Parse(d *jx.Decoder) (Patch, error) {
+	var p Patch
+	if err := d.Arr(func(d *jx.Decoder) error {
+		var (
+			op      Operation
+			hasPath bool
+			hasFrom bool
+		)
+		if err := d.ObjBytes(func(d *jx.Decoder, key []byte) error {
+			var err error
+			switch string(key) {
+			case "op":
+				var s string
+				s, err = d.Str()
+				op.Op = Op(s)
+			case "path":
+				op.Path, err = d.Str()
+				hasPath = true
+			case "from":
+				op.From, err = d.Str()
+				hasFrom = true
+			case "value":
+				op.Value, err = d.RawAppend(nil)
+			default:
+				// Unknown members are ignored.
+				err = d.Skip()
+			}
+			if err != nil {
+				return errors.Wrapf(err, "%q", key)
+			}
+			return nil
+		}); err != nil {
+			return errors.Wrapf(err, "operation %d", len(p))
+		}
+		if err := op.validate(hasPath, hasFrom); err != nil {
+			return errors.Wrapf(err, "operation %d", len(p))
+		}
+		p = append(p, op)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// validate checks that operation has required members.
+func (op Operation) validate(hasPath, hasFrom bool) error {
+	switch op.Op {
+	case Add, Replace, Test:
+		if op.Value == nil {
+			return errors.Errorf("%s: missing %q", op.Op, "value")
+		}
+	case Move, Copy:
+		if !hasFrom {
+			return errors.Errorf("%s: missing %q", op.Op, "from")
+		}
+	case Remove:
+	case "":
+		return errors.Errorf("missing %q", "op")
+	default:
+		return errors.Errorf("unknown op %q", op.Op)
+	}
+	if !hasPath {
+		return errors.Errorf("%s: missing %q", op.Op, "path")
+	}
+	return nil
+}
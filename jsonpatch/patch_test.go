@@ -0,0 +1,44 @@
+package jsonpatch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-faster/jx"
+)
+
+func TestParse(t *testing.T) {
+	p, err := Parse(jx.DecodeStr(`[
+  {"op": "test", "path": "/a/b/c", "value": "foo"},
+  {"op": "remove", "path": "/a/b/c"},
+  {"op": "add", "path": "/a/b/c", "value": ["foo", "bar"]},
+  {"op": "replace", "path": "/a/b/c", "value": 42},
+  {"op": "move", "from": "/a/b/c", "path": "/a/b/d"},
+  {"op": "copy", "from": "/a/b/d", "path": "/a/b/e"}
+]`))
+	require.NoError(t, err)
+	require.Equal(t, Patch{
+		{Op: Test, Path: "/a/b/c", Value: jx.Raw(`"foo"`)},
+		{Op: Remove, Path: "/a/b/c"},
+		{Op: Add, Path: "/a/b/c", Value: jx.Raw(`["foo", "bar"]`)},
+		{Op: Replace, Path: "/a/b/c", Value: jx.Raw(`42`)},
+		{Op: Move, Path: "/a/b/d", From: "/a/b/c"},
+		{Op: Copy, Path: "/a/b/e", From: "/a/b/d"},
+	}, p)
+
+	for _, input := range []string{
+		`{}`,
+		`[1]`,
+		`[{"path": "/a"}]`,
+		`[{"op": "foo", "path": "/a"}]`,
+		`[{"op": "add", "path": "/a"}]`,
+		`[{"op": "add", "value": 1}]`,
+		`[{"op": "move", "path": "/a"}]`,
+		`[{"op": 1, "path": "/a"}]`,
+		`[{"op": "remove", "path": "/a"}, {"op": "remove"}]`,
+	} {
+		_, err := Parse(jx.DecodeStr(input))
+		require.Error(t, err, input)
+	}
+}
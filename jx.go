@@ -39,6 +39,10 @@ func GetDecoder() *Decoder {
 // PutDecoder puts *Decoder into pool.
 func PutDecoder(d *Decoder) {
 	d.Reset(nil)
+	d.limits = DecoderLimits{}
+	d.ext = 0
+	d.utf8 = UTF8PassThrough
+	d.dup = DuplicateKeysAllow
 	decPool.Put(d)
 }
 
@@ -51,6 +55,7 @@ func GetEncoder() *Encoder {
 func PutEncoder(e *Encoder) {
 	e.Reset()
 	e.SetIdent(0)
+	e.SetCheck(EncoderCheckNone)
 	encPool.Put(e)
 }
 
@@ -0,0 +1,126 @@
+package jx
+
+import (
+	"strings"
+
+	"github.com/go-faster/errors"
+)
+
+// splitPointer returns unescaped first reference token of RFC 6901 JSON
+// Pointer p and the rest of pointer.
+//
+// Token is sub-string of p, unless it has escapes.
+func splitPointer(p string) (tok, rest string, err error) {
+	if p == "" || p[0] != '/' {
+		return "", "", errors.Errorf("invalid pointer %q: must start with %q", p, "/")
+	}
+	tok = p[1:]
+	if i := strings.IndexByte(tok, '/'); i >= 0 {
+		tok, rest = tok[:i], tok[i:]
+	}
+	if strings.IndexByte(tok, '~') < 0 {
+		return tok, rest, nil
+	}
+	var b strings.Builder
+	for i := 0; i < len(tok); i++ {
+		c := tok[i]
+		if c != '~' {
+			b.WriteByte(c)
+			continue
+		}
+		i++
+		switch {
+		case i < len(tok) && tok[i] == '0':
+			b.WriteByte('~')
+		case i < len(tok) && tok[i] == '1':
+			b.WriteByte('/')
+		default:
+			return "", "", errors.Errorf("invalid pointer %q: bad escape", p)
+		}
+	}
+	return b.String(), rest, nil
+}
+
+// pointerIndex returns array index from reference token, or -1 if token is
+// not an index.
+//
+// Leading zeroes are not allowed, "-" is not an index.
+func pointerIndex(tok string) int {
+	if tok == "" || len(tok) > 1 && tok[0] == '0' || len(tok) > 18 {
+		return -1
+	}
+	n := 0
+	for i := 0; i < len(tok); i++ {
+		c := tok[i]
+		if c < '0' || c > '9' {
+			return -1
+		}
+		n = n*10 + int(c-'0')
+	}
+	return n
+}
+
+// Pointers is compiled set of RFC 6901 JSON Pointers, see Decoder.Extract.
+type Pointers struct {
+	root pointerNode
+	n    int
+}
+
+// pointerNode is node of prefix tree of reference tokens.
+type pointerNode struct {
+	id     int // index of pointer ending at this node, or -1
+	fields map[string]*pointerNode
+	elems  map[int]*pointerNode
+}
+
+// CompilePointers compiles set of JSON Pointers.
+//
+// Index of pointer in ptrs identifies matched value in Decoder.Extract.
+// Pointers must be unique.
+func CompilePointers(ptrs ...string) (*Pointers, error) {
+	p := &Pointers{
+		root: pointerNode{id: -1},
+		n:    len(ptrs),
+	}
+	for id, ptr := range ptrs {
+		n := &p.root
+		for rest := ptr; rest != ""; {
+			tok, next, err := splitPointer(rest)
+			if err != nil {
+				return nil, err
+			}
+			n = n.child(tok)
+			rest = next
+		}
+		if n.id >= 0 {
+			return nil, errors.Errorf("duplicate pointer %q", ptr)
+		}
+		n.id = id
+	}
+	return p, nil
+}
+
+// Len returns count of pointers in set.
+func (p *Pointers) Len() int {
+	return p.n
+}
+
+// child returns child node for reference token, adding it if needed.
+func (n *pointerNode) child(tok string) *pointerNode {
+	if c, ok := n.fields[tok]; ok {
+		return c
+	}
+	c := &pointerNode{id: -1}
+	if n.fields == nil {
+		n.fields = map[string]*pointerNode{}
+	}
+	n.fields[tok] = c
+	// Token can reference both field and element.
+	if idx := pointerIndex(tok); idx >= 0 {
+		if n.elems == nil {
+			n.elems = map[int]*pointerNode{}
+		}
+		n.elems[idx] = c
+	}
+	return c
+}
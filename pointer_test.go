@@ -0,0 +1,49 @@
+package jx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompilePointers(t *testing.T) {
+	p, err := CompilePointers("", "/a", "/a/b", "/a/0", "/c/~1~0")
+	require.NoError(t, err)
+	require.Equal(t, 5, p.Len())
+	require.Equal(t, 0, p.root.id)
+
+	a := p.root.fields["a"]
+	require.Equal(t, 1, a.id)
+	require.Equal(t, 2, a.fields["b"].id)
+	require.Equal(t, 3, a.fields["0"].id)
+	require.Same(t, a.fields["0"], a.elems[0])
+	require.Len(t, a.elems, 1)
+	require.Equal(t, 4, p.root.fields["c"].fields["/~"].id)
+
+	for _, ptrs := range [][]string{
+		{"a"},
+		{"/a~2"},
+		{"/a", "/b", "/a"},
+	} {
+		_, err := CompilePointers(ptrs...)
+		require.Error(t, err, ptrs)
+	}
+}
+
+func TestPointerIndex(t *testing.T) {
+	for _, tt := range []struct {
+		Tok    string
+		Expect int
+	}{
+		{"0", 0},
+		{"10", 10},
+		{"", -1},
+		{"-", -1},
+		{"01", -1},
+		{"1a", -1},
+		{"-1", -1},
+		{"1234567890123456789", -1},
+	} {
+		require.Equal(t, tt.Expect, pointerIndex(tt.Tok), tt.Tok)
+	}
+}
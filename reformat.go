@@ -0,0 +1,116 @@
+package jx
+
+import "github.com/go-faster/errors"
+
+// Indent reads json value from d and writes it to e, indented as set by
+// Encoder.SetIdent. If indentation is not set, value is written compact.
+//
+// Keys, strings and numbers are copied as is, so escapes and number text are
+// preserved. Value is read by Token, so memory usage is bounded by the
+// largest token, not by the whole value.
+func Indent(d *Decoder, e *Encoder) error {
+	depth := len(d.tok.stack)
+	tok, raw, err := d.Token()
+	for {
+		if err != nil {
+			return err
+		}
+		var fail bool
+		switch tok {
+		case TokenObjStart, TokenArrStart:
+			next, nextRaw, err := d.Token()
+			if err != nil {
+				return err
+			}
+			switch {
+			case next == TokenObjEnd:
+				fail = e.ObjEmpty()
+			case next == TokenArrEnd:
+				fail = e.ArrEmpty()
+			case tok == TokenObjStart:
+				fail = e.ObjStart()
+			default:
+				fail = e.ArrStart()
+			}
+			if fail {
+				return e.writeErr()
+			}
+			if next != TokenObjEnd && next != TokenArrEnd {
+				// Write the first token of container.
+				tok, raw = next, nextRaw
+				continue
+			}
+		case TokenObjEnd:
+			fail = e.ObjEnd()
+		case TokenArrEnd:
+			fail = e.ArrEnd()
+		case TokenKey:
+			fail = e.fieldStartRaw(raw)
+		default:
+			fail = e.Raw(raw)
+		}
+		if fail {
+			return e.writeErr()
+		}
+		if tok != TokenKey && len(d.tok.stack) == depth {
+			return nil
+		}
+		tok, raw, err = d.Token()
+	}
+}
+
+// RawIndented writes raw json value, re-formatted at current depth as set by
+// Encoder.SetIndent.
+//
+// Unlike Raw, value is validated. If v is not a single valid json value,
+// error is returned and nothing is written.
+func (e *Encoder) RawIndented(v []byte) error {
+	d := GetDecoder()
+	defer PutDecoder(d)
+
+	d.ResetBytes(v)
+	if err := d.Validate(); err != nil {
+		return errors.Wrap(err, "validate")
+	}
+	d.ResetBytes(v)
+	return Indent(d, e)
+}
+
+// Compact reads json value from d and writes it to w without insignificant
+// whitespace.
+//
+// Keys, strings and numbers are copied as is, so escapes and number text are
+// preserved. Value is read by Token, so memory usage is bounded by the
+// largest token, not by the whole value.
+func Compact(d *Decoder, w *Writer) error {
+	var (
+		depth = len(d.tok.stack)
+		comma bool // comma is needed before next key or value
+	)
+	for {
+		tok, raw, err := d.Token()
+		if err != nil {
+			return err
+		}
+		var fail bool
+		switch tok {
+		case TokenObjEnd, TokenArrEnd:
+			fail = w.Raw(raw)
+			comma = true
+		case TokenKey:
+			fail = comma && w.Comma()
+			fail = fail || w.Raw(raw) || w.byte(':')
+			comma = false
+		default:
+			fail = comma && w.Comma()
+			fail = fail || w.Raw(raw)
+			comma = tok != TokenObjStart && tok != TokenArrStart
+		}
+		if fail {
+			return w.writeErr()
+		}
+		if tok != TokenKey && len(d.tok.stack) == depth {
+			return nil
+		}
+	}
+}
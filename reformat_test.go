@@ -0,0 +1,181 @@
+package jx
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndent(t *testing.T) {
+	const input = ` {"a" : [1, 2.50e1, "A\n"], "b":{ }, "c": [ ], "d": {"e": null, "f": [{}]}} `
+	t.Run("Indent", testBufferReader(input+` 1`, func(t *testing.T, d *Decoder) {
+		var e Encoder
+		e.SetIdent(2)
+		require.NoError(t, Indent(d, &e))
+		require.Equal(t, `{
+  "a": [
+    1,
+    2.50e1,
+    "A\n"
+  ],
+  "b": {},
+  "c": [],
+  "d": {
+    "e": null,
+    "f": [
+      {}
+    ]
+  }
+}`, e.String())
+		// Only one value is consumed.
+		v, err := d.Int()
+		require.NoError(t, err)
+		require.Equal(t, 1, v)
+	}))
+	t.Run("NoIndent", testBufferReader(input, func(t *testing.T, d *Decoder) {
+		var e Encoder
+		require.NoError(t, Indent(d, &e))
+		require.Equal(t, `{"a":[1,2.50e1,"A\n"],"b":{},"c":[],"d":{"e":null,"f":[{}]}}`, e.String())
+	}))
+	t.Run("Compact", testBufferReader(input+` 1`, func(t *testing.T, d *Decoder) {
+		var w Writer
+		require.NoError(t, Compact(d, &w))
+		require.Equal(t, `{"a":[1,2.50e1,"A\n"],"b":{},"c":[],"d":{"e":null,"f":[{}]}}`, w.String())
+		v, err := d.Int()
+		require.NoError(t, err)
+		require.Equal(t, 1, v)
+	}))
+	t.Run("Scalar", func(t *testing.T) {
+		var w Writer
+		require.NoError(t, Compact(DecodeStr(` "foo" `), &w))
+		require.Equal(t, `"foo"`, w.String())
+
+		var e Encoder
+		e.SetIdent(2)
+		require.NoError(t, Indent(DecodeStr(` 1e3 `), &e))
+		require.Equal(t, `1e3`, e.String())
+	})
+	t.Run("Nested", func(t *testing.T) {
+		// Value is written as element of encoded array.
+		var e Encoder
+		e.SetIdent(2)
+		e.ArrStart()
+		e.Int(1)
+		require.NoError(t, Indent(DecodeStr(`{"a":[]}`), &e))
+		e.ArrEnd()
+		require.Equal(t, "[\n  1,\n  {\n    \"a\": []\n  }\n]", e.String())
+
+		// Value is read from field.
+		d := DecodeStr(`{"x": {"y" : [1, 2]}, "z": 1}`)
+		var w Writer
+		require.NoError(t, d.ObjBytes(func(d *Decoder, key []byte) error {
+			if string(key) != "x" {
+				return d.Skip()
+			}
+			return Compact(d, &w)
+		}))
+		require.Equal(t, `{"y":[1,2]}`, w.String())
+	})
+	t.Run("Error", func(t *testing.T) {
+		for _, input := range []string{
+			``,
+			`{"a": 1`,
+			`[1, tru]`,
+			`{"a" 1}`,
+		} {
+			var e Encoder
+			require.Error(t, Indent(DecodeStr(input), &e), input)
+			var w Writer
+			require.Error(t, Compact(DecodeStr(input), &w), input)
+		}
+	})
+	t.Run("WriteError", func(t *testing.T) {
+		e := NewStreamingEncoder(&errWriter{err: io.ErrClosedPipe}, minEncoderBufSize)
+		require.ErrorIs(t, Indent(DecodeBytes(benchData), e), io.ErrClosedPipe)
+	})
+	t.Run("Testdata", func(t *testing.T) {
+		runTestdata(t.Fatal, func(name string, data []byte) {
+			t.Run(name, func(t *testing.T) {
+				var expect bytes.Buffer
+				require.NoError(t, json.Indent(&expect, data, "", "  "))
+				// Trailing whitespace is kept by json.Indent.
+				expectIndent := bytes.TrimRight(expect.Bytes(), " \t\r\n")
+
+				var out bytes.Buffer
+				e := NewStreamingEncoder(&out, -1)
+				e.SetIdent(2)
+				require.NoError(t, Indent(Decode(bytes.NewReader(data), 512), e))
+				require.NoError(t, e.Close())
+				require.Equal(t, string(expectIndent), out.String())
+
+				expect.Reset()
+				require.NoError(t, json.Compact(&expect, data))
+				var w Writer
+				require.NoError(t, Compact(DecodeBytes(data), &w))
+				require.Equal(t, expect.String(), w.String())
+			})
+		})
+	})
+}
+
+func TestEncoder_RawIndented(t *testing.T) {
+	const raw = `{"b":[1, 2],"c" : {"d":null}}`
+	encode := func(e *Encoder) error {
+		var err error
+		e.Obj(func(e *Encoder) {
+			e.Field("a", func(e *Encoder) {
+				err = e.RawIndented([]byte(raw))
+			})
+			e.Field("z", func(e *Encoder) { e.Int(1) })
+		})
+		return err
+	}
+	t.Run("Indent", func(t *testing.T) {
+		testEncoderModes(t, func(e *Encoder) {
+			e.SetIdent(2)
+			require.NoError(t, encode(e))
+		}, `{
+  "a": {
+    "b": [
+      1,
+      2
+    ],
+    "c": {
+      "d": null
+    }
+  },
+  "z": 1
+}`)
+	})
+	t.Run("Options", func(t *testing.T) {
+		var e Encoder
+		e.SetIndent(IndentOptions{Indent: "\t", InlineArrays: 10})
+		require.NoError(t, encode(&e))
+		require.Equal(t, "{\n\t\"a\": {\n\t\t\"b\": [1, 2],\n\t\t\"c\": {\n\t\t\t\"d\": null\n\t\t}\n\t},\n\t\"z\": 1\n}", e.String())
+	})
+	t.Run("Compact", func(t *testing.T) {
+		var e Encoder
+		require.NoError(t, encode(&e))
+		require.Equal(t, `{"a":{"b":[1,2],"c":{"d":null}},"z":1}`, e.String())
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		for _, input := range []string{
+			``,
+			` `,
+			`{"a": 1`,
+			`[1, tru]`,
+			`1 2`,
+			`{} x`,
+		} {
+			var e Encoder
+			e.SetIdent(2)
+			e.ArrStart()
+			require.Error(t, e.RawIndented([]byte(input)), input)
+			// Nothing is written.
+			require.Equal(t, "[\n  ", e.String(), input)
+		}
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"go/format"
 	"io"
 	"math"
+	"math/bits"
 	"os"
 	"strconv"
 	"text/template"
@@ -19,6 +20,7 @@ import (
 // IntType represents Go integer type.
 type IntType struct {
 	Name              string
+	Bits              int
 	EncoderIterations int // ceil(log1000 (max value))
 	DecoderIterations int // ceil(log10 (max value))
 }
@@ -33,6 +35,7 @@ func defineIntType(name string, max uint64) IntType {
 	}
 	return IntType{
 		Name:              name,
+		Bits:              bits.Len64(max),
 		EncoderIterations: formattedLen/3 + 1, // Compute maximum pow of 1000 plus remainder.
 		DecoderIterations: decoderIters,       // Compute maximum pow of 10 plus remainder.
 	}
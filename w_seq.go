@@ -0,0 +1,13 @@
+package jx
+
+// SeqStart writes record separator (RS) of RFC 7464 json text sequence.
+func (w *Writer) SeqStart() bool {
+	return w.byte(seqSeparator)
+}
+
+// SeqEnd writes line feed that ends record of json text sequence.
+//
+// In streaming mode, also flushes buffer to writer.
+func (w *Writer) SeqEnd() bool {
+	return w.byte('\n') || w.flush()
+}
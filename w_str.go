@@ -2,6 +2,7 @@ package jx
 
 import (
 	"github.com/go-faster/jx/internal/byteseq"
+	"github.com/go-faster/jx/internal/scan"
 )
 
 const hexChars = "0123456789abcdef"
@@ -42,18 +43,9 @@ func writeStr[S byteseq.Byteseq](w *Writer, v S) (fail bool) {
 	fail = w.byte('"')
 
 	// Fast path, without utf8 and escape support.
-	var (
-		i      = 0
-		length = len(v)
-	)
-	for ; i < length && !fail; i++ {
-		c := v[i]
-		if safeSet[c] != 0 {
-			break
-		}
-	}
+	i := scan.Str(v)
 	fail = fail || writeStreamByteseq(w, v[:i])
-	if i == length {
+	if i == len(v) {
 		return fail || w.byte('"')
 	}
 	return fail || strSlow[S](w, v[i:])
@@ -65,7 +57,7 @@ func strSlow[S byteseq.Byteseq](w *Writer, v S) (fail bool) {
 	for i < len(v) && !fail {
 		b := v[i]
 		if safeSet[b] == 0 {
-			i++
+			i += scan.Str(v[i:])
 			continue
 		}
 		if start < i {
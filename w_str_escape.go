@@ -4,6 +4,7 @@ import (
 	"unicode/utf8"
 
 	"github.com/go-faster/jx/internal/byteseq"
+	"github.com/go-faster/jx/internal/scan"
 )
 
 // htmlSafeSet holds the value true if the ASCII character with the given
@@ -126,21 +127,12 @@ func strEscape[S byteseq.Byteseq](w *Writer, v S) (fail bool) {
 	fail = w.byte('"')
 
 	// Fast path, probably does not require escaping.
-	var (
-		i      = 0
-		length = len(v)
-	)
-	for ; i < length && !fail; i++ {
-		c := v[i]
-		if c >= utf8.RuneSelf || !(htmlSafeSet[c]) {
-			break
-		}
-	}
+	i := scan.StrEscape(v)
 	fail = fail || writeStreamByteseq(w, v[:i])
-	if i == length {
+	if i == len(v) {
 		return fail || w.byte('"')
 	}
-	return fail || strEscapeSlow[S](w, i, v, length)
+	return fail || strEscapeSlow[S](w, i, v, len(v))
 }
 
 func strEscapeSlow[S byteseq.Byteseq](w *Writer, i int, v S, valLen int) (fail bool) {
@@ -149,7 +141,7 @@ func strEscapeSlow[S byteseq.Byteseq](w *Writer, i int, v S, valLen int) (fail b
 	for i < valLen && !fail {
 		if b := v[i]; b < utf8.RuneSelf {
 			if htmlSafeSet[b] {
-				i++
+				i += scan.StrEscape(v[i:])
 				continue
 			}
 			if start < i {
@@ -20,11 +20,28 @@ func (w *Writer) Close() error {
 	return nil
 }
 
+// writeErr returns error of failed write in streaming mode.
+func (w *Writer) writeErr() error {
+	if w.stream == nil || w.stream.writeErr == nil {
+		return errors.New("write failed")
+	}
+	return w.stream.writeErr
+}
+
+// flushes returns count of flushes of streaming writer.
+func (w *Writer) flushes() int {
+	if w.stream == nil {
+		return 0
+	}
+	return w.stream.flushes
+}
+
 var errStreaming = errors.New("unexpected call in streaming mode")
 
 type streamState struct {
 	writer   io.Writer
 	writeErr error
+	flushes  int // count of successful flushes
 }
 
 func newStreamState(w io.Writer) *streamState {
@@ -66,6 +83,7 @@ func (s *streamState) flush(buf []byte) ([]byte, bool) {
 		s.setError(io.ErrShortWrite)
 		return nil, true
 	default:
+		s.flushes++
 		buf = buf[:0]
 		return buf, false
 	}
@@ -102,3 +120,16 @@ func writeStreamByteseqSlow[S byteseq.Byteseq](w *Writer, s S) bool {
 	w.Buf = append(w.Buf, s...)
 	return false
 }
+
+// flush writes buffer to writer in streaming mode.
+func (w *Writer) flush() bool {
+	if w.stream == nil {
+		return false
+	}
+	buf, fail := w.stream.flush(w.Buf)
+	if fail {
+		return true
+	}
+	w.Buf = buf
+	return false
+}